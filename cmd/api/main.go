@@ -20,35 +20,52 @@ package main
 // @name Authorization
 // @description 輸入 "Bearer {token}" 來進行認證
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description 個人 API 金鑰（也可使用 Authorization: ApiKey {key}）
+
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	_ "github.com/dinosaur1258/GolangFramework/docs"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
 	"github.com/dinosaur1258/GolangFramework/internal/handler"
+	"github.com/dinosaur1258/GolangFramework/internal/middleware"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/memory"
 	"github.com/dinosaur1258/GolangFramework/internal/repository/postgres"
 	"github.com/dinosaur1258/GolangFramework/internal/router"
 	"github.com/dinosaur1258/GolangFramework/internal/service"
 	"github.com/dinosaur1258/GolangFramework/internal/usecase"
+	"github.com/dinosaur1258/GolangFramework/internal/worker"
 	"github.com/dinosaur1258/GolangFramework/pkg/config"
 	"github.com/dinosaur1258/GolangFramework/pkg/database"
 	"github.com/dinosaur1258/GolangFramework/pkg/logger"
+	"github.com/dinosaur1258/GolangFramework/pkg/mailer"
+	"github.com/dinosaur1258/GolangFramework/pkg/ratelimit"
+	"github.com/dinosaur1258/GolangFramework/pkg/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 func main() {
-	// 根據環境選擇配置檔案
-	configPath := "config/config.yaml"
-	if os.Getenv("DOCKER_ENV") == "true" {
-		configPath = "config/config.docker.yaml"
+	// 載入配置：預設值 → YAML（-config 或 APP_CONFIG_FILE）→ APP_* 環境變數 → 命令列參數
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
 	}
-
-	// 載入配置
-	cfg, err := config.Load(configPath)
 	if err != nil {
-		log.Fatal("Failed to load config:", err)
+		log.Fatal("Failed to load config: ", err)
 	}
+	gin.SetMode(cfg.Server.Mode)
 
 	// 初始化日誌系統
 	env := "development" // 或從 cfg.Server.Mode 讀取
@@ -58,6 +75,10 @@ func main() {
 	defer logger.Sync()
 
 	logger.Info("🚀 Application starting", zap.String("env", env))
+	if len(cfg.GeneratedSecrets) > 0 {
+		logger.Warn("Using random secrets for this process only, tokens and links will be invalid after restart",
+			zap.Strings("secrets", cfg.GeneratedSecrets))
+	}
 
 	// 建立資料庫連線
 	dbConfig := database.Config{
@@ -77,23 +98,156 @@ func main() {
 
 	logger.Info("✅ Database connected successfully")
 
-	// 初始化 Services
-	jwtService := service.NewJWTService(cfg.JWT.Secret, cfg.JWT.ExpireHours)
-
 	// 依賴注入：Repository -> UseCase -> Handler
 	userRepo := postgres.NewUserRepository(db)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
+	roleRepo := postgres.NewRoleRepository(db)
+	passwordResetTokenRepo := postgres.NewPasswordResetTokenRepository(db)
+	mfaRepo := postgres.NewMFARepository(db)
+	userIdentityRepo := postgres.NewUserIdentityRepository(db)
+	apiKeyRepo := postgres.NewAPIKeyRepository(db)
+	sessionRepo := postgres.NewSessionRepository(db)
+	auditEventRepo := postgres.NewAuditEventRepository(db)
+	passwordHistoryRepo := postgres.NewPasswordHistoryRepository(db)
+
+	// Token 撤銷清單
+	var revokedTokenStore contract.RevokedTokenStore
+	if cfg.JWT.RevocationStore == "memory" {
+		revokedTokenStore = memory.NewRevokedTokenStore()
+	} else {
+		revokedTokenStore = postgres.NewRevokedTokenStore(db)
+	}
+
+	// 初始化 Services
+	jwtService, err := newJWTService(cfg.JWT)
+	if err != nil {
+		log.Fatal("Failed to initialize JWT service:", err)
+	}
+	tokenRevocation := service.NewTokenRevocationService(revokedTokenStore, userRepo)
+	authorization := service.NewAuthorizationService(roleRepo, time.Minute)
+	actionTokens := service.NewActionTokenService(cfg.Auth.ActionTokenSecret)
+	mfaService := service.NewMFAService(mfaRepo, cfg.Auth.MFA.Issuer)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, roleRepo)
+	sessionService := service.NewSessionService(sessionRepo)
+	passwordService, err := newPasswordService(cfg.Auth.PasswordPolicy, cfg.Auth.PasswordHashing, passwordHistoryRepo)
+	if err != nil {
+		log.Fatal("Failed to initialize password policy:", err)
+	}
+	dataExports := service.NewDataExportService()
+	dataExports.Register("profile", userRepo)
+	dataExports.Register("roles", roleRepo)
+	dataExports.Register("sessions", sessionRepo)
+	dataExports.Register("api_keys", apiKeyRepo)
+	dataExports.Register("identities", userIdentityRepo)
+	dataExports.Register("mfa", mfaRepo)
+	dataExports.Register("audit_events", auditEventRepo)
+	blobStore, media, err := newBlobStore(cfg.Storage)
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+	avatarService := service.NewAvatarService(blobStore, cfg.Avatar.Sizes, int64(cfg.Avatar.MaxUploadKB)<<10,
+		time.Duration(cfg.Storage.SignedURLExpireMinutes)*time.Minute)
+	oidcStateTTL := time.Duration(cfg.OIDC.StateExpireMinutes) * time.Minute
+	oidcService := service.NewOIDCService(oidcProviders(cfg.OIDC), cfg.Auth.ActionTokenSecret, oidcStateTTL)
+
+	// 限流計數（使用 Redis 時多個實例共用）
+	redisClient, err := newRedisClient(cfg.RateLimit)
+	if err != nil {
+		log.Fatal("Failed to initialize rate limit store:", err)
+	}
+	if redisClient != nil {
+		defer redisClient.Close()
+	}
+	rateLimitStore := newRateLimitStore(cfg.RateLimit, redisClient)
+	rateLimiter, err := newRateLimiter(cfg.RateLimit, rateLimitStore)
+	if err != nil {
+		log.Fatal("Failed to initialize rate limits:", err)
+	}
+	magicLinkLimiter, err := newMagicLinkLimiter(cfg.Auth.MagicLink, rateLimitStore)
+	if err != nil {
+		log.Fatal("Failed to initialize magic link rate limit:", err)
+	}
+
+	// 郵件寄送
+	var mail mailer.Mailer
+	if cfg.Mail.Driver == "smtp" {
+		mail = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.Mail.SMTP.Host,
+			Port:     cfg.Mail.SMTP.Port,
+			Username: cfg.Mail.SMTP.Username,
+			Password: cfg.Mail.SMTP.Password,
+			From:     cfg.Mail.From,
+		})
+	} else {
+		mail = mailer.NewLogMailer(logger.Log)
+	}
 
 	// 建立 UseCase
 	// ⭐ 修改:傳入 db 參數
-	authUseCase := usecase.NewAuthUseCase(userRepo, db) // ← 加入 db
-	userUseCase := usecase.NewUserUseCase(userRepo)
+	authUseCase := usecase.NewAuthUseCase(userRepo, refreshTokenRepo, roleRepo, sessionRepo, jwtService, tokenRevocation, passwordService, mfaService, actionTokens, db, usecase.AuthConfig{
+		RefreshTTL:               time.Duration(cfg.JWT.RefreshExpireHours) * time.Hour,
+		RequireEmailVerification: cfg.Auth.RequireEmailVerification,
+		LockoutThreshold:         int32(cfg.Auth.Lockout.Threshold),
+		LockoutBaseDuration:      time.Duration(cfg.Auth.Lockout.BaseDurationSeconds) * time.Second,
+		LockoutMaxDuration:       time.Duration(cfg.Auth.Lockout.MaxDurationMinutes) * time.Minute,
+		MFAPendingTTL:            time.Duration(cfg.Auth.MFA.PendingTokenExpireMinutes) * time.Minute,
+		ImpersonationTTL:         time.Duration(cfg.Auth.ImpersonationExpireMinutes) * time.Minute,
+	}) // ← 加入 db
+	userUseCase := usecase.NewUserUseCase(userRepo, refreshTokenRepo, sessionRepo, roleRepo, passwordService, db,
+		time.Duration(cfg.Auth.AccountDeletion.RestoreWindowDays)*24*time.Hour, usecase.UsernameChangePolicy{
+			Cooldown:    time.Duration(cfg.Auth.UsernameChange.CooldownDays) * 24 * time.Hour,
+			Reservation: time.Duration(cfg.Auth.UsernameChange.ReservationDays) * 24 * time.Hour,
+		}, avatarService)
+	emailVerificationUseCase := usecase.NewEmailVerificationUseCase(userRepo, actionTokens, mail,
+		time.Duration(cfg.Auth.EmailVerificationExpireHours)*time.Hour, cfg.Auth.FrontendURL)
+	passwordResetUseCase := usecase.NewPasswordResetUseCase(userRepo, passwordResetTokenRepo, refreshTokenRepo, sessionRepo, passwordService, mail, db,
+		time.Duration(cfg.Auth.PasswordResetExpireMinutes)*time.Minute, cfg.Auth.FrontendURL)
+	mfaUseCase := usecase.NewMFAUseCase(userRepo, mfaRepo, mfaService, passwordService)
+	magicLinkUseCase := usecase.NewMagicLinkUseCase(authUseCase, userRepo, actionTokens, tokenRevocation, mail, magicLinkLimiter,
+		time.Duration(cfg.Auth.MagicLink.ExpireMinutes)*time.Minute, cfg.Auth.FrontendURL)
+	emailChangeUseCase := usecase.NewEmailChangeUseCase(authUseCase, userRepo, actionTokens, tokenRevocation, mail,
+		time.Duration(cfg.Auth.EmailChangeExpireHours)*time.Hour, cfg.Auth.FrontendURL)
+	oidcUseCase := usecase.NewOIDCUseCase(authUseCase, userRepo, userIdentityRepo, roleRepo, oidcService)
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo, roleRepo, apiKeyService, authorization)
+	sessionUseCase := usecase.NewSessionUseCase(sessionRepo, refreshTokenRepo)
+	dataExportUseCase := usecase.NewDataExportUseCase(userRepo, dataExports)
 
 	// 建立 Handler
-	authHandler := handler.NewAuthHandler(authUseCase, jwtService)
-	userHandler := handler.NewUserHandler(userUseCase)
+	authHandler := handler.NewAuthHandler(authUseCase, emailVerificationUseCase, passwordResetUseCase, magicLinkUseCase)
+	mfaHandler := handler.NewMFAHandler(mfaUseCase)
+	oidcHandler := handler.NewOIDCHandler(oidcUseCase, int(oidcStateTTL.Seconds()))
+	userHandler := handler.NewUserHandler(userUseCase, dataExportUseCase, emailChangeUseCase, avatarService.MaxBytes())
+	adminHandler := handler.NewAdminHandler(userUseCase, authUseCase)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUseCase)
+	sessionHandler := handler.NewSessionHandler(sessionUseCase)
+	jwksHandler := handler.NewJWKSHandler(jwtService)
 
 	// 設定路由
-	r := router.SetupRouter(userHandler, authHandler, jwtService)
+	r := router.SetupRouter(userHandler, authHandler, mfaHandler, oidcHandler, adminHandler, apiKeyHandler, sessionHandler, jwksHandler, media, jwtService, tokenRevocation, authorization, apiKeyService, sessionService, auditEventRepo, rateLimiter)
+
+	// 背景工作
+	worker.Start(context.Background(), logger.Log, worker.Job{
+		Name:     "session-cleanup",
+		Interval: time.Duration(cfg.Auth.SessionCleanupIntervalMinutes) * time.Minute,
+		Run: func(ctx context.Context) error {
+			deleted, err := sessionService.DeleteExpired(ctx)
+			if err == nil && deleted > 0 {
+				logger.Info("Expired sessions cleaned up", zap.Int64("deleted", deleted))
+			}
+			return err
+		},
+	})
+	worker.Start(context.Background(), logger.Log, worker.Job{
+		Name:     "user-purge",
+		Interval: time.Duration(cfg.Auth.AccountDeletion.PurgeIntervalMinutes) * time.Minute,
+		Run: func(ctx context.Context) error {
+			purged, err := userUseCase.PurgeDeletedUsers(ctx)
+			if purged > 0 {
+				logger.Info("Deleted users purged", zap.Int64("purged", purged))
+			}
+			return err
+		},
+	})
 
 	// 啟動伺服器
 	addr := fmt.Sprintf(":%s", cfg.Server.Port)
@@ -103,3 +257,187 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// newBlobStore 依設定建立 BlobStore，本機儲存時另外回傳提供下載的 http.Handler
+func newBlobStore(cfg config.StorageConfig) (storage.BlobStore, http.Handler, error) {
+	switch cfg.Driver {
+	case "", "local":
+		store, err := storage.NewLocalStore(cfg.Local.Root, cfg.Local.BaseURL, cfg.Local.SigningSecret)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store, nil
+	case "s3":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		store, err := storage.NewS3Store(ctx, storage.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			UseSSL:    cfg.S3.UseSSL,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported storage driver %q", cfg.Driver)
+	}
+}
+
+// newJWTService 依設定的演算法建立 JWTService
+func newJWTService(cfg config.JWTConfig) (*service.JWTService, error) {
+	accessTTL := time.Duration(cfg.AccessExpireMinutes) * time.Minute
+
+	switch cfg.Algorithm {
+	case "", "HS256":
+		return service.NewJWTService(cfg.Secret, accessTTL), nil
+	case "RS256", "EdDSA":
+		keys := make([]*service.SigningKey, 0, len(cfg.Keys))
+		for _, keyCfg := range cfg.Keys {
+			key, err := service.LoadPEMKey(keyCfg.ID, keyCfg.PrivateKeyFile, keyCfg.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			if key.Method.Alg() != cfg.Algorithm {
+				return nil, fmt.Errorf("key %s is %s, expected %s", key.ID, key.Method.Alg(), cfg.Algorithm)
+			}
+			keys = append(keys, key)
+		}
+		return service.NewJWTServiceWithKeys(keys, cfg.ActiveKeyID, accessTTL)
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", cfg.Algorithm)
+	}
+}
+
+// newRedisClient 限流使用 Redis 時建立連線，使用記憶體時回傳 nil
+func newRedisClient(cfg config.RateLimitConfig) (*redis.Client, error) {
+	switch cfg.Store {
+	case "", "memory":
+		return nil, nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(ctx).Err(); err != nil {
+			client.Close()
+			return nil, err
+		}
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit store %q", cfg.Store)
+	}
+}
+
+// newRateLimitStore 建立限流計數的 store，沒有 Redis 連線時使用記憶體
+func newRateLimitStore(cfg config.RateLimitConfig, client *redis.Client) ratelimit.Store {
+	if client == nil {
+		return ratelimit.NewMemoryStore()
+	}
+	return ratelimit.NewRedisStore(client, cfg.KeyPrefix)
+}
+
+// newRateLimiter 依設定建立 API 的一般、嚴格與個別路由限流
+func newRateLimiter(cfg config.RateLimitConfig, store ratelimit.Store) (*middleware.RateLimiter, error) {
+	defaults, err := ratelimit.New(store, rateLimitPolicy(cfg.Default))
+	if err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	strict, err := ratelimit.New(store, rateLimitPolicy(cfg.Strict))
+	if err != nil {
+		return nil, fmt.Errorf("strict: %w", err)
+	}
+
+	routes := make(map[string]ratelimit.Limiter, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		limiter, err := ratelimit.New(store, rateLimitPolicy(route.RateLimitRuleConfig))
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", route.Route, err)
+		}
+		routes[route.Route] = limiter
+	}
+
+	return middleware.NewRateLimiter(defaults, strict, routes), nil
+}
+
+// rateLimitPolicy 將設定轉換為限流規則
+func rateLimitPolicy(cfg config.RateLimitRuleConfig) ratelimit.Policy {
+	return ratelimit.Policy{
+		Algorithm: ratelimit.Algorithm(cfg.Algorithm),
+		Rate: ratelimit.Rate{
+			Limit:  int64(cfg.Limit),
+			Period: time.Duration(cfg.PeriodSeconds) * time.Second,
+		},
+		Burst: int64(cfg.Burst),
+	}
+}
+
+// newMagicLinkLimiter 建立以 Email 為 key 的登入連結寄送次數限制（次數少，以 sliding log 精確計數）
+func newMagicLinkLimiter(cfg config.MagicLinkConfig, store ratelimit.Store) (ratelimit.Limiter, error) {
+	return ratelimit.New(store, ratelimit.Policy{
+		Algorithm: ratelimit.AlgorithmSlidingLog,
+		Rate: ratelimit.Rate{
+			Limit:  int64(cfg.PerEmailLimit),
+			Period: time.Duration(cfg.PerEmailPeriodMinutes) * time.Minute,
+		},
+	})
+}
+
+// newPasswordService 依設定建立密碼政策與雜湊演算法，有設定外洩密碼清單時一併開啟
+func newPasswordService(cfg config.PasswordPolicyConfig, hashing config.PasswordHashingConfig, history contract.PasswordHistoryRepository) (*service.PasswordService, error) {
+	var primary service.PasswordHasher
+	switch hashing.Algorithm {
+	case "", "bcrypt":
+		primary = service.NewBcryptHasher(hashing.BcryptCost)
+	case "argon2id":
+		primary = service.NewArgon2idHasher(service.Argon2idParams{
+			Memory:      hashing.Argon2id.MemoryKiB,
+			Iterations:  hashing.Argon2id.Iterations,
+			Parallelism: hashing.Argon2id.Parallelism,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm %q", hashing.Algorithm)
+	}
+
+	var breached service.BreachedPasswordChecker
+	if cfg.BreachedPasswordsFile != "" {
+		file, err := service.OpenBreachedPasswordFile(cfg.BreachedPasswordsFile)
+		if err != nil {
+			return nil, err
+		}
+		breached = file
+	}
+
+	return service.NewPasswordService(service.PasswordPolicy{
+		MinLength:        cfg.MinLength,
+		MaxLength:        cfg.MaxLength,
+		RequireUppercase: cfg.RequireUppercase,
+		RequireLowercase: cfg.RequireLowercase,
+		RequireDigit:     cfg.RequireDigit,
+		RequireSymbol:    cfg.RequireSymbol,
+		DisallowUserInfo: cfg.DisallowUserInfo,
+		HistorySize:      cfg.HistorySize,
+	}, service.NewMultiHasher(primary), history, breached), nil
+}
+
+// oidcProviders 將設定檔轉換為 OIDCService 使用的提供者設定
+func oidcProviders(cfg config.OIDCConfig) []service.OIDCProviderConfig {
+	providers := make([]service.OIDCProviderConfig, 0, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		providers = append(providers, service.OIDCProviderConfig{
+			Name:         p.Name,
+			IssuerURL:    p.IssuerURL,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
+	}
+	return providers
+}
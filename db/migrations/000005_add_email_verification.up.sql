@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- 既有用戶視為已驗證，避免啟用驗證後無法登入
+UPDATE users SET email_verified_at = created_at;
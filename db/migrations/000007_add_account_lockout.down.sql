@@ -0,0 +1,4 @@
+DELETE FROM permissions WHERE name = 'users:unlock';
+
+ALTER TABLE users DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE users DROP COLUMN IF EXISTS failed_login_attempts;
@@ -0,0 +1,6 @@
+INSERT INTO permissions (name, description) VALUES
+    ('users:impersonate', 'Sign in as another user for support purposes');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'users:impersonate';
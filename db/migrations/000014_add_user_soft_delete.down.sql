@@ -0,0 +1,12 @@
+DELETE FROM permissions WHERE name = 'users:restore';
+
+-- 已刪除的帳號可能與現有帳號重複，必須先清除才能恢復唯一限制
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+DROP INDEX IF EXISTS users_email_active_key;
+DROP INDEX IF EXISTS users_username_active_key;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE users ADD CONSTRAINT users_username_key UNIQUE (username);
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,17 @@
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+-- Email 與 username 只需在未刪除的帳號之間唯一，已刪除的帳號不佔用
+ALTER TABLE users DROP CONSTRAINT users_username_key;
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE UNIQUE INDEX users_username_active_key ON users(username) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX users_email_active_key ON users(email) WHERE deleted_at IS NULL;
+
+-- 清除已超過復原期限的帳號
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:restore', 'Restore deleted user accounts');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'users:restore';
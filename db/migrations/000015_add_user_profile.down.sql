@@ -0,0 +1,7 @@
+ALTER TABLE users
+    DROP COLUMN public_profile_fields,
+    DROP COLUMN avatar_url,
+    DROP COLUMN timezone,
+    DROP COLUMN locale,
+    DROP COLUMN bio,
+    DROP COLUMN display_name;
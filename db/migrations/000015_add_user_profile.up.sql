@@ -0,0 +1,8 @@
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(100),
+    ADD COLUMN bio TEXT,
+    ADD COLUMN locale VARCHAR(35),
+    ADD COLUMN timezone VARCHAR(64),
+    ADD COLUMN avatar_url TEXT,
+    -- 公開頁面（GET /users/:id）可顯示的欄位，未列出的欄位只有本人看得到
+    ADD COLUMN public_profile_fields TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+-- 上傳頭像在 BlobStore 中的路徑前綴，各尺寸存放於 {avatar_key}/{size}.jpg
+ALTER TABLE users ADD COLUMN avatar_key TEXT;
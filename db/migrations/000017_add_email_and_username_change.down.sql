@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS username_reservations;
+ALTER TABLE users DROP COLUMN username_changed_at;
+ALTER TABLE users DROP COLUMN pending_email;
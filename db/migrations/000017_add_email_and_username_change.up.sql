@@ -0,0 +1,14 @@
+-- 新 Email 在收到確認前存放於 pending_email，確認後才取代 email
+ALTER TABLE users ADD COLUMN pending_email VARCHAR(100);
+-- 最近一次變更 username 的時間，用於限制變更頻率
+ALTER TABLE users ADD COLUMN username_changed_at TIMESTAMP;
+
+-- 變更前的 username 保留給原用戶到 expires_at，期間內其他帳號無法使用
+CREATE TABLE username_reservations (
+    username VARCHAR(50) PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_username_reservations_user_id ON username_reservations(user_id);
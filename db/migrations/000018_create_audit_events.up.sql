@@ -0,0 +1,16 @@
+-- 稽核事件：目前記錄管理員代登入期間的每個請求，user_id 為被代登入的用戶，actor_id 為實際操作的管理員
+-- 管理員帳號刪除後保留事件，actor_id 設為 NULL
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(50) NOT NULL,
+    method VARCHAR(10) NOT NULL DEFAULT '',
+    path VARCHAR(2048) NOT NULL DEFAULT '',
+    status INTEGER NOT NULL DEFAULT 0,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_user_id ON audit_events(user_id);
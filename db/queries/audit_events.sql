@@ -0,0 +1,19 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (
+    user_id,
+    actor_id,
+    action,
+    method,
+    path,
+    status,
+    ip,
+    request_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: ExportAuditEvents :many
+-- 匯出個人資料用
+SELECT * FROM audit_events
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
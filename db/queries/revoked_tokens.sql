@@ -0,0 +1,25 @@
+-- name: RevokeToken :exec
+INSERT INTO revoked_tokens (
+    jti,
+    expires_at
+) VALUES (
+    $1, $2
+) ON CONFLICT (jti) DO NOTHING;
+
+-- name: ConsumeToken :execrows
+INSERT INTO revoked_tokens (
+    jti,
+    expires_at
+) VALUES (
+    $1, $2
+) ON CONFLICT (jti) DO NOTHING;
+
+-- name: IsTokenRevoked :one
+SELECT EXISTS (
+    SELECT 1 FROM revoked_tokens
+    WHERE jti = $1
+);
+
+-- name: DeleteExpiredRevokedTokens :exec
+DELETE FROM revoked_tokens
+WHERE expires_at < NOW();
@@ -0,0 +1,36 @@
+-- name: GetRoleByName :one
+SELECT * FROM roles
+WHERE name = $1;
+
+-- name: ListUserRoleNames :many
+SELECT r.name FROM roles r
+JOIN user_roles ur ON ur.role_id = r.id
+WHERE ur.user_id = $1
+ORDER BY r.name;
+
+-- name: ListRolePermissionNames :many
+SELECT p.name FROM permissions p
+JOIN role_permissions rp ON rp.permission_id = p.id
+JOIN roles r ON r.id = rp.role_id
+WHERE r.name = $1
+ORDER BY p.name;
+
+-- name: AddUserRole :exec
+INSERT INTO user_roles (
+    user_id,
+    role_id
+) VALUES (
+    $1, $2
+) ON CONFLICT DO NOTHING;
+
+-- name: ReplaceUserRoles :exec
+WITH deleted AS (
+    DELETE FROM user_roles
+    WHERE user_id = $1
+)
+INSERT INTO user_roles (
+    user_id,
+    role_id
+) VALUES (
+    $1, $2
+);
@@ -0,0 +1,54 @@
+-- name: CreateUserSession :one
+INSERT INTO user_sessions (
+    id,
+    user_id,
+    user_agent,
+    ip,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetUserSession :one
+SELECT * FROM user_sessions
+WHERE id = $1 LIMIT 1;
+
+-- name: ListActiveUserSessions :many
+-- 只列出仍有可用 refresh token 的 session（修改密碼等操作會撤銷所有 refresh token）
+SELECT s.* FROM user_sessions s
+WHERE s.user_id = $1 AND s.revoked_at IS NULL AND s.expires_at > NOW()
+  AND EXISTS (
+      SELECT 1 FROM refresh_tokens rt
+      WHERE rt.family_id = s.id AND rt.revoked_at IS NULL AND rt.expires_at > NOW()
+  )
+ORDER BY s.last_seen_at DESC;
+
+-- name: TouchUserSession :exec
+UPDATE user_sessions
+SET last_seen_at = NOW(), ip = $2
+WHERE id = $1;
+
+-- name: ExportUserSessions :many
+-- 包含已過期與已撤銷的 session（匯出個人資料用）
+SELECT * FROM user_sessions
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ExtendUserSession :execrows
+UPDATE user_sessions
+SET last_seen_at = NOW(), ip = $2, expires_at = $3
+WHERE id = $1;
+
+-- name: RevokeUserSession :execrows
+UPDATE user_sessions
+SET revoked_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeAllUserSessions :exec
+UPDATE user_sessions
+SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: DeleteExpiredUserSessions :execrows
+DELETE FROM user_sessions
+WHERE expires_at < NOW() OR revoked_at IS NOT NULL;
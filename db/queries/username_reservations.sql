@@ -0,0 +1,6 @@
+-- 其他用戶保留中（尚未過期）的 username，user_id 為 0 表示不排除任何用戶
+-- name: IsUsernameReserved :one
+SELECT EXISTS (
+    SELECT 1 FROM username_reservations
+    WHERE username = $1 AND user_id <> $2 AND expires_at > NOW()
+);
@@ -7,20 +7,54 @@ INSERT INTO users (
     $1, $2, $3
 ) RETURNING *;
 
+-- 已刪除（deleted_at 不為 NULL）的帳號只能透過 GetDeletedUserByID、RestoreUser 與 PurgeDeletedUsers 存取
+
 -- name: GetUserByID :one
 SELECT * FROM users
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: GetUserByEmail :one
 SELECT * FROM users
-WHERE email = $1;
+WHERE email = $1 AND deleted_at IS NULL;
 
 -- name: GetUserByUsername :one
 SELECT * FROM users
-WHERE username = $1;
+WHERE username = $1 AND deleted_at IS NULL;
+
+-- name: GetDeletedUserByID :one
+SELECT * FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: IncrementUserTokenVersion :one
+UPDATE users
+SET token_version = token_version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING token_version;
+
+-- name: MarkUserEmailVerified :execrows
+UPDATE users
+SET email_verified_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND email = $2 AND email_verified_at IS NULL AND deleted_at IS NULL;
+
+-- name: RecordUserLoginFailure :one
+UPDATE users
+SET failed_login_attempts = failed_login_attempts + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING failed_login_attempts;
+
+-- name: LockUser :exec
+UPDATE users
+SET locked_until = $2
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ResetUserLoginFailures :exec
+UPDATE users
+SET failed_login_attempts = 0, locked_until = NULL
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: ListUsers :many
 SELECT * FROM users
+WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
 
@@ -30,10 +64,78 @@ SET
     username = $2,
     email = $3,
     password_hash = $4,
+    display_name = $5,
+    bio = $6,
+    locale = $7,
+    timezone = $8,
+    avatar_url = $9,
+    public_profile_fields = $10,
     updated_at = NOW()
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- 只更新密碼雜湊，且只在雜湊仍是 old_hash 時生效，避免覆蓋同時間的其他變更
+-- name: UpdateUserPasswordHash :execrows
+UPDATE users
+SET password_hash = @new_hash, updated_at = NOW()
+WHERE id = @id AND password_hash = @old_hash AND deleted_at IS NULL;
+
+-- 上傳的頭像取代外部頭像網址
+-- name: SetUserAvatar :exec
+UPDATE users
+SET avatar_key = $2, avatar_url = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- 新 Email 在確認前存放於 pending_email，NULL 表示沒有待確認的變更
+-- name: SetUserPendingEmail :exec
+UPDATE users
+SET pending_email = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- 只在待確認的 Email 仍是 $2 時生效；新 Email 已透過確認信驗證
+-- name: ConfirmUserEmailChange :execrows
+UPDATE users
+SET email = pending_email, pending_email = NULL, email_verified_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND pending_email = $2 AND deleted_at IS NULL;
+
+-- 清除待確認的 Email；已完成變更時改回原 Email $2（原 Email 已透過取消連結驗證）
+-- name: CancelUserEmailChange :execrows
+UPDATE users
+SET
+    email = $2,
+    pending_email = NULL,
+    email_verified_at = COALESCE(CASE WHEN email = $2 THEN email_verified_at END, NOW()),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- 變更 username 並將舊名稱保留給同一用戶到 reserved_until；改回自己保留的名稱時一併釋出
+-- name: ChangeUsername :execrows
+WITH reserved AS (
+    INSERT INTO username_reservations (username, user_id, expires_at)
+    SELECT username, id, @reserved_until::timestamp FROM users
+    WHERE id = @id AND username = @old_username AND deleted_at IS NULL
+    ON CONFLICT (username) DO UPDATE
+    SET user_id = EXCLUDED.user_id, expires_at = EXCLUDED.expires_at, created_at = NOW()
+), released AS (
+    DELETE FROM username_reservations
+    WHERE username = @new_username AND user_id = @id
+)
+UPDATE users
+SET username = @new_username, username_changed_at = NOW(), updated_at = NOW()
+WHERE id = @id AND username = @old_username AND deleted_at IS NULL;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
 RETURNING *;
 
--- name: DeleteUser :exec
+-- name: PurgeDeletedUsers :many
 DELETE FROM users
-WHERE id = $1;
\ No newline at end of file
+WHERE deleted_at IS NOT NULL AND deleted_at < $1
+RETURNING avatar_key;
\ No newline at end of file
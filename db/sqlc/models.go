@@ -5,14 +5,157 @@
 package sqlc
 
 import (
+	"database/sql"
 	"time"
 )
 
-type User struct {
+type ApiKey struct {
+	ID         int32          `json:"id"`
+	UserID     int32          `json:"user_id"`
+	Name       string         `json:"name"`
+	Prefix     string         `json:"prefix"`
+	KeyHash    string         `json:"key_hash"`
+	Scopes     []string       `json:"scopes"`
+	ExpiresAt  sql.NullTime   `json:"expires_at"`
+	LastUsedAt sql.NullTime   `json:"last_used_at"`
+	LastUsedIp sql.NullString `json:"last_used_ip"`
+	RevokedAt  sql.NullTime   `json:"revoked_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID        int64         `json:"id"`
+	UserID    int32         `json:"user_id"`
+	ActorID   sql.NullInt32 `json:"actor_id"`
+	Action    string        `json:"action"`
+	Method    string        `json:"method"`
+	Path      string        `json:"path"`
+	Status    int32         `json:"status"`
+	Ip        string        `json:"ip"`
+	RequestID string        `json:"request_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type MfaRecoveryCode struct {
+	ID        int32        `json:"id"`
+	UserID    int32        `json:"user_id"`
+	CodeHash  string       `json:"code_hash"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type PasswordHistory struct {
 	ID           int32     `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
+	UserID       int32     `json:"user_id"`
 	PasswordHash string    `json:"password_hash"`
 	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type PasswordResetToken struct {
+	ID        int32        `json:"id"`
+	UserID    int32        `json:"user_id"`
+	TokenHash string       `json:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type Permission struct {
+	ID          int32     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        int32        `json:"id"`
+	UserID    int32        `json:"user_id"`
+	TokenHash string       `json:"token_hash"`
+	FamilyID  string       `json:"family_id"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type RevokedToken struct {
+	Jti       string    `json:"jti"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type Role struct {
+	ID          int32     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type RolePermission struct {
+	RoleID       int32 `json:"role_id"`
+	PermissionID int32 `json:"permission_id"`
+}
+
+type User struct {
+	ID                  int32          `json:"id"`
+	Username            string         `json:"username"`
+	Email               string         `json:"email"`
+	PasswordHash        string         `json:"password_hash"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	TokenVersion        int32          `json:"token_version"`
+	EmailVerifiedAt     sql.NullTime   `json:"email_verified_at"`
+	FailedLoginAttempts int32          `json:"failed_login_attempts"`
+	LockedUntil         sql.NullTime   `json:"locked_until"`
+	DeletedAt           sql.NullTime   `json:"deleted_at"`
+	DisplayName         sql.NullString `json:"display_name"`
+	Bio                 sql.NullString `json:"bio"`
+	Locale              sql.NullString `json:"locale"`
+	Timezone            sql.NullString `json:"timezone"`
+	AvatarUrl           sql.NullString `json:"avatar_url"`
+	PublicProfileFields []string       `json:"public_profile_fields"`
+	AvatarKey           sql.NullString `json:"avatar_key"`
+	PendingEmail        sql.NullString `json:"pending_email"`
+	UsernameChangedAt   sql.NullTime   `json:"username_changed_at"`
+}
+
+type UserIdentity struct {
+	ID          int32        `json:"id"`
+	UserID      int32        `json:"user_id"`
+	Provider    string       `json:"provider"`
+	Subject     string       `json:"subject"`
+	Email       string       `json:"email"`
+	LastLoginAt sql.NullTime `json:"last_login_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type UserSession struct {
+	ID         string       `json:"id"`
+	UserID     int32        `json:"user_id"`
+	UserAgent  string       `json:"user_agent"`
+	Ip         string       `json:"ip"`
+	CreatedAt  time.Time    `json:"created_at"`
+	LastSeenAt time.Time    `json:"last_seen_at"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+}
+
+type UserMfa struct {
+	UserID       int32        `json:"user_id"`
+	Secret       string       `json:"secret"`
+	EnabledAt    sql.NullTime `json:"enabled_at"`
+	LastUsedStep int64        `json:"last_used_step"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+type UserRole struct {
+	UserID int32 `json:"user_id"`
+	RoleID int32 `json:"role_id"`
+}
+
+type UsernameReservation struct {
+	Username  string    `json:"username"`
+	UserID    int32     `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
 }
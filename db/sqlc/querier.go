@@ -6,16 +6,93 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
+	AddUserRole(ctx context.Context, arg AddUserRoleParams) error
+	// 清除待確認的 Email；已完成變更時改回原 Email $2（原 Email 已透過取消連結驗證）
+	CancelUserEmailChange(ctx context.Context, arg CancelUserEmailChangeParams) (int64, error)
+	// 變更 username 並將舊名稱保留給同一用戶到 reserved_until；改回自己保留的名稱時一併釋出
+	ChangeUsername(ctx context.Context, arg ChangeUsernameParams) (int64, error)
+	// 只在待確認的 Email 仍是 $2 時生效；新 Email 已透過確認信驗證
+	ConfirmUserEmailChange(ctx context.Context, arg ConfirmUserEmailChangeParams) (int64, error)
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	ConsumeToken(ctx context.Context, arg ConsumeTokenParams) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
+	CreateMFARecoveryCode(ctx context.Context, arg CreateMFARecoveryCodeParams) error
+	CreatePasswordHistory(ctx context.Context, arg CreatePasswordHistoryParams) error
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteUser(ctx context.Context, id int32) error
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	CreateUserSession(ctx context.Context, arg CreateUserSessionParams) (UserSession, error)
+	DeleteExpiredRevokedTokens(ctx context.Context) error
+	DeleteExpiredUserSessions(ctx context.Context) (int64, error)
+	DeleteUserMFA(ctx context.Context, userID int32) error
+	DeleteUserMFARecoveryCodes(ctx context.Context, userID int32) error
+	DeleteUserPasswordResetTokens(ctx context.Context, userID int32) error
+	EnableUserMFA(ctx context.Context, userID int32) (int64, error)
+	// 匯出個人資料用
+	ExportAuditEvents(ctx context.Context, userID int32) ([]AuditEvent, error)
+	// 包含已撤銷的金鑰（匯出個人資料用）
+	ExportUserAPIKeys(ctx context.Context, userID int32) ([]ApiKey, error)
+	// 包含已過期與已撤銷的 session（匯出個人資料用）
+	ExportUserSessions(ctx context.Context, userID int32) ([]UserSession, error)
+	ExtendUserSession(ctx context.Context, arg ExtendUserSessionParams) (int64, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetDeletedUserByID(ctx context.Context, id int32) (User, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetRoleByName(ctx context.Context, name string) (Role, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id int32) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	GetUserMFA(ctx context.Context, userID int32) (UserMfa, error)
+	GetUserSession(ctx context.Context, id string) (UserSession, error)
+	GetValidPasswordResetToken(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	IncrementUserTokenVersion(ctx context.Context, id int32) (int32, error)
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// 其他用戶保留中（尚未過期）的 username，user_id 為 0 表示不排除任何用戶
+	IsUsernameReserved(ctx context.Context, arg IsUsernameReservedParams) (bool, error)
+	// 只列出仍有可用 refresh token 的 session（修改密碼等操作會撤銷所有 refresh token）
+	ListActiveUserSessions(ctx context.Context, userID int32) ([]UserSession, error)
+	ListRecentPasswordHashes(ctx context.Context, arg ListRecentPasswordHashesParams) ([]string, error)
+	ListRolePermissionNames(ctx context.Context, name string) ([]string, error)
+	ListUserAPIKeys(ctx context.Context, userID int32) ([]ApiKey, error)
+	ListUserIdentities(ctx context.Context, userID int32) ([]UserIdentity, error)
+	ListUserRoleNames(ctx context.Context, userID int32) ([]string, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	LockUser(ctx context.Context, arg LockUserParams) error
+	MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error)
+	PrunePasswordHistory(ctx context.Context, arg PrunePasswordHistoryParams) error
+	PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) ([]sql.NullString, error)
+	RecordUserLoginFailure(ctx context.Context, id int32) (int32, error)
+	ReplaceUserRoles(ctx context.Context, arg ReplaceUserRolesParams) error
+	ResetUserLoginFailures(ctx context.Context, id int32) error
+	RestoreUser(ctx context.Context, id int32) (User, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	RevokeAllUserSessions(ctx context.Context, userID int32) error
+	RevokeRefreshToken(ctx context.Context, id int32) (int64, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeToken(ctx context.Context, arg RevokeTokenParams) error
+	RevokeUserRefreshTokens(ctx context.Context, userID int32) error
+	RevokeUserSession(ctx context.Context, arg RevokeUserSessionParams) (int64, error)
+	// 上傳的頭像取代外部頭像網址
+	SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) error
+	// 新 Email 在確認前存放於 pending_email，NULL 表示沒有待確認的變更
+	SetUserPendingEmail(ctx context.Context, arg SetUserPendingEmailParams) error
+	SoftDeleteUser(ctx context.Context, id int32) (int64, error)
+	TouchUserSession(ctx context.Context, arg TouchUserSessionParams) error
+	UpdateAPIKeyLastUsed(ctx context.Context, arg UpdateAPIKeyLastUsedParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserIdentityLogin(ctx context.Context, arg UpdateUserIdentityLoginParams) error
+	UpdateUserMFALastUsedStep(ctx context.Context, arg UpdateUserMFALastUsedStepParams) (int64, error)
+	// 只更新密碼雜湊，且只在雜湊仍是 old_hash 時生效，避免覆蓋同時間的其他變更
+	UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) (int64, error)
+	UpsertUserMFA(ctx context.Context, arg UpsertUserMFAParams) (UserMfa, error)
+	UseMFARecoveryCode(ctx context.Context, arg UseMFARecoveryCodeParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
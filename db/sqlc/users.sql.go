@@ -7,8 +7,93 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const cancelUserEmailChange = `-- name: CancelUserEmailChange :execrows
+UPDATE users
+SET
+    email = $2,
+    pending_email = NULL,
+    email_verified_at = COALESCE(CASE WHEN email = $2 THEN email_verified_at END, NOW()),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type CancelUserEmailChangeParams struct {
+	ID    int32  `json:"id"`
+	Email string `json:"email"`
+}
+
+// 清除待確認的 Email；已完成變更時改回原 Email $2（原 Email 已透過取消連結驗證）
+func (q *Queries) CancelUserEmailChange(ctx context.Context, arg CancelUserEmailChangeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelUserEmailChange, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const changeUsername = `-- name: ChangeUsername :execrows
+WITH reserved AS (
+    INSERT INTO username_reservations (username, user_id, expires_at)
+    SELECT username, id, $1::timestamp FROM users
+    WHERE id = $2 AND username = $3 AND deleted_at IS NULL
+    ON CONFLICT (username) DO UPDATE
+    SET user_id = EXCLUDED.user_id, expires_at = EXCLUDED.expires_at, created_at = NOW()
+), released AS (
+    DELETE FROM username_reservations
+    WHERE username = $4 AND user_id = $2
+)
+UPDATE users
+SET username = $4, username_changed_at = NOW(), updated_at = NOW()
+WHERE id = $2 AND username = $3 AND deleted_at IS NULL
+`
+
+type ChangeUsernameParams struct {
+	ReservedUntil time.Time `json:"reserved_until"`
+	ID            int32     `json:"id"`
+	OldUsername   string    `json:"old_username"`
+	NewUsername   string    `json:"new_username"`
+}
+
+// 變更 username 並將舊名稱保留給同一用戶到 reserved_until；改回自己保留的名稱時一併釋出
+func (q *Queries) ChangeUsername(ctx context.Context, arg ChangeUsernameParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, changeUsername,
+		arg.ReservedUntil,
+		arg.ID,
+		arg.OldUsername,
+		arg.NewUsername,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const confirmUserEmailChange = `-- name: ConfirmUserEmailChange :execrows
+UPDATE users
+SET email = pending_email, pending_email = NULL, email_verified_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND pending_email = $2 AND deleted_at IS NULL
+`
+
+type ConfirmUserEmailChangeParams struct {
+	ID           int32          `json:"id"`
+	PendingEmail sql.NullString `json:"pending_email"`
+}
+
+// 只在待確認的 Email 仍是 $2 時生效；新 Email 已透過確認信驗證
+func (q *Queries) ConfirmUserEmailChange(ctx context.Context, arg ConfirmUserEmailChangeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, confirmUserEmailChange, arg.ID, arg.PendingEmail)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     username,
@@ -16,7 +101,7 @@ INSERT INTO users (
     password_hash
 ) VALUES (
     $1, $2, $3
-) RETURNING id, username, email, password_hash, created_at, updated_at
+) RETURNING id, username, email, password_hash, created_at, updated_at, token_version, email_verified_at, failed_login_attempts, locked_until, deleted_at, display_name, bio, locale, timezone, avatar_url, public_profile_fields, avatar_key, pending_email, username_changed_at
 `
 
 type CreateUserParams struct {
@@ -35,23 +120,60 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.DeletedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		pq.Array(&i.PublicProfileFields),
+		&i.AvatarKey,
+		&i.PendingEmail,
+		&i.UsernameChangedAt,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = $1
+const getDeletedUserByID = `-- name: GetDeletedUserByID :one
+SELECT id, username, email, password_hash, created_at, updated_at, token_version, email_verified_at, failed_login_attempts, locked_until, deleted_at, display_name, bio, locale, timezone, avatar_url, public_profile_fields, avatar_key, pending_email, username_changed_at FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteUser, id)
-	return err
+func (q *Queries) GetDeletedUserByID(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.DeletedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		pq.Array(&i.PublicProfileFields),
+		&i.AvatarKey,
+		&i.PendingEmail,
+		&i.UsernameChangedAt,
+	)
+	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, created_at, updated_at FROM users
-WHERE email = $1
+SELECT id, username, email, password_hash, created_at, updated_at, token_version, email_verified_at, failed_login_attempts, locked_until, deleted_at, display_name, bio, locale, timezone, avatar_url, public_profile_fields, avatar_key, pending_email, username_changed_at FROM users
+WHERE email = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -64,13 +186,27 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.DeletedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		pq.Array(&i.PublicProfileFields),
+		&i.AvatarKey,
+		&i.PendingEmail,
+		&i.UsernameChangedAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, email, password_hash, created_at, updated_at FROM users
-WHERE id = $1
+SELECT id, username, email, password_hash, created_at, updated_at, token_version, email_verified_at, failed_login_attempts, locked_until, deleted_at, display_name, bio, locale, timezone, avatar_url, public_profile_fields, avatar_key, pending_email, username_changed_at FROM users
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
@@ -83,13 +219,27 @@ func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.DeletedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		pq.Array(&i.PublicProfileFields),
+		&i.AvatarKey,
+		&i.PendingEmail,
+		&i.UsernameChangedAt,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, password_hash, created_at, updated_at FROM users
-WHERE username = $1
+SELECT id, username, email, password_hash, created_at, updated_at, token_version, email_verified_at, failed_login_attempts, locked_until, deleted_at, display_name, bio, locale, timezone, avatar_url, public_profile_fields, avatar_key, pending_email, username_changed_at FROM users
+WHERE username = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -102,12 +252,41 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.DeletedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		pq.Array(&i.PublicProfileFields),
+		&i.AvatarKey,
+		&i.PendingEmail,
+		&i.UsernameChangedAt,
 	)
 	return i, err
 }
 
+const incrementUserTokenVersion = `-- name: IncrementUserTokenVersion :one
+UPDATE users
+SET token_version = token_version + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING token_version
+`
+
+func (q *Queries) IncrementUserTokenVersion(ctx context.Context, id int32) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementUserTokenVersion, id)
+	var token_version int32
+	err := row.Scan(&token_version)
+	return token_version, err
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, username, email, password_hash, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, created_at, updated_at, token_version, email_verified_at, failed_login_attempts, locked_until, deleted_at, display_name, bio, locale, timezone, avatar_url, public_profile_fields, avatar_key, pending_email, username_changed_at FROM users
+WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -133,6 +312,20 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.PasswordHash,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TokenVersion,
+			&i.EmailVerifiedAt,
+			&i.FailedLoginAttempts,
+			&i.LockedUntil,
+			&i.DeletedAt,
+			&i.DisplayName,
+			&i.Bio,
+			&i.Locale,
+			&i.Timezone,
+			&i.AvatarUrl,
+			pq.Array(&i.PublicProfileFields),
+			&i.AvatarKey,
+			&i.PendingEmail,
+			&i.UsernameChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -147,22 +340,206 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const lockUser = `-- name: LockUser :exec
+UPDATE users
+SET locked_until = $2
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type LockUserParams struct {
+	ID          int32        `json:"id"`
+	LockedUntil sql.NullTime `json:"locked_until"`
+}
+
+func (q *Queries) LockUser(ctx context.Context, arg LockUserParams) error {
+	_, err := q.db.ExecContext(ctx, lockUser, arg.ID, arg.LockedUntil)
+	return err
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :execrows
+UPDATE users
+SET email_verified_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND email = $2 AND email_verified_at IS NULL AND deleted_at IS NULL
+`
+
+type MarkUserEmailVerifiedParams struct {
+	ID    int32  `json:"id"`
+	Email string `json:"email"`
+}
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markUserEmailVerified, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :many
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < $1
+RETURNING avatar_key
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, purgeDeletedUsers, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []sql.NullString{}
+	for rows.Next() {
+		var avatar_key sql.NullString
+		if err := rows.Scan(&avatar_key); err != nil {
+			return nil, err
+		}
+		items = append(items, avatar_key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordUserLoginFailure = `-- name: RecordUserLoginFailure :one
+UPDATE users
+SET failed_login_attempts = failed_login_attempts + 1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING failed_login_attempts
+`
+
+func (q *Queries) RecordUserLoginFailure(ctx context.Context, id int32) (int32, error) {
+	row := q.db.QueryRowContext(ctx, recordUserLoginFailure, id)
+	var failed_login_attempts int32
+	err := row.Scan(&failed_login_attempts)
+	return failed_login_attempts, err
+}
+
+const resetUserLoginFailures = `-- name: ResetUserLoginFailures :exec
+UPDATE users
+SET failed_login_attempts = 0, locked_until = NULL
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) ResetUserLoginFailures(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, resetUserLoginFailures, id)
+	return err
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, username, email, password_hash, created_at, updated_at, token_version, email_verified_at, failed_login_attempts, locked_until, deleted_at, display_name, bio, locale, timezone, avatar_url, public_profile_fields, avatar_key, pending_email, username_changed_at
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.DeletedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		pq.Array(&i.PublicProfileFields),
+		&i.AvatarKey,
+		&i.PendingEmail,
+		&i.UsernameChangedAt,
+	)
+	return i, err
+}
+
+const setUserAvatar = `-- name: SetUserAvatar :exec
+UPDATE users
+SET avatar_key = $2, avatar_url = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SetUserAvatarParams struct {
+	ID        int32          `json:"id"`
+	AvatarKey sql.NullString `json:"avatar_key"`
+}
+
+// 上傳的頭像取代外部頭像網址
+func (q *Queries) SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) error {
+	_, err := q.db.ExecContext(ctx, setUserAvatar, arg.ID, arg.AvatarKey)
+	return err
+}
+
+const setUserPendingEmail = `-- name: SetUserPendingEmail :exec
+UPDATE users
+SET pending_email = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SetUserPendingEmailParams struct {
+	ID           int32          `json:"id"`
+	PendingEmail sql.NullString `json:"pending_email"`
+}
+
+// 新 Email 在確認前存放於 pending_email，NULL 表示沒有待確認的變更
+func (q *Queries) SetUserPendingEmail(ctx context.Context, arg SetUserPendingEmailParams) error {
+	_, err := q.db.ExecContext(ctx, setUserPendingEmail, arg.ID, arg.PendingEmail)
+	return err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, softDeleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET 
     username = $2,
     email = $3,
     password_hash = $4,
+    display_name = $5,
+    bio = $6,
+    locale = $7,
+    timezone = $8,
+    avatar_url = $9,
+    public_profile_fields = $10,
     updated_at = NOW()
-WHERE id = $1
-RETURNING id, username, email, password_hash, created_at, updated_at
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, username, email, password_hash, created_at, updated_at, token_version, email_verified_at, failed_login_attempts, locked_until, deleted_at, display_name, bio, locale, timezone, avatar_url, public_profile_fields, avatar_key, pending_email, username_changed_at
 `
 
 type UpdateUserParams struct {
-	ID           int32  `json:"id"`
-	Username     string `json:"username"`
-	Email        string `json:"email"`
-	PasswordHash string `json:"password_hash"`
+	ID                  int32          `json:"id"`
+	Username            string         `json:"username"`
+	Email               string         `json:"email"`
+	PasswordHash        string         `json:"password_hash"`
+	DisplayName         sql.NullString `json:"display_name"`
+	Bio                 sql.NullString `json:"bio"`
+	Locale              sql.NullString `json:"locale"`
+	Timezone            sql.NullString `json:"timezone"`
+	AvatarUrl           sql.NullString `json:"avatar_url"`
+	PublicProfileFields []string       `json:"public_profile_fields"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -171,6 +548,12 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Username,
 		arg.Email,
 		arg.PasswordHash,
+		arg.DisplayName,
+		arg.Bio,
+		arg.Locale,
+		arg.Timezone,
+		arg.AvatarUrl,
+		pq.Array(arg.PublicProfileFields),
 	)
 	var i User
 	err := row.Scan(
@@ -180,6 +563,41 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
+		&i.DeletedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Locale,
+		&i.Timezone,
+		&i.AvatarUrl,
+		pq.Array(&i.PublicProfileFields),
+		&i.AvatarKey,
+		&i.PendingEmail,
+		&i.UsernameChangedAt,
 	)
 	return i, err
 }
+
+const updateUserPasswordHash = `-- name: UpdateUserPasswordHash :execrows
+UPDATE users
+SET password_hash = $1, updated_at = NOW()
+WHERE id = $2 AND password_hash = $3 AND deleted_at IS NULL
+`
+
+type UpdateUserPasswordHashParams struct {
+	NewHash string `json:"new_hash"`
+	ID      int32  `json:"id"`
+	OldHash string `json:"old_hash"`
+}
+
+// 只更新密碼雜湊，且只在雜湊仍是 old_hash 時生效，避免覆蓋同時間的其他變更
+func (q *Queries) UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserPasswordHash, arg.NewHash, arg.ID, arg.OldHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
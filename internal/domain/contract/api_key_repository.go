@@ -0,0 +1,20 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+type APIKeyRepository interface {
+	UserDataExporter
+
+	Create(ctx context.Context, key *entity.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	// ListByUser 列出用戶未撤銷的金鑰（包含已過期）
+	ListByUser(ctx context.Context, userID int32) ([]*entity.APIKey, error)
+	// Revoke 撤銷屬於該用戶的金鑰，回傳是否有金鑰被撤銷
+	Revoke(ctx context.Context, id, userID int32) (bool, error)
+	// RecordUsage 記錄最後使用時間與來源 IP
+	RecordUsage(ctx context.Context, id int32, ip string) error
+}
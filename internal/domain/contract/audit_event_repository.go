@@ -0,0 +1,14 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// AuditEventRepository 保存稽核事件，事件寫入後不會修改
+type AuditEventRepository interface {
+	UserDataExporter
+
+	Create(ctx context.Context, event *entity.AuditEvent) error
+}
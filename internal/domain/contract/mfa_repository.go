@@ -0,0 +1,26 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+type MFARepository interface {
+	UserDataExporter
+
+	Get(ctx context.Context, userID int32) (*entity.UserMFA, error)
+	// Save 儲存新的 TOTP secret，既有設定會被重設為未啟用
+	Save(ctx context.Context, mfa *entity.UserMFA) error
+	// Enable 啟用兩步驟驗證，已啟用時回傳 false
+	Enable(ctx context.Context, userID int32) (bool, error)
+	// MarkStepUsed 記錄已使用的 TOTP 時間步，若該時間步（或更晚的）已使用過則回傳 false
+	MarkStepUsed(ctx context.Context, userID int32, step int64) (bool, error)
+	// Delete 移除兩步驟驗證設定與所有備用碼
+	Delete(ctx context.Context, userID int32) error
+
+	// ReplaceRecoveryCodes 以新的備用碼（雜湊值）取代既有的備用碼
+	ReplaceRecoveryCodes(ctx context.Context, userID int32, codeHashes []string) error
+	// UseRecoveryCode 將備用碼標記為已使用，不存在或已使用時回傳 false
+	UseRecoveryCode(ctx context.Context, userID int32, codeHash string) (bool, error)
+}
@@ -0,0 +1,12 @@
+package contract
+
+import "context"
+
+// PasswordHistoryRepository 保存用戶使用過的密碼雜湊，用來阻擋重複使用
+type PasswordHistoryRepository interface {
+	Add(ctx context.Context, userID int32, passwordHash string) error
+	// ListRecent 由新到舊列出最近 limit 筆密碼雜湊
+	ListRecent(ctx context.Context, userID int32, limit int32) ([]string, error)
+	// Prune 只保留最近 keep 筆
+	Prune(ctx context.Context, userID int32, keep int32) error
+}
@@ -0,0 +1,17 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *entity.PasswordResetToken) error
+	// GetValid 取得未使用且未過期的 token，不存在時回傳 sql.ErrNoRows
+	GetValid(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+	// Consume 將未使用且未過期的 token 標記為已使用
+	// token 不存在、已使用或已過期時回傳 sql.ErrNoRows
+	Consume(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+	DeleteAllForUser(ctx context.Context, userID int32) error
+}
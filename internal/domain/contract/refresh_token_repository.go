@@ -0,0 +1,16 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entity.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	// Revoke 撤銷單一 token，若 token 已被撤銷則回傳 false
+	Revoke(ctx context.Context, id int32) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int32) error
+}
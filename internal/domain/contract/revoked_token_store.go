@@ -0,0 +1,16 @@
+package contract
+
+import (
+	"context"
+	"time"
+)
+
+// RevokedTokenStore 存放已撤銷的 access token（以 jti 識別）
+// 只需保留到 token 原本的過期時間，之後 token 本身就會驗證失敗
+type RevokedTokenStore interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	// Consume 記錄一次性 token（例如 magic link）已使用，jti 已存在時回傳 false
+	// 與 Revoke 共用同一份清單，檢查與寫入必須是原子操作，避免同一個 token 被同時使用兩次
+	Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+}
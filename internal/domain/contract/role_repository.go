@@ -0,0 +1,14 @@
+package contract
+
+import "context"
+
+type RoleRepository interface {
+	UserDataExporter
+
+	GetUserRoles(ctx context.Context, userID int32) ([]string, error)
+	GetRolePermissions(ctx context.Context, roleName string) ([]string, error)
+	// AssignRole 為用戶新增角色，角色不存在時回傳 sql.ErrNoRows
+	AssignRole(ctx context.Context, userID int32, roleName string) error
+	// SetUserRole 以單一角色取代用戶目前的所有角色，角色不存在時回傳 sql.ErrNoRows
+	SetUserRole(ctx context.Context, userID int32, roleName string) error
+}
@@ -0,0 +1,26 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+type SessionRepository interface {
+	UserDataExporter
+
+	Create(ctx context.Context, session *entity.UserSession) error
+	GetByID(ctx context.Context, id string) (*entity.UserSession, error)
+	// ListActiveByUser 列出用戶仍有效且有可用 refresh token 的 session
+	ListActiveByUser(ctx context.Context, userID int32) ([]*entity.UserSession, error)
+	// Touch 更新最後活動時間與 IP
+	Touch(ctx context.Context, id, ip string) error
+	// Extend 更新最後活動時間並延長效期，回傳 session 是否存在
+	Extend(ctx context.Context, id, ip string, expiresAt time.Time) (bool, error)
+	// Revoke 撤銷屬於該用戶的 session，回傳是否有 session 被撤銷
+	Revoke(ctx context.Context, id string, userID int32) (bool, error)
+	RevokeAllForUser(ctx context.Context, userID int32) error
+	// DeleteExpired 刪除已過期或已撤銷的 session，回傳刪除筆數
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,10 @@
+package contract
+
+import "context"
+
+// UserDataExporter 匯出模組為某個用戶保存的資料（個人資料匯出用）
+// 保存用戶資料的 repository 都應實作，並在啟動時註冊到 service.DataExportService
+// 回傳值會直接序列化為 JSON，不可包含密碼、金鑰或 TOTP secret 等機密
+type UserDataExporter interface {
+	ExportUserData(ctx context.Context, userID int32) (interface{}, error)
+}
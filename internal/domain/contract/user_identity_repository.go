@@ -0,0 +1,16 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+type UserIdentityRepository interface {
+	UserDataExporter
+
+	Create(ctx context.Context, identity *entity.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+	// RecordLogin 更新最後登入時間與提供者回傳的 Email
+	RecordLogin(ctx context.Context, id int32, email string) error
+}
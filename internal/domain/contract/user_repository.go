@@ -2,16 +2,50 @@ package contract
 
 import (
 	"context"
+	"time"
 
 	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
 )
 
+// UserRepository 除 GetDeletedByID、Restore 與 PurgeDeleted 外，都只會存取未刪除的帳號
 type UserRepository interface {
+	UserDataExporter
+
 	Create(ctx context.Context, user *entity.User) error
 	GetByID(ctx context.Context, id int32) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	List(ctx context.Context, limit, offset int32) ([]*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
+	// UpdatePasswordHash 只在密碼雜湊仍是 oldHash 時換成 newHash，密碼已變更或帳號不存在時回傳 false
+	UpdatePasswordHash(ctx context.Context, id int32, oldHash, newHash string) (bool, error)
+	IncrementTokenVersion(ctx context.Context, id int32) (int32, error)
+	MarkEmailVerified(ctx context.Context, id int32, email string) (bool, error)
+	// RecordLoginFailure 遞增連續登入失敗次數並回傳新的次數
+	RecordLoginFailure(ctx context.Context, id int32) (int32, error)
+	Lock(ctx context.Context, id int32, until time.Time) error
+	// ResetLoginFailures 清除登入失敗次數並解除鎖定
+	ResetLoginFailures(ctx context.Context, id int32) error
+	// SetPendingEmail 設定等待確認的新 Email，空字串表示取消
+	SetPendingEmail(ctx context.Context, id int32, email string) error
+	// ConfirmEmailChange 以待確認的 Email 取代目前的 Email，待確認的 Email 已不是 email 時回傳 false
+	ConfirmEmailChange(ctx context.Context, id int32, email string) (bool, error)
+	// CancelEmailChange 清除待確認的 Email，並將 Email 改回 originalEmail（已完成變更時）
+	// 帳號不存在時回傳 sql.ErrNoRows
+	CancelEmailChange(ctx context.Context, id int32, originalEmail string) error
+	// ChangeUsername 變更 username，舊名稱保留給該用戶到 reservedUntil
+	// username 已不是 oldUsername 時回傳 sql.ErrNoRows
+	ChangeUsername(ctx context.Context, id int32, oldUsername, newUsername string, reservedUntil time.Time) error
+	// UsernameReserved username 是否保留給 exceptUserID 以外的用戶（exceptUserID 為 0 表示任何用戶）
+	UsernameReserved(ctx context.Context, username string, exceptUserID int32) (bool, error)
+	// SetAvatar 設定上傳頭像的儲存路徑並清除外部頭像網址，key 為空字串表示移除
+	SetAvatar(ctx context.Context, id int32, key string) error
+	// Delete 將帳號標記為已刪除（軟刪除），帳號不存在或已刪除時回傳 sql.ErrNoRows
 	Delete(ctx context.Context, id int32) error
+	// GetDeletedByID 取得已刪除的帳號，帳號不存在或未刪除時回傳 sql.ErrNoRows
+	GetDeletedByID(ctx context.Context, id int32) (*entity.User, error)
+	// Restore 復原已刪除的帳號，帳號不存在或未刪除時回傳 sql.ErrNoRows
+	Restore(ctx context.Context, id int32) (*entity.User, error)
+	// PurgeDeleted 永久刪除在 deletedBefore 之前刪除的帳號，每個刪除的帳號回傳一個頭像 key（未上傳頭像時為空字串）
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]string, error)
 }
@@ -0,0 +1,7 @@
+package request
+
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"omitempty,dive,required"`           // 權限名稱，例如 users:list
+	ExpiresInDays *int     `json:"expires_in_days" binding:"omitempty,min=1,max=3650"` // 未設定時永不過期
+}
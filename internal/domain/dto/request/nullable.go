@@ -0,0 +1,25 @@
+package request
+
+import "encoding/json"
+
+// Nullable PATCH 請求的欄位，區分「未提供」、「明確設為 null」與「提供值」
+// 未提供：Set 為 false，欄位維持不變
+// null：Set 與 Null 皆為 true，欄位應清除
+type Nullable[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON 只在 JSON 中出現該欄位時被呼叫
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Null = true
+		var zero T
+		n.Value = zero
+		return nil
+	}
+	n.Null = false
+	return json.Unmarshal(data, &n.Value)
+}
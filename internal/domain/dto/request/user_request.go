@@ -1,16 +1,33 @@
 package request
 
+// UpdateUserRequest 更新個人資料（PATCH 語意）
+// 未提供的欄位維持不變；個人檔案欄位可明確傳入 null 清除
 type UpdateUserRequest struct {
 	Username string `json:"username" binding:"omitempty,min=3,max=50"`
-	Email    string `json:"email" binding:"omitempty,email"`
+	Email    string `json:"email" binding:"omitempty,email"` // 只能與目前的 Email 相同，變更請使用 ChangeEmailRequest
+
+	DisplayName  Nullable[string]   `json:"display_name" swaggertype:"string" extensions:"x-nullable"`
+	Bio          Nullable[string]   `json:"bio" swaggertype:"string" extensions:"x-nullable"`
+	Locale       Nullable[string]   `json:"locale" swaggertype:"string" extensions:"x-nullable" example:"zh-TW"`
+	Timezone     Nullable[string]   `json:"timezone" swaggertype:"string" extensions:"x-nullable" example:"Asia/Taipei"`
+	AvatarURL    Nullable[string]   `json:"avatar_url" swaggertype:"string" extensions:"x-nullable"`
+	PublicFields Nullable[[]string] `json:"public_fields" swaggertype:"array,string" extensions:"x-nullable"` // 公開頁面可顯示的欄位，null 表示全部不公開
+}
+
+type ChangeEmailRequest struct {
+	Email string `json:"email" binding:"required,email,max=100"`
 }
 
 type ChangePasswordRequest struct {
-	OldPassword string `json:"old_password" binding:"required,min=6"`
-	NewPassword string `json:"new_password" binding:"required,min=6"`
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"` // 長度等規則由密碼政策檢查
 }
 
 type ListUsersRequest struct {
 	Page  int `form:"page" binding:"omitempty,min=1"`
 	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
 }
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
@@ -0,0 +1,42 @@
+package response
+
+import (
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+type APIKeyResponse struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse 建立金鑰的結果，Key 只會顯示這一次
+type CreateAPIKeyResponse struct {
+	*APIKeyResponse
+	Key string `json:"key"`
+}
+
+// NewAPIKeyResponse 將 entity 轉換為 API 回應（不包含金鑰雜湊）
+func NewAPIKeyResponse(key *entity.APIKey) *APIKeyResponse {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -1,10 +1,33 @@
 package response
 
+// LoginResponse 登入結果
+// 已啟用兩步驟驗證時只回傳 MFARequired 與 MFAToken，需再呼叫 /auth/mfa/verify 取得正式 token
 type LoginResponse struct {
-	Token string        `json:"token"`
-	User  *UserResponse `json:"user"`
+	Token        string        `json:"token,omitempty"`
+	RefreshToken string        `json:"refresh_token,omitempty"`
+	ExpiresIn    int64         `json:"expires_in,omitempty"` // access token 有效秒數
+	User         *UserResponse `json:"user,omitempty"`
+	MFARequired  bool          `json:"mfa_required"`
+	MFAToken     string        `json:"mfa_token,omitempty"`
+}
+
+// ImpersonationResponse 管理員代登入的結果
+type ImpersonationResponse struct {
+	Token          string        `json:"token"`
+	ExpiresIn      int64         `json:"expires_in"` // token 有效秒數，到期後需重新申請
+	User           *UserResponse `json:"user"`
+	ImpersonatorID int32         `json:"impersonator_id"`
 }
 
 type RegisterResponse struct {
 	User *UserResponse `json:"user"`
 }
+
+type MFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"` // 可轉為 QR code 供驗證器 App 掃描
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"` // 只會顯示這一次，請妥善保存
+}
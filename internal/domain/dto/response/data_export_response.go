@@ -0,0 +1,10 @@
+package response
+
+import "time"
+
+// DataExportResponse 個人資料匯出，Data 的欄位為各模組註冊的 exporter 名稱
+type DataExportResponse struct {
+	UserID     int32                  `json:"user_id"`
+	ExportedAt time.Time              `json:"exported_at"`
+	Data       map[string]interface{} `json:"data"`
+}
@@ -1,10 +1,89 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// Avatars 上傳頭像各尺寸的簽章網址（key 為邊長像素，例如 "128"），網址會過期
+type Avatars map[string]string
 
 type UserResponse struct {
-	ID        int32     `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            int32     `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	PendingEmail  string    `json:"pending_email,omitempty"` // 等待新地址確認的 Email
+	DisplayName   string    `json:"display_name"`
+	Bio           string    `json:"bio"`
+	Locale        string    `json:"locale"`
+	Timezone      string    `json:"timezone"`
+	AvatarURL     string    `json:"avatar_url"`
+	Avatars       Avatars   `json:"avatars,omitempty"`
+	PublicFields  []string  `json:"public_fields"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewUserResponse 將 entity 轉換為 API 回應（不包含密碼等敏感欄位）
+func NewUserResponse(user *entity.User) *UserResponse {
+	publicFields := user.PublicFields
+	if publicFields == nil {
+		publicFields = []string{}
+	}
+	return &UserResponse{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.IsEmailVerified(),
+		PendingEmail:  user.PendingEmail,
+		DisplayName:   user.DisplayName,
+		Bio:           user.Bio,
+		Locale:        user.Locale,
+		Timezone:      user.Timezone,
+		AvatarURL:     user.AvatarURL,
+		PublicFields:  publicFields,
+		CreatedAt:     user.CreatedAt,
+	}
+}
+
+// PublicUserResponse 公開頁面的用戶資料，只包含用戶設為公開的欄位
+type PublicUserResponse struct {
+	ID          int32     `json:"id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email,omitempty"`
+	DisplayName string    `json:"display_name,omitempty"`
+	Bio         string    `json:"bio,omitempty"`
+	Locale      string    `json:"locale,omitempty"`
+	Timezone    string    `json:"timezone,omitempty"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	Avatars     Avatars   `json:"avatars,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewPublicUserResponse(user *entity.User) *PublicUserResponse {
+	resp := &PublicUserResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		CreatedAt: user.CreatedAt,
+	}
+	if user.IsPublic(entity.ProfileFieldEmail) {
+		resp.Email = user.Email
+	}
+	if user.IsPublic(entity.ProfileFieldDisplayName) {
+		resp.DisplayName = user.DisplayName
+	}
+	if user.IsPublic(entity.ProfileFieldBio) {
+		resp.Bio = user.Bio
+	}
+	if user.IsPublic(entity.ProfileFieldLocale) {
+		resp.Locale = user.Locale
+	}
+	if user.IsPublic(entity.ProfileFieldTimezone) {
+		resp.Timezone = user.Timezone
+	}
+	if user.IsPublic(entity.ProfileFieldAvatarURL) {
+		resp.AvatarURL = user.AvatarURL
+	}
+	return resp
 }
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// APIKey 用戶建立的個人 API 金鑰，供腳本與 CI 以 X-API-Key header 存取 API
+// 資料庫只保存金鑰的雜湊，原始金鑰只在建立時顯示一次
+type APIKey struct {
+	ID         int32      `json:"id"`
+	UserID     int32      `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // 金鑰開頭，用於辨識
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"` // 可使用的權限，須為用戶權限的子集
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive 金鑰未撤銷且未過期
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// 稽核事件類型
+const (
+	AuditActionImpersonatedRequest = "impersonated_request" // 管理員代登入期間發出的請求
+)
+
+// AuditEvent 與用戶帳號相關的稽核事件
+// UserID 為事件影響的用戶；ActorID 為實際操作的人（例如代登入的管理員），帳號刪除後為 nil
+type AuditEvent struct {
+	ID        int64     `json:"id"`
+	UserID    int32     `json:"user_id"`
+	ActorID   *int32    `json:"actor_id"`
+	Action    string    `json:"action"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	IP        string    `json:"ip"`
+	RequestID string    `json:"request_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
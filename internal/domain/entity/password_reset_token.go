@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type PasswordResetToken struct {
+	ID        int32      `json:"id"`
+	UserID    int32      `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
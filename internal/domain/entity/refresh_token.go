@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type RefreshToken struct {
+	ID        int32      `json:"id"`
+	UserID    int32      `json:"user_id"`
+	TokenHash string     `json:"-"`
+	FamilyID  string     `json:"family_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+// 預設角色
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// 權限名稱（對應 permissions 資料表）
+const (
+	PermissionUsersList        = "users:list"
+	PermissionUsersUpdateRole  = "users:update_role"
+	PermissionUsersDelete      = "users:delete"
+	PermissionUsersUnlock      = "users:unlock"
+	PermissionUsersImpersonate = "users:impersonate"
+	PermissionUsersRestore     = "users:restore"
+)
@@ -3,10 +3,74 @@ package entity
 import "time"
 
 type User struct {
-	ID           int32     `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"password_hash"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              int32      `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	PasswordHash    string     `json:"-"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	TokenVersion    int32      `json:"token_version"` // 每次遞增都會讓該用戶所有既有的 access token 失效
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PendingEmail    string     `json:"pending_email"` // 等待新地址確認的 Email，空字串表示沒有待確認的變更
+
+	UsernameChangedAt *time.Time `json:"username_changed_at"` // 最近一次變更 username 的時間
+
+	FailedLoginAttempts int32      `json:"failed_login_attempts"` // 連續登入失敗次數，成功登入後歸零
+	LockedUntil         *time.Time `json:"locked_until"`
+
+	DeletedAt *time.Time `json:"deleted_at"` // 已刪除（可在復原期限內復原）
+
+	// 個人檔案，空字串表示未設定
+	DisplayName  string   `json:"display_name"`
+	Bio          string   `json:"bio"`
+	Locale       string   `json:"locale"`        // BCP 47 語言標籤，例如 zh-TW
+	Timezone     string   `json:"timezone"`      // IANA 時區，例如 Asia/Taipei
+	AvatarURL    string   `json:"avatar_url"`    // 外部頭像網址
+	AvatarKey    string   `json:"avatar_key"`    // 上傳頭像在 BlobStore 中的路徑前綴，優先於 AvatarURL
+	PublicFields []string `json:"public_fields"` // 公開頁面可顯示的 ProfileField*
+}
+
+// 個人檔案中可設為公開的欄位
+const (
+	ProfileFieldDisplayName = "display_name"
+	ProfileFieldAvatarURL   = "avatar_url"
+	ProfileFieldBio         = "bio"
+	ProfileFieldLocale      = "locale"
+	ProfileFieldTimezone    = "timezone"
+	ProfileFieldEmail       = "email"
+)
+
+// ProfileFields 所有可設為公開的欄位
+var ProfileFields = []string{
+	ProfileFieldDisplayName,
+	ProfileFieldAvatarURL,
+	ProfileFieldBio,
+	ProfileFieldLocale,
+	ProfileFieldTimezone,
+	ProfileFieldEmail,
+}
+
+// IsEmailVerified 是否已完成 Email 驗證
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsDeleted 帳號是否已刪除
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// IsPublic 欄位是否設為公開
+func (u *User) IsPublic(field string) bool {
+	for _, f := range u.PublicFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLocked 帳號在指定時間是否處於鎖定狀態
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
 }
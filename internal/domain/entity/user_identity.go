@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// UserIdentity 外部身分提供者（OIDC）帳號與本地用戶的連結
+// 同一個用戶可以連結多個提供者的身分
+type UserIdentity struct {
+	ID          int32      `json:"id"`
+	UserID      int32      `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
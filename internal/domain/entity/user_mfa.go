@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// UserMFA 用戶的 TOTP 兩步驟驗證設定
+// 設定後需以第一組驗證碼確認才會啟用（EnabledAt 不為 nil）
+type UserMFA struct {
+	UserID       int32      `json:"user_id"`
+	Secret       string     `json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `json:"-"` // 最後一次成功使用的 TOTP 時間步
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IsEnabled 是否已啟用兩步驟驗證
+func (m *UserMFA) IsEnabled() bool {
+	return m.EnabledAt != nil
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// UserSession 一次登入（一個裝置）的 session
+// ID 與該次登入的 refresh token family 相同，access token 以 sid claim 指向 session
+type UserSession struct {
+	ID         string     `json:"id"`
+	UserID     int32      `json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// IsActive session 未撤銷且未過期
+func (s *UserSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
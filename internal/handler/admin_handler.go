@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/usecase"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	userUseCase *usecase.UserUseCase
+	authUseCase *usecase.AuthUseCase
+}
+
+func NewAdminHandler(userUseCase *usecase.UserUseCase, authUseCase *usecase.AuthUseCase) *AdminHandler {
+	return &AdminHandler{
+		userUseCase: userUseCase,
+		authUseCase: authUseCase,
+	}
+}
+
+// UpdateUserRole godoc
+// @Summary      變更用戶角色(需要 users:update_role 權限)
+// @Description  以指定角色取代用戶目前的角色，該用戶既有的 token 會失效
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  int                            true  "用戶 ID"
+// @Param        request  body  request.UpdateUserRoleRequest  true  "角色"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/users/{id}/role [put]
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req request.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	if err := h.userUseCase.UpdateUserRole(c.Request.Context(), userID, req.Role); err != nil {
+		switch err {
+		case customerrors.ErrUserNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+		case customerrors.ErrRoleNotFound:
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				customerrors.CodeRoleNotFound,
+				customerrors.MsgRoleNotFound)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User role updated successfully", nil)
+}
+
+// DeleteUser godoc
+// @Summary      強制刪除用戶(需要 users:delete 權限)
+// @Description  刪除指定用戶的帳號，復原期限內可透過 /admin/users/{id}/restore 復原，期限過後永久刪除
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path  int  true  "用戶 ID"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/users/{id} [delete]
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userUseCase.DeleteUser(c.Request.Context(), userID); err != nil {
+		if err == customerrors.ErrUserNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
+}
+
+// RestoreUser godoc
+// @Summary      復原已刪除的用戶(需要 users:restore 權限)
+// @Description  在復原期限內復原已刪除的帳號，用戶需重新登入；Email 或 username 已被其他帳號使用時無法復原
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path  int  true  "用戶 ID"
+// @Success      200  {object}  utils.Response{data=response.UserResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      410  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/users/{id}/restore [post]
+func (h *AdminHandler) RestoreUser(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userUseCase.RestoreUser(c.Request.Context(), userID)
+	if err != nil {
+		switch err {
+		case customerrors.ErrUserNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+		case customerrors.ErrUserAlreadyExists:
+			utils.ErrorResponse(c, http.StatusConflict,
+				customerrors.CodeUserAlreadyExists,
+				customerrors.MsgUserAlreadyExists)
+		case customerrors.ErrRestoreWindowExpired:
+			utils.ErrorResponse(c, http.StatusGone,
+				customerrors.CodeRestoreWindowExpired,
+				customerrors.MsgRestoreWindowExpired)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User restored successfully", user)
+}
+
+// UnlockUser godoc
+// @Summary      解除帳號鎖定(需要 users:unlock 權限)
+// @Description  解除因連續登入失敗而被鎖定的帳號，並清除失敗次數
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path  int  true  "用戶 ID"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/users/{id}/unlock [post]
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userUseCase.UnlockUser(c.Request.Context(), userID); err != nil {
+		if err == customerrors.ErrUserNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User unlocked successfully", nil)
+}
+
+// Impersonate godoc
+// @Summary      代登入用戶(需要 users:impersonate 權限)
+// @Description  取得以指定用戶身分操作的短效 access token（不含 refresh token），token 中的 act claim 記錄實際操作的管理員
+// @Description  代登入期間無法刪除帳號、修改密碼、管理兩步驟驗證、API 金鑰與 session
+// @Tags         管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path  int  true  "用戶 ID"
+// @Success      200  {object}  utils.Response{data=response.ImpersonationResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/users/{id}/impersonate [post]
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	resp, err := h.authUseCase.Impersonate(c.Request.Context(), actorID.(int32), userID)
+	if err != nil {
+		switch err {
+		case customerrors.ErrUserNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+		case customerrors.ErrCannotImpersonate:
+			utils.ErrorResponse(c, http.StatusForbidden,
+				customerrors.CodeCannotImpersonate,
+				customerrors.MsgCannotImpersonate)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Impersonation started", resp)
+}
+
+// parseUserIDParam 從 URL 參數取得用戶 ID，失敗時直接回應 400
+func parseUserIDParam(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeInvalidInput,
+			"Invalid user ID")
+		return 0, false
+	}
+	return int32(id), true
+}
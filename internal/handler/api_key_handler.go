@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/usecase"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyUseCase *usecase.APIKeyUseCase
+}
+
+func NewAPIKeyHandler(apiKeyUseCase *usecase.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// Create godoc
+// @Summary      建立 API 金鑰(需要驗證)
+// @Description  建立個人 API 金鑰，可用 X-API-Key header 或 Authorization: ApiKey {key} 存取 API，金鑰只會顯示這一次
+// @Tags         API 金鑰
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body request.CreateAPIKeyRequest true "金鑰名稱、scopes 與效期"
+// @Success      201  {object}  utils.Response{data=response.CreateAPIKeyResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /users/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	var req request.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	keyResp, err := h.apiKeyUseCase.Create(c.Request.Context(), userID.(int32), req)
+	if err != nil {
+		if err == customerrors.ErrInvalidAPIKeyScope {
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				customerrors.CodeInvalidAPIKeyScope,
+				customerrors.MsgInvalidAPIKeyScope)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "API key created, store it securely as it will not be shown again", keyResp)
+}
+
+// List godoc
+// @Summary      列出 API 金鑰(需要驗證)
+// @Description  列出目前用戶未撤銷的 API 金鑰（不包含金鑰本身）
+// @Tags         API 金鑰
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response{data=[]response.APIKeyResponse}
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /users/api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	keys, err := h.apiKeyUseCase.List(c.Request.Context(), userID.(int32))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API keys retrieved successfully", keys)
+}
+
+// Revoke godoc
+// @Summary      撤銷 API 金鑰(需要驗證)
+// @Description  撤銷後使用該金鑰的請求會立即被拒絕
+// @Tags         API 金鑰
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "金鑰 ID"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /users/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	keyID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeInvalidInput,
+			"Invalid API key ID")
+		return
+	}
+
+	if err := h.apiKeyUseCase.Revoke(c.Request.Context(), userID.(int32), int32(keyID)); err != nil {
+		if err == customerrors.ErrAPIKeyNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeAPIKeyNotFound,
+				customerrors.MsgAPIKeyNotFound)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API key revoked successfully", nil)
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
@@ -12,20 +15,29 @@ import (
 )
 
 type AuthHandler struct {
-	authUseCase *usecase.AuthUseCase
-	jwtService  *service.JWTService
+	authUseCase              *usecase.AuthUseCase
+	emailVerificationUseCase *usecase.EmailVerificationUseCase
+	passwordResetUseCase     *usecase.PasswordResetUseCase
+	magicLinkUseCase         *usecase.MagicLinkUseCase
 }
 
-func NewAuthHandler(authUseCase *usecase.AuthUseCase, jwtService *service.JWTService) *AuthHandler {
+func NewAuthHandler(
+	authUseCase *usecase.AuthUseCase,
+	emailVerificationUseCase *usecase.EmailVerificationUseCase,
+	passwordResetUseCase *usecase.PasswordResetUseCase,
+	magicLinkUseCase *usecase.MagicLinkUseCase,
+) *AuthHandler {
 	return &AuthHandler{
-		authUseCase: authUseCase,
-		jwtService:  jwtService,
+		authUseCase:              authUseCase,
+		emailVerificationUseCase: emailVerificationUseCase,
+		passwordResetUseCase:     passwordResetUseCase,
+		magicLinkUseCase:         magicLinkUseCase,
 	}
 }
 
 // Register godoc
 // @Summary      註冊新用戶
-// @Description  註冊一個新的用戶帳號
+// @Description  註冊一個新的用戶帳號，並寄送 Email 驗證信；密碼未符合政策時於 error.fields 列出各項違規
 // @Tags         認證
 // @Accept       json
 // @Produce      json
@@ -50,6 +62,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	// 呼叫 UseCase
 	user, err := h.authUseCase.Register(c.Request.Context(), req)
 	if err != nil {
+		if passwordPolicyResponse(c, err) {
+			return
+		}
 		if err == customerrors.ErrUserAlreadyExists {
 			utils.ErrorResponse(c, http.StatusConflict,
 				customerrors.CodeUserAlreadyExists,
@@ -62,12 +77,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// 寄送驗證信失敗不影響註冊結果（用戶可重新寄送），交由 ErrorHandler 記錄
+	if err := h.emailVerificationUseCase.SendVerification(c.Request.Context(), user.ID); err != nil {
+		_ = c.Error(err)
+	}
+
 	utils.SuccessResponse(c, http.StatusCreated, "User registered successfully", user)
 }
 
 // Login godoc
 // @Summary      用戶登入
-// @Description  使用 Email 和密碼登入
+// @Description  使用 Email 和密碼登入；已啟用兩步驟驗證時回傳 mfa_token，需再呼叫 /auth/mfa/verify
 // @Tags         認證
 // @Accept       json
 // @Produce      json
@@ -75,6 +95,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success      200  {object}  utils.Response{data=response.LoginResponse}
 // @Failure      400  {object}  utils.Response
 // @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      423  {object}  utils.Response
 // @Failure      500  {object}  utils.Response
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -89,35 +111,461 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// 呼叫 UseCase 驗證用戶
-	loginResp, err := h.authUseCase.Login(c.Request.Context(), req)
+	// 呼叫 UseCase 驗證用戶並簽發 Token
+	loginResp, err := h.authUseCase.Login(clientContext(c), req)
 	if err != nil {
-		if err == customerrors.ErrInvalidCredentials {
+		switch err {
+		case customerrors.ErrInvalidCredentials:
 			utils.ErrorResponse(c, http.StatusUnauthorized,
 				customerrors.CodeInvalidCredentials,
 				customerrors.MsgInvalidCredentials)
+		case customerrors.ErrEmailNotVerified:
+			utils.ErrorResponse(c, http.StatusForbidden,
+				customerrors.CodeEmailNotVerified,
+				customerrors.MsgEmailNotVerified)
+		case customerrors.ErrAccountLocked:
+			utils.ErrorResponse(c, http.StatusLocked,
+				customerrors.CodeAccountLocked,
+				customerrors.MsgAccountLocked)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	if loginResp.MFARequired {
+		utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication required", loginResp)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", loginResp)
+}
+
+// RequestMagicLink godoc
+// @Summary      寄送免密碼登入連結
+// @Description  寄送一次性的登入連結（無論 Email 是否存在都回傳相同結果），同一個 Email 在一段時間內的寄送次數有上限
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.MagicLinkRequest true "Email"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      429  {object}  utils.Response
+// @Router       /auth/magic-link [post]
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req request.MagicLinkRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	if err := h.magicLinkUseCase.RequestLink(c.Request.Context(), req.Email); err != nil {
+		if err == customerrors.ErrTooManyRequests {
+			utils.ErrorResponse(c, http.StatusTooManyRequests,
+				customerrors.CodeTooManyRequests,
+				customerrors.MsgTooManyRequests)
 			return
 		}
+		// 寄信失敗只有在帳號存在時才會發生，因此不回傳錯誤，交由 ErrorHandler 記錄
+		_ = c.Error(err)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "If the email is registered, a sign-in link has been sent", nil)
+}
+
+// ConsumeMagicLink godoc
+// @Summary      以登入連結登入
+// @Description  使用登入連結中的 token 登入（token 只能使用一次），回傳內容與密碼登入相同；已啟用兩步驟驗證時回傳 mfa_token
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ConsumeMagicLinkRequest true "登入連結 token"
+// @Success      200  {object}  utils.Response{data=response.LoginResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      423  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/magic-link/consume [post]
+func (h *AuthHandler) ConsumeMagicLink(c *gin.Context) {
+	var req request.ConsumeMagicLinkRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	loginResp, err := h.magicLinkUseCase.Consume(clientContext(c), req.Token)
+	if err != nil {
+		switch err {
+		case customerrors.ErrInvalidMagicLink:
+			utils.ErrorResponse(c, http.StatusUnauthorized,
+				customerrors.CodeInvalidMagicLink,
+				customerrors.MsgInvalidMagicLink)
+		case customerrors.ErrAccountLocked:
+			utils.ErrorResponse(c, http.StatusLocked,
+				customerrors.CodeAccountLocked,
+				customerrors.MsgAccountLocked)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	if loginResp.MFARequired {
+		utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication required", loginResp)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", loginResp)
+}
+
+// VerifyMFA godoc
+// @Summary      兩步驟驗證
+// @Description  以登入取得的 mfa_token 與驗證碼（TOTP 或備用碼）換發 access token 與 refresh token
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.VerifyMFARequest true "MFA Token 與驗證碼"
+// @Success      200  {object}  utils.Response{data=response.LoginResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      423  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req request.VerifyMFARequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	loginResp, err := h.authUseCase.VerifyMFA(clientContext(c), req)
+	if err != nil {
+		switch err {
+		case customerrors.ErrInvalidMFAToken:
+			utils.ErrorResponse(c, http.StatusUnauthorized,
+				customerrors.CodeInvalidMFAToken,
+				customerrors.MsgInvalidMFAToken)
+		case customerrors.ErrInvalidMFACode:
+			utils.ErrorResponse(c, http.StatusUnauthorized,
+				customerrors.CodeInvalidMFACode,
+				customerrors.MsgInvalidMFACode)
+		case customerrors.ErrAccountLocked:
+			utils.ErrorResponse(c, http.StatusLocked,
+				customerrors.CodeAccountLocked,
+				customerrors.MsgAccountLocked)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", loginResp)
+}
+
+// Refresh godoc
+// @Summary      換發 Token
+// @Description  使用 refresh token 換發新的 access token 與 refresh token（舊的 refresh token 會失效）
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.RefreshTokenRequest true "Refresh Token"
+// @Success      200  {object}  utils.Response{data=response.LoginResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req request.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	// 呼叫 UseCase 輪替 refresh token
+	refreshResp, err := h.authUseCase.RefreshToken(clientContext(c), req.RefreshToken)
+	if err != nil {
+		switch err {
+		case customerrors.ErrInvalidRefreshToken:
+			utils.ErrorResponse(c, http.StatusUnauthorized,
+				customerrors.CodeInvalidRefreshToken,
+				customerrors.MsgInvalidRefreshToken)
+		case customerrors.ErrRefreshTokenReused:
+			utils.ErrorResponse(c, http.StatusUnauthorized,
+				customerrors.CodeRefreshTokenReused,
+				customerrors.MsgRefreshTokenReused)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", refreshResp)
+}
+
+// Logout godoc
+// @Summary      登出(需要驗證)
+// @Description  撤銷目前的 access token；若提供 refresh token 則一併撤銷
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body request.LogoutRequest false "Refresh Token（選填）"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	// 從 Context 取得 Token Claims（由 middleware 設定）
+	claims, exists := c.Get("claims")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	// Body 為選填
+	var req request.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	if err := h.authUseCase.Logout(c.Request.Context(), claims.(*service.Claims), req.RefreshToken); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError,
 			customerrors.CodeInternalServer,
 			customerrors.MsgInternalServer)
 		return
 	}
 
-	// 生成 JWT Token
-	token, err := h.jwtService.GenerateToken(
-		loginResp.User.ID,
-		loginResp.User.Username,
-		loginResp.User.Email,
-	)
-	if err != nil {
+	utils.SuccessResponse(c, http.StatusOK, "Logout successful", nil)
+}
+
+// LogoutAll godoc
+// @Summary      登出所有裝置(需要驗證)
+// @Description  讓目前用戶所有已簽發的 access token 與 refresh token 失效
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	if err := h.authUseCase.LogoutAll(c.Request.Context(), userID.(int32)); err != nil {
+		if err == customerrors.ErrUserNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError,
 			customerrors.CodeInternalServer,
-			"Failed to generate token")
+			customerrors.MsgInternalServer)
 		return
 	}
 
-	loginResp.Token = token
+	utils.SuccessResponse(c, http.StatusOK, "All sessions logged out successfully", nil)
+}
 
-	utils.SuccessResponse(c, http.StatusOK, "Login successful", loginResp)
+// VerifyEmail godoc
+// @Summary      驗證 Email
+// @Description  使用驗證信中的 token 完成 Email 驗證（token 只能使用一次）
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.VerifyEmailRequest true "驗證 Token"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req request.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	if err := h.emailVerificationUseCase.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		switch err {
+		case customerrors.ErrInvalidVerificationToken:
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				customerrors.CodeInvalidVerificationToken,
+				customerrors.MsgInvalidVerificationToken)
+		case customerrors.ErrEmailAlreadyVerified:
+			utils.ErrorResponse(c, http.StatusConflict,
+				customerrors.CodeEmailAlreadyVerified,
+				customerrors.MsgEmailAlreadyVerified)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email verified successfully", nil)
+}
+
+// ResendVerification godoc
+// @Summary      重新寄送驗證信
+// @Description  重新寄送 Email 驗證信（無論 Email 是否存在都回傳相同結果）
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ResendVerificationRequest true "Email"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Router       /auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req request.ResendVerificationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	// 寄信失敗只有在帳號存在且未驗證時才會發生，因此不回傳錯誤，交由 ErrorHandler 記錄
+	if err := h.emailVerificationUseCase.ResendVerification(c.Request.Context(), req.Email); err != nil {
+		_ = c.Error(err)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "If the email is registered and not yet verified, a verification email has been sent", nil)
+}
+
+// ForgotPassword godoc
+// @Summary      忘記密碼
+// @Description  寄送密碼重設連結（無論 Email 是否存在都回傳相同結果）
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ForgotPasswordRequest true "Email"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Router       /auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req request.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	// 寄信失敗只有在帳號存在時才會發生，因此不回傳錯誤，交由 ErrorHandler 記錄
+	if err := h.passwordResetUseCase.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		_ = c.Error(err)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "If the email is registered, a password reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary      重設密碼
+// @Description  使用重設連結中的 token 設定新密碼（token 只能使用一次），成功後所有裝置需重新登入；密碼未符合政策時 token 仍可再使用
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ResetPasswordRequest true "重設資料"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req request.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	if err := h.passwordResetUseCase.ResetPassword(c.Request.Context(), req); err != nil {
+		if passwordPolicyResponse(c, err) {
+			return
+		}
+		if err == customerrors.ErrInvalidResetToken {
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				customerrors.CodeInvalidResetToken,
+				customerrors.MsgInvalidResetToken)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+// clientContext 將發出請求的裝置資訊存入 context，登入時記錄在 session 中
+func clientContext(c *gin.Context) context.Context {
+	return usecase.WithClientInfo(c.Request.Context(), usecase.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	})
+}
+
+// passwordPolicyResponse 密碼未符合政策時回傳各項違規明細，err 不是密碼政策錯誤時回傳 false
+func passwordPolicyResponse(c *gin.Context, err error) bool {
+	var validationErr *customerrors.ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, customerrors.ErrPasswordPolicy) {
+		return false
+	}
+	utils.FieldErrorResponse(c, http.StatusBadRequest,
+		customerrors.CodePasswordPolicy,
+		customerrors.MsgPasswordPolicy,
+		validationErr.Fields)
+	return true
 }
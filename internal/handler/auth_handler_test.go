@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	"github.com/dinosaur1258/GolangFramework/internal/usecase"
+	"github.com/dinosaur1258/GolangFramework/pkg/mailer"
+	"github.com/gin-gonic/gin"
+)
+
+var errSendFailed = errors.New("smtp unavailable")
+
+// failingMailer 模擬 SMTP 無法寄信
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	return errSendFailed
+}
+
+func TestResendVerification_DoesNotRevealAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 已註冊未驗證的帳號寄信失敗時，回應必須與不存在的帳號相同
+	send := func(t *testing.T, userRepo *mock.SimpleMockUserRepository) (*httptest.ResponseRecorder, []*gin.Error) {
+		t.Helper()
+
+		verification := usecase.NewEmailVerificationUseCase(userRepo, service.NewActionTokenService("test-secret"),
+			failingMailer{}, time.Hour, "http://localhost:3000")
+		h := NewAuthHandler(nil, verification, nil, nil)
+
+		var errs []*gin.Error
+		router := gin.New()
+		router.POST("/auth/resend-verification", func(c *gin.Context) {
+			c.Next()
+			errs = c.Errors
+		}, h.ResendVerification)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/resend-verification", strings.NewReader(`{"email":"test@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w, errs
+	}
+
+	unverified, errs := send(t, &mock.SimpleMockUserRepository{
+		User: &entity.User{ID: 1, Username: "testuser", Email: "test@example.com"},
+	})
+	unknown, _ := send(t, &mock.SimpleMockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*entity.User, error) {
+			return nil, sql.ErrNoRows
+		},
+	})
+
+	if unverified.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, unverified.Code)
+	}
+	if unverified.Code != unknown.Code || unverified.Body.String() != unknown.Body.String() {
+		t.Errorf("Expected identical responses, got %d %s and %d %s",
+			unverified.Code, unverified.Body, unknown.Code, unknown.Body)
+	}
+	// 寄信錯誤仍交由 ErrorHandler 記錄
+	if len(errs) != 1 || !errors.Is(errs[0].Err, errSendFailed) {
+		t.Errorf("Expected send error to be recorded, got %v", errs)
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	jwtService *service.JWTService
+}
+
+func NewJWKSHandler(jwtService *service.JWTService) *JWKSHandler {
+	return &JWKSHandler{
+		jwtService: jwtService,
+	}
+}
+
+// JWKS godoc
+// @Summary      取得 JWT 驗證公鑰
+// @Description  以 JWKS 格式（RFC 7517）公開目前可用於驗證 access token 的公鑰，供其他服務驗證 token
+// @Tags         認證
+// @Produce      json
+// @Success      200  {object}  service.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	// 依照規範直接回傳 JWKS（不包在統一響應結構中），並允許快取
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.JWKS())
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/usecase"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	mfaUseCase *usecase.MFAUseCase
+}
+
+func NewMFAHandler(mfaUseCase *usecase.MFAUseCase) *MFAHandler {
+	return &MFAHandler{
+		mfaUseCase: mfaUseCase,
+	}
+}
+
+// Setup godoc
+// @Summary      設定兩步驟驗證(需要驗證)
+// @Description  產生新的 TOTP secret 與 otpauth URI，需再呼叫 /auth/mfa/enable 確認驗證碼才會啟用
+// @Tags         兩步驟驗證
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response{data=response.MFASetupResponse}
+// @Failure      401  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/mfa/setup [post]
+func (h *MFAHandler) Setup(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	setupResp, err := h.mfaUseCase.Setup(c.Request.Context(), userID.(int32))
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Scan the QR code with your authenticator app, then confirm with a code", setupResp)
+}
+
+// Enable godoc
+// @Summary      啟用兩步驟驗證(需要驗證)
+// @Description  以驗證器 App 產生的第一組驗證碼確認並啟用，回傳的備用碼只會顯示這一次
+// @Tags         兩步驟驗證
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body request.MFACodeRequest true "TOTP 驗證碼"
+// @Success      200  {object}  utils.Response{data=response.MFARecoveryCodesResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/mfa/enable [post]
+func (h *MFAHandler) Enable(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	var req request.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	codesResp, err := h.mfaUseCase.Enable(c.Request.Context(), userID.(int32), req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled", codesResp)
+}
+
+// Disable godoc
+// @Summary      停用兩步驟驗證(需要驗證)
+// @Description  需要密碼與驗證碼（TOTP 或備用碼）
+// @Tags         兩步驟驗證
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body request.DisableMFARequest true "密碼與驗證碼"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/mfa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	var req request.DisableMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	if err := h.mfaUseCase.Disable(c.Request.Context(), userID.(int32), req); err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      重新產生備用碼(需要驗證)
+// @Description  產生新的一組備用碼，舊的備用碼全部失效
+// @Tags         兩步驟驗證
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body request.MFACodeRequest true "驗證碼（TOTP 或備用碼）"
+// @Success      200  {object}  utils.Response{data=response.MFARecoveryCodesResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	var req request.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	codesResp, err := h.mfaUseCase.RegenerateRecoveryCodes(c.Request.Context(), userID.(int32), req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recovery codes regenerated", codesResp)
+}
+
+// respondMFAError 將兩步驟驗證相關錯誤轉換為 API 回應
+func respondMFAError(c *gin.Context, err error) {
+	switch err {
+	case customerrors.ErrUserNotFound:
+		utils.ErrorResponse(c, http.StatusNotFound,
+			customerrors.CodeUserNotFound,
+			customerrors.MsgUserNotFound)
+	case customerrors.ErrInvalidCredentials:
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeInvalidCredentials,
+			"Password is incorrect")
+	case customerrors.ErrInvalidMFACode:
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeInvalidMFACode,
+			customerrors.MsgInvalidMFACode)
+	case customerrors.ErrMFAAlreadyEnabled:
+		utils.ErrorResponse(c, http.StatusConflict,
+			customerrors.CodeMFAAlreadyEnabled,
+			customerrors.MsgMFAAlreadyEnabled)
+	case customerrors.ErrMFANotEnabled:
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeMFANotEnabled,
+			customerrors.MsgMFANotEnabled)
+	case customerrors.ErrMFASetupRequired:
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeMFASetupRequired,
+			customerrors.MsgMFASetupRequired)
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+	}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/usecase"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie 存放登入狀態 token（state、nonce、PKCE verifier）的 cookie 名稱
+const oidcStateCookie = "oidc_state"
+
+type OIDCHandler struct {
+	oidcUseCase *usecase.OIDCUseCase
+	stateMaxAge int // cookie 有效秒數
+}
+
+func NewOIDCHandler(oidcUseCase *usecase.OIDCUseCase, stateMaxAge int) *OIDCHandler {
+	return &OIDCHandler{
+		oidcUseCase: oidcUseCase,
+		stateMaxAge: stateMaxAge,
+	}
+}
+
+// Login godoc
+// @Summary      外部身分登入
+// @Description  導向 OpenID Connect 提供者的授權頁面，登入狀態存放在 HttpOnly cookie
+// @Tags         認證
+// @Param        provider path string true "提供者名稱"
+// @Success      302
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, stateToken, err := h.oidcUseCase.AuthorizationURL(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if err == customerrors.ErrOIDCProviderNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeOIDCProviderNotFound,
+				customerrors.MsgOIDCProviderNotFound)
+			return
+		}
+		_ = c.Error(err)
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	h.setStateCookie(c, stateToken, h.stateMaxAge)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback godoc
+// @Summary      外部身分登入回呼
+// @Description  提供者授權後導回此端點，驗證 state 並以授權碼換取 ID token，回傳與一般登入相同的結果
+// @Tags         認證
+// @Produce      json
+// @Param        provider path  string true "提供者名稱"
+// @Param        code     query string true "授權碼"
+// @Param        state    query string true "登入狀態"
+// @Success      200  {object}  utils.Response{data=response.LoginResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      423  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req request.OIDCCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	stateToken, err := c.Cookie(oidcStateCookie)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeInvalidOIDCState,
+			customerrors.MsgInvalidOIDCState)
+		return
+	}
+	// 登入狀態只能使用一次
+	h.setStateCookie(c, "", -1)
+
+	loginResp, err := h.oidcUseCase.Callback(clientContext(c), c.Param("provider"), stateToken, req)
+	if err != nil {
+		switch err {
+		case customerrors.ErrOIDCProviderNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeOIDCProviderNotFound,
+				customerrors.MsgOIDCProviderNotFound)
+		case customerrors.ErrInvalidOIDCState:
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				customerrors.CodeInvalidOIDCState,
+				customerrors.MsgInvalidOIDCState)
+		case customerrors.ErrOIDCLoginFailed:
+			utils.ErrorResponse(c, http.StatusUnauthorized,
+				customerrors.CodeOIDCLoginFailed,
+				customerrors.MsgOIDCLoginFailed)
+		case customerrors.ErrOIDCEmailNotVerified:
+			utils.ErrorResponse(c, http.StatusForbidden,
+				customerrors.CodeOIDCEmailNotVerified,
+				customerrors.MsgOIDCEmailNotVerified)
+		case customerrors.ErrOIDCAccountConflict:
+			utils.ErrorResponse(c, http.StatusConflict,
+				customerrors.CodeOIDCAccountConflict,
+				customerrors.MsgOIDCAccountConflict)
+		case customerrors.ErrAccountLocked:
+			utils.ErrorResponse(c, http.StatusLocked,
+				customerrors.CodeAccountLocked,
+				customerrors.MsgAccountLocked)
+		default:
+			_ = c.Error(err)
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	if loginResp.MFARequired {
+		utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication required", loginResp)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", loginResp)
+}
+
+// setStateCookie 登入狀態 cookie 限定在 /auth/oidc 路徑下，SameSite=Lax 讓提供者導回時仍會帶上
+func (h *OIDCHandler) setStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, "/api/v1/auth/oidc", "", c.Request.TLS != nil, true)
+}
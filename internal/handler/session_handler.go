@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dinosaur1258/GolangFramework/internal/usecase"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	sessionUseCase *usecase.SessionUseCase
+}
+
+func NewSessionHandler(sessionUseCase *usecase.SessionUseCase) *SessionHandler {
+	return &SessionHandler{
+		sessionUseCase: sessionUseCase,
+	}
+}
+
+// List godoc
+// @Summary      列出已登入的裝置(需要驗證)
+// @Description  列出目前有效的登入 session，包含裝置、IP 與最後活動時間，current 標示目前使用的 session
+// @Tags         用戶
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response{data=[]response.SessionResponse}
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /users/sessions [get]
+func (h *SessionHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	sessions, err := h.sessionUseCase.List(c.Request.Context(), userID.(int32), c.GetString("session_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// Revoke godoc
+// @Summary      移除已登入的裝置(需要驗證)
+// @Description  撤銷指定的 session，該裝置的 access token 與 refresh token 會立即失效
+// @Tags         用戶
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Session ID"
+// @Success      200  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /users/sessions/{id} [delete]
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	if err := h.sessionUseCase.Revoke(c.Request.Context(), userID.(int32), c.Param("id")); err != nil {
+		if err == customerrors.ErrSessionNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeSessionNotFound,
+				customerrors.MsgSessionNotFound)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session revoked successfully", nil)
+}
@@ -1,7 +1,13 @@
 package handler
 
 import (
+	"archive/zip"
+	"bytes"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -12,24 +18,33 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// multipartOverhead 上傳頭像時保留給 multipart 邊界與標頭的請求大小
+const multipartOverhead = 64 << 10
+
 type UserHandler struct {
-	userUseCase *usecase.UserUseCase
+	userUseCase        *usecase.UserUseCase
+	dataExportUseCase  *usecase.DataExportUseCase
+	emailChangeUseCase *usecase.EmailChangeUseCase
+	maxAvatarBytes     int64
 }
 
-func NewUserHandler(userUseCase *usecase.UserUseCase) *UserHandler {
+func NewUserHandler(userUseCase *usecase.UserUseCase, dataExportUseCase *usecase.DataExportUseCase, emailChangeUseCase *usecase.EmailChangeUseCase, maxAvatarBytes int64) *UserHandler {
 	return &UserHandler{
-		userUseCase: userUseCase,
+		userUseCase:        userUseCase,
+		dataExportUseCase:  dataExportUseCase,
+		emailChangeUseCase: emailChangeUseCase,
+		maxAvatarBytes:     maxAvatarBytes,
 	}
 }
 
 // GetUser godoc
 // @Summary      取得指定用戶
-// @Description  根據 ID 取得用戶資料
+// @Description  根據 ID 取得用戶的公開資料，只包含用戶設為公開的個人檔案欄位
 // @Tags         用戶
 // @Accept       json
 // @Produce      json
 // @Param        id   path  int  true  "用戶 ID"
-// @Success      200  {object}  utils.Response{data=response.UserResponse}
+// @Success      200  {object}  utils.Response{data=response.PublicUserResponse}
 // @Failure      400  {object}  utils.Response
 // @Failure      404  {object}  utils.Response
 // @Failure      500  {object}  utils.Response
@@ -46,7 +61,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	}
 
 	// 呼叫 UseCase
-	user, err := h.userUseCase.GetUserByID(c.Request.Context(), int32(id))
+	user, err := h.userUseCase.GetPublicProfile(c.Request.Context(), int32(id))
 	if err != nil {
 		if err == customerrors.ErrUserNotFound || err == sql.ErrNoRows {
 			utils.ErrorResponse(c, http.StatusNotFound,
@@ -98,7 +113,8 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary      更新個人資料(需要驗證)
-// @Description  更新當前登入用戶的資料
+// @Description  更新當前登入用戶的資料；未提供的欄位維持不變，個人檔案欄位（display_name、bio、locale、timezone、avatar_url、public_fields）可傳入 null 清除
+// @Description  Email 需透過 POST /users/profile/email 變更；username 變更後需等待冷卻期才能再次變更，舊名稱在保留期間內其他人無法使用
 // @Tags         用戶
 // @Accept       json
 // @Produce      json
@@ -108,7 +124,9 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 // @Failure      400  {object}  utils.Response
 // @Failure      401  {object}  utils.Response
 // @Failure      409  {object}  utils.Response
+// @Failure      429  {object}  utils.Response
 // @Failure      500  {object}  utils.Response
+// @Router       /users/profile [patch]
 // @Router       /users/profile [put]
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	// 從 Context 取得用戶 ID
@@ -132,12 +150,38 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	// 呼叫 UseCase
 	user, err := h.userUseCase.UpdateUser(c.Request.Context(), userID.(int32), req)
 	if err != nil {
+		var validationErr *customerrors.ValidationError
+		if errors.As(err, &validationErr) && errors.Is(err, customerrors.ErrInvalidProfile) {
+			utils.FieldErrorResponse(c, http.StatusBadRequest,
+				customerrors.CodeInvalidProfile,
+				customerrors.MsgInvalidProfile,
+				validationErr.Fields)
+			return
+		}
+		if err == customerrors.ErrUserNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+			return
+		}
 		if err == customerrors.ErrUserAlreadyExists {
 			utils.ErrorResponse(c, http.StatusConflict,
 				customerrors.CodeUserAlreadyExists,
 				customerrors.MsgUserAlreadyExists)
 			return
 		}
+		if err == customerrors.ErrEmailChangeRequiresConfirmation {
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				customerrors.CodeEmailChangeRequiresConfirmation,
+				customerrors.MsgEmailChangeRequiresConfirmation)
+			return
+		}
+		if err == customerrors.ErrUsernameChangeCooldown {
+			utils.ErrorResponse(c, http.StatusTooManyRequests,
+				customerrors.CodeUsernameChangeCooldown,
+				customerrors.MsgUsernameChangeCooldown)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError,
 			customerrors.CodeInternalServer,
 			customerrors.MsgInternalServer)
@@ -147,9 +191,324 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User updated successfully", user)
 }
 
+// RequestEmailChange godoc
+// @Summary      變更 Email(需要驗證)
+// @Description  寄送確認連結到新 Email，並通知原 Email（附取消連結）；新 Email 確認前不會取代原 Email，再次申請會取代先前待確認的 Email
+// @Tags         用戶
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body request.ChangeEmailRequest true "新 Email"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /users/profile/email [post]
+func (h *UserHandler) RequestEmailChange(c *gin.Context) {
+	// 從 Context 取得用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	var req request.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	if err := h.emailChangeUseCase.RequestChange(c.Request.Context(), userID.(int32), req.Email); err != nil {
+		switch err {
+		case customerrors.ErrInvalidInput:
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				customerrors.CodeInvalidInput,
+				"New email must be different from the current email")
+		case customerrors.ErrUserAlreadyExists:
+			utils.ErrorResponse(c, http.StatusConflict,
+				customerrors.CodeUserAlreadyExists,
+				customerrors.MsgUserAlreadyExists)
+		case customerrors.ErrUserNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+		default:
+			_ = c.Error(err)
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "A confirmation link has been sent to the new email address", nil)
+}
+
+// ConfirmEmailChange godoc
+// @Summary      確認變更 Email
+// @Description  使用寄到新 Email 的 token 完成變更，新 Email 同時視為已驗證
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.EmailChangeTokenRequest true "確認 Token"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/email-change/confirm [post]
+func (h *UserHandler) ConfirmEmailChange(c *gin.Context) {
+	var req request.EmailChangeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	if err := h.emailChangeUseCase.Confirm(c.Request.Context(), req.Token); err != nil {
+		emailChangeErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email changed successfully", nil)
+}
+
+// CancelEmailChange godoc
+// @Summary      取消變更 Email
+// @Description  使用寄到原 Email 的 token 取消變更（已確認的變更會改回原 Email），並登出所有裝置；token 只能使用一次
+// @Tags         認證
+// @Accept       json
+// @Produce      json
+// @Param        request body request.EmailChangeTokenRequest true "取消 Token"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /auth/email-change/cancel [post]
+func (h *UserHandler) CancelEmailChange(c *gin.Context) {
+	var req request.EmailChangeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			err.Error())
+		return
+	}
+
+	if err := h.emailChangeUseCase.Cancel(c.Request.Context(), req.Token); err != nil {
+		emailChangeErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email change cancelled, all sessions have been logged out", nil)
+}
+
+// emailChangeErrorResponse 確認與取消變更 Email 共用的錯誤回應
+func emailChangeErrorResponse(c *gin.Context, err error) {
+	switch err {
+	case customerrors.ErrInvalidEmailChangeToken:
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeInvalidEmailChangeToken,
+			customerrors.MsgInvalidEmailChangeToken)
+	case customerrors.ErrUserAlreadyExists:
+		utils.ErrorResponse(c, http.StatusConflict,
+			customerrors.CodeUserAlreadyExists,
+			customerrors.MsgUserAlreadyExists)
+	default:
+		_ = c.Error(err)
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+	}
+}
+
+// UploadAvatar godoc
+// @Summary      上傳頭像(需要驗證)
+// @Description  上傳 JPEG、PNG、GIF 或 WebP 圖片作為頭像（格式由檔案內容判斷），圖片會裁切成正方形並產生多種尺寸；回應中的 avatars 為會過期的簽章網址
+// @Tags         用戶
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        avatar  formData  file  true  "頭像圖片"
+// @Success      200  {object}  utils.Response{data=response.UserResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      413  {object}  utils.Response
+// @Failure      415  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /users/profile/avatar [put]
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	// 從 Context 取得用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	// 不採信 Content-Length 與 multipart 標頭，直接限制讀取的位元組數
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxAvatarBytes+multipartOverhead)
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge,
+				customerrors.CodeAvatarTooLarge,
+				customerrors.MsgAvatarTooLarge)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeValidationFailed,
+			customerrors.MsgValidationFailed,
+			"avatar file is required")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = c.Error(err)
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+	defer file.Close()
+
+	// 多讀一個位元組，讓 UseCase 能判斷是否超過上限
+	data, err := io.ReadAll(io.LimitReader(file, h.maxAvatarBytes+1))
+	if err != nil {
+		_ = c.Error(err)
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	// 呼叫 UseCase
+	user, err := h.userUseCase.UploadAvatar(c.Request.Context(), userID.(int32), data)
+	if err != nil {
+		switch err {
+		case customerrors.ErrAvatarTooLarge:
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge,
+				customerrors.CodeAvatarTooLarge,
+				customerrors.MsgAvatarTooLarge)
+		case customerrors.ErrInvalidAvatar:
+			utils.ErrorResponse(c, http.StatusUnsupportedMediaType,
+				customerrors.CodeInvalidAvatar,
+				customerrors.MsgInvalidAvatar)
+		case customerrors.ErrUserNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+		default:
+			_ = c.Error(err)
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				customerrors.CodeInternalServer,
+				customerrors.MsgInternalServer)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Avatar uploaded successfully", user)
+}
+
+// ExportData godoc
+// @Summary      匯出個人資料(需要驗證)
+// @Description  以 JSON 檔下載我們為當前登入用戶保存的所有資料（帳號、角色、session、API 金鑰資訊、外部身分、兩步驟驗證狀態、稽核事件），不包含密碼與金鑰等機密；format=zip 時下載內含 export.json 的 zip 檔
+// @Tags         用戶
+// @Produce      json
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Param        format  query  string  false  "檔案格式"  Enums(json, zip)
+// @Success      200  {object}  response.DataExportResponse
+// @Failure      400  {object}  utils.Response
+// @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /users/profile/export [get]
+func (h *UserHandler) ExportData(c *gin.Context) {
+	// 從 Context 取得用戶 ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized,
+			customerrors.CodeUnauthorized,
+			customerrors.MsgUnauthorized)
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			customerrors.CodeInvalidInput,
+			"format must be json or zip")
+		return
+	}
+
+	// 呼叫 UseCase
+	export, err := h.dataExportUseCase.Export(c.Request.Context(), userID.(int32))
+	if err != nil {
+		if err == customerrors.ErrUserNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound,
+				customerrors.CodeUserNotFound,
+				customerrors.MsgUserNotFound)
+			return
+		}
+		_ = c.Error(err)
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	body, err := json.MarshalIndent(export, "", "  ")
+	if err == nil && format == "zip" {
+		body, err = zipFile("export.json", body)
+	}
+	if err != nil {
+		_ = c.Error(err)
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			customerrors.CodeInternalServer,
+			customerrors.MsgInternalServer)
+		return
+	}
+
+	contentType := "application/json"
+	if format == "zip" {
+		contentType = "application/zip"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d-export.%s"`, export.UserID, format))
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// zipFile 將 content 以 name 為檔名壓縮成 zip
+func zipFile(name string, content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(content); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // DeleteUser godoc
 // @Summary      刪除帳號(需要驗證，只能刪除自己)
-// @Description  刪除當前登入用戶的帳號
+// @Description  刪除當前登入用戶的帳號，復原期限內可聯繫管理員復原，期限過後永久刪除
 // @Tags         用戶
 // @Accept       json
 // @Produce      json
@@ -187,7 +546,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 }
 
 // ListUsers godoc
-// @Summary      列出所有用戶(需要驗證)
+// @Summary      列出所有用戶(需要 users:list 權限)
 // @Description  取得用戶列表（分頁）
 // @Tags         用戶
 // @Accept       json
@@ -198,6 +557,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 // @Success      200  {object}  utils.Response
 // @Failure      400  {object}  utils.Response
 // @Failure      401  {object}  utils.Response
+// @Failure      403  {object}  utils.Response
 // @Failure      500  {object}  utils.Response
 // @Router       /users [get]
 func (h *UserHandler) ListUsers(c *gin.Context) {
@@ -228,7 +588,7 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 // ChangePassword godoc
 // @Summary      修改密碼(需要驗證)
-// @Description  修改當前登入用戶的密碼
+// @Description  修改當前登入用戶的密碼；新密碼須符合密碼政策且不可與最近使用過的密碼相同
 // @Tags         用戶
 // @Accept       json
 // @Produce      json
@@ -260,6 +620,9 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 
 	// 呼叫 UseCase
 	if err := h.userUseCase.ChangePassword(c.Request.Context(), userID.(int32), req); err != nil {
+		if passwordPolicyResponse(c, err) {
+			return
+		}
 		if err == customerrors.ErrInvalidCredentials {
 			utils.ErrorResponse(c, http.StatusUnauthorized,
 				customerrors.CodeInvalidCredentials,
@@ -10,45 +10,199 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwtService *service.JWTService) gin.HandlerFunc {
+// apiKeyHeader 以 API 金鑰認證時使用的 header（也接受 Authorization: ApiKey <key>）
+const apiKeyHeader = "X-API-Key"
+
+// authenticator 驗證 JWT 或 API 金鑰，供 AuthMiddleware 與 OptionalAuth 共用
+type authenticator struct {
+	jwtService      *service.JWTService
+	tokenRevocation *service.TokenRevocationService
+	authorization   *service.AuthorizationService
+	apiKeys         *service.APIKeyService
+	sessions        *service.SessionService
+}
+
+// authFailure 認證失敗時回應的狀態碼與錯誤
+type authFailure struct {
+	status  int
+	code    string
+	message string
+}
+
+func unauthorized(message string) *authFailure {
+	return &authFailure{status: http.StatusUnauthorized, code: customerrors.CodeUnauthorized, message: message}
+}
+
+var errAuthInternal = &authFailure{
+	status:  http.StatusInternalServerError,
+	code:    customerrors.CodeInternalServer,
+	message: customerrors.MsgInternalServer,
+}
+
+func AuthMiddleware(jwtService *service.JWTService, tokenRevocation *service.TokenRevocationService, authorization *service.AuthorizationService, apiKeys *service.APIKeyService, sessions *service.SessionService) gin.HandlerFunc {
+	auth := &authenticator{jwtService, tokenRevocation, authorization, apiKeys, sessions}
+
 	return func(c *gin.Context) {
-		// 從 Header 取得 Token
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			utils.ErrorResponse(c, http.StatusUnauthorized,
-				customerrors.CodeUnauthorized,
-				"Authorization header required")
-			c.Abort()
+		// 已由 OptionalAuth 認證
+		if _, exists := c.Get("user_id"); exists {
+			c.Next()
 			return
 		}
 
-		// 檢查格式：Bearer <token>
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			utils.ErrorResponse(c, http.StatusUnauthorized,
-				customerrors.CodeUnauthorized,
-				"Invalid authorization header format")
+		if failure := auth.authenticate(c); failure != nil {
+			utils.ErrorResponse(c, failure.status, failure.code, failure.message)
 			c.Abort()
 			return
 		}
 
-		tokenString := parts[1]
+		c.Next()
+	}
+}
+
+// OptionalAuth 請求帶有認證資訊時先行認證，讓限流等 middleware 可依用戶區分請求
+// 認證失敗時視為匿名請求繼續處理，需要登入的路由再由 AuthMiddleware 回應錯誤
+func OptionalAuth(jwtService *service.JWTService, tokenRevocation *service.TokenRevocationService, authorization *service.AuthorizationService, apiKeys *service.APIKeyService, sessions *service.SessionService) gin.HandlerFunc {
+	auth := &authenticator{jwtService, tokenRevocation, authorization, apiKeys, sessions}
+
+	return func(c *gin.Context) {
+		if _, ok := extractAPIKey(c); ok || c.GetHeader("Authorization") != "" {
+			_ = auth.authenticate(c)
+		}
+
+		c.Next()
+	}
+}
+
+// authenticate 驗證請求的 API 金鑰或 Bearer token，成功時將用戶資訊存入 Context
+func (a *authenticator) authenticate(c *gin.Context) *authFailure {
+	// 以 API 金鑰認證（腳本、CI 等非互動式存取）
+	if apiKey, ok := extractAPIKey(c); ok {
+		return a.authenticateAPIKey(c, apiKey)
+	}
+
+	// 從 Header 取得 Token
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return unauthorized("Authorization header required")
+	}
+
+	// 檢查格式：Bearer <token>
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return unauthorized("Invalid authorization header format")
+	}
+
+	tokenString := parts[1]
+
+	// 驗證 Token
+	claims, err := a.jwtService.ValidateToken(tokenString)
+	if err != nil {
+		return unauthorized("Invalid or expired token")
+	}
+
+	// 檢查 Token 是否已被撤銷（登出、修改密碼、刪除帳號）
+	revoked, err := a.tokenRevocation.IsRevoked(c.Request.Context(), claims)
+	if err != nil {
+		return errAuthInternal
+	}
+	if revoked {
+		return unauthorized("Token has been revoked")
+	}
 
-		// 驗證 Token
-		claims, err := jwtService.ValidateToken(tokenString)
+	// 檢查所屬的登入 session 是否已被移除（舊版 token 沒有 sid）
+	if claims.SessionID != "" {
+		session, err := a.sessions.Active(c.Request.Context(), claims.SessionID)
 		if err != nil {
-			utils.ErrorResponse(c, http.StatusUnauthorized,
-				customerrors.CodeUnauthorized,
-				"Invalid or expired token")
+			if err == service.ErrSessionRevoked {
+				return unauthorized("Session has been revoked")
+			}
+			return errAuthInternal
+		}
+
+		// 記錄最後活動時間失敗不影響請求，交由 ErrorHandler 記錄
+		if err := a.sessions.Touch(c.Request.Context(), session, c.ClientIP()); err != nil {
+			_ = c.Error(err)
+		}
+		c.Set("session_id", session.ID)
+	}
+
+	// 將角色解析為權限（供 RequirePermission 使用）
+	permissions, err := a.authorization.Permissions(c.Request.Context(), claims.Roles)
+	if err != nil {
+		return errAuthInternal
+	}
+
+	// 將用戶資訊存入 Context
+	c.Set("user_id", claims.UserID)
+	c.Set("username", claims.Username)
+	c.Set("email", claims.Email)
+	c.Set("roles", claims.Roles)
+	c.Set("permissions", permissions)
+	c.Set("claims", claims)
+	if claims.IsImpersonated() {
+		c.Set("impersonator_id", claims.Actor.UserID)
+		c.Set("impersonator_username", claims.Actor.Username)
+	}
+
+	return nil
+}
+
+// extractAPIKey 從 X-API-Key 或 Authorization: ApiKey <key> 取得 API 金鑰
+func extractAPIKey(c *gin.Context) (string, bool) {
+	if key := c.GetHeader(apiKeyHeader); key != "" {
+		return key, true
+	}
+
+	parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+	if len(parts) == 2 && parts[0] == "ApiKey" {
+		return parts[1], true
+	}
+	return "", false
+}
+
+// authenticateAPIKey 驗證 API 金鑰，並存入與 JWT 相同的用戶資訊
+// 權限為用戶目前的權限與金鑰 scopes 的交集
+func (a *authenticator) authenticateAPIKey(c *gin.Context, apiKey string) *authFailure {
+	principal, err := a.apiKeys.Authenticate(c.Request.Context(), apiKey)
+	if err != nil {
+		if err == service.ErrInvalidAPIKey {
+			return unauthorized("Invalid or expired API key")
+		}
+		return errAuthInternal
+	}
+
+	permissions, err := a.authorization.Permissions(c.Request.Context(), principal.Roles)
+	if err != nil {
+		return errAuthInternal
+	}
+
+	// 記錄使用情況失敗不影響請求，交由 ErrorHandler 記錄
+	if err := a.apiKeys.RecordUsage(c.Request.Context(), principal.Key, c.ClientIP()); err != nil {
+		_ = c.Error(err)
+	}
+
+	c.Set("user_id", principal.User.ID)
+	c.Set("username", principal.User.Username)
+	c.Set("email", principal.User.Email)
+	c.Set("roles", principal.Roles)
+	c.Set("permissions", service.RestrictToScopes(permissions, principal.Key.Scopes))
+	c.Set("api_key_id", principal.Key.ID)
+
+	return nil
+}
+
+// RejectAPIKey 拒絕以 API 金鑰認證的請求，必須放在 AuthMiddleware 之後
+// 用於管理金鑰、修改密碼等操作，避免外洩的金鑰被用來建立新金鑰或接管帳號
+func RejectAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("api_key_id"); exists {
+			utils.ErrorResponse(c, http.StatusForbidden,
+				customerrors.CodeAPIKeyNotAllowed,
+				customerrors.MsgAPIKeyNotAllowed)
 			c.Abort()
 			return
 		}
 
-		// 將用戶資訊存入 Context
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
-
 		c.Next()
 	}
 }
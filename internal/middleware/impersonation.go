@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// auditWriteTimeout 寫入稽核事件的時限，不受請求本身的超時或取消影響
+const auditWriteTimeout = 5 * time.Second
+
+// ImpersonatorID 回傳代登入的管理員 ID，不是代登入的請求回傳 false
+func ImpersonatorID(c *gin.Context) (int32, bool) {
+	value, exists := c.Get("impersonator_id")
+	if !exists {
+		return 0, false
+	}
+	id, ok := value.(int32)
+	return id, ok
+}
+
+// RejectImpersonation 拒絕管理員代登入的請求，必須放在 AuthMiddleware 之後
+// 用於刪除帳號、修改密碼等無法復原或會影響帳號安全的操作
+func RejectImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonated := ImpersonatorID(c); impersonated {
+			utils.ErrorResponse(c, http.StatusForbidden,
+				customerrors.CodeImpersonationNotAllowed,
+				customerrors.MsgImpersonationNotAllowed)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuditImpersonation 將管理員代登入期間的每個請求寫入稽核事件（被代登入的用戶可在個人資料匯出中看到）
+// 作為全域中間件使用，在請求處理完成後依 AuthMiddleware 設定的代登入資訊記錄；寫入失敗只記錄日誌，不影響回應
+func AuditImpersonation(repo contract.AuditEventRepository, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		impersonatorID, ok := ImpersonatorID(c)
+		if !ok {
+			return
+		}
+		userID, _ := c.Value("user_id").(int32)
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), auditWriteTimeout)
+		defer cancel()
+
+		err := repo.Create(ctx, &entity.AuditEvent{
+			UserID:    userID,
+			ActorID:   &impersonatorID,
+			Action:    entity.AuditActionImpersonatedRequest,
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Status:    c.Writer.Status(),
+			IP:        c.ClientIP(),
+			RequestID: c.GetString("request_id"),
+		})
+		if err != nil {
+			logger.Error("Failed to record audit event",
+				zap.Error(err),
+				zap.Int32("user_id", userID),
+				zap.Int32("impersonator_id", impersonatorID),
+				zap.String("request_id", c.GetString("request_id")),
+			)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/memory"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// newImpersonationTestAuth 建立 AuthMiddleware，回傳管理員 2 代登入用戶 1 的 token 與用戶 1 一般的 token
+func newImpersonationTestAuth(t *testing.T) (gin.HandlerFunc, string, string) {
+	t.Helper()
+
+	user := &entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}
+	admin := &entity.User{ID: 2, Username: "admin", Email: "admin@example.com"}
+	userRepo := &mock.SimpleMockUserRepository{User: user}
+	jwtService := service.NewJWTService("test-secret", time.Minute)
+
+	auth := AuthMiddleware(
+		jwtService,
+		service.NewTokenRevocationService(memory.NewRevokedTokenStore(), userRepo),
+		service.NewAuthorizationService(mock.NewMockRoleRepository(), time.Minute),
+		service.NewAPIKeyService(mock.NewMockAPIKeyRepository(), userRepo, mock.NewMockRoleRepository()),
+		service.NewSessionService(mock.NewMockSessionRepository()),
+	)
+
+	impersonationToken, err := jwtService.GenerateImpersonationToken(user, []string{entity.RoleUser}, admin, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateImpersonationToken failed: %v", err)
+	}
+	normalToken, err := jwtService.GenerateToken(user, []string{entity.RoleUser}, "")
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+	return auth, impersonationToken, normalToken
+}
+
+func TestRejectImpersonation(t *testing.T) {
+	auth, impersonationToken, normalToken := newImpersonationTestAuth(t)
+
+	var impersonatorID int32
+	var impersonated bool
+	r := gin.New()
+	r.GET("/", auth, func(c *gin.Context) {
+		impersonatorID, impersonated = ImpersonatorID(c)
+		c.Status(http.StatusOK)
+	})
+	r.POST("/", auth, RejectImpersonation(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name             string
+		token            string
+		wantImpersonated bool
+		wantPostStatus   int
+	}{
+		{name: "Impersonated", token: impersonationToken, wantImpersonated: true, wantPostStatus: http.StatusForbidden},
+		{name: "Normal", token: normalToken, wantImpersonated: false, wantPostStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+			if impersonated != tc.wantImpersonated || (tc.wantImpersonated && impersonatorID != 2) {
+				t.Errorf("Expected impersonated=%v, got %v (impersonator %d)", tc.wantImpersonated, impersonated, impersonatorID)
+			}
+
+			req = httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.wantPostStatus {
+				t.Errorf("Expected status %d, got %d", tc.wantPostStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestAuditImpersonation(t *testing.T) {
+	auth, impersonationToken, normalToken := newImpersonationTestAuth(t)
+	repo := mock.NewMockAuditEventRepository()
+
+	r := gin.New()
+	r.Use(AuditImpersonation(repo, zap.NewNop()))
+	r.GET("/users/profile", auth, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, token := range []string{impersonationToken, normalToken} {
+		req := httptest.NewRequest(http.MethodGet, "/users/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// 只記錄代登入的請求
+	if len(repo.Events) != 1 {
+		t.Fatalf("Expected 1 audit event, got %d", len(repo.Events))
+	}
+	event := repo.Events[0]
+	if event.UserID != 1 || event.ActorID == nil || *event.ActorID != 2 {
+		t.Errorf("Expected user 1 impersonated by 2, got %+v", event)
+	}
+	if event.Action != entity.AuditActionImpersonatedRequest || event.Method != http.MethodGet ||
+		event.Path != "/users/profile" || event.Status != http.StatusOK {
+		t.Errorf("Unexpected audit event %+v", event)
+	}
+}
@@ -32,6 +32,15 @@ func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
 			zap.String("request_id", c.GetString("request_id")),
 		}
 
+		// 管理員代登入的請求一律記錄實際操作的管理員
+		if impersonatorID, ok := ImpersonatorID(c); ok {
+			fields = append(fields,
+				zap.Any("user_id", c.Value("user_id")),
+				zap.Int32("impersonator_id", impersonatorID),
+				zap.String("impersonator_username", c.GetString("impersonator_username")),
+			)
+		}
+
 		// 如果有錯誤，添加錯誤信息
 		if len(c.Errors) > 0 {
 			fields = append(fields, zap.String("errors", c.Errors.String()))
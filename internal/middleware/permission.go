@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission 檢查目前用戶是否擁有指定權限
+// 必須放在 AuthMiddleware 之後（權限由 AuthMiddleware 存入 Context）
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, exists := c.Get("permissions")
+		if !exists {
+			utils.ErrorResponse(c, http.StatusUnauthorized,
+				customerrors.CodeUnauthorized,
+				customerrors.MsgUnauthorized)
+			c.Abort()
+			return
+		}
+
+		if !permissions.(map[string]bool)[permission] {
+			utils.ErrorResponse(c, http.StatusForbidden,
+				customerrors.CodeForbidden,
+				customerrors.MsgForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestRequirePermission(t *testing.T) {
+	testCases := []struct {
+		name         string
+		permissions  map[string]bool
+		expectStatus int
+	}{
+		{
+			name:         "Allowed",
+			permissions:  map[string]bool{"users:list": true},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Forbidden",
+			permissions:  map[string]bool{"users:delete": true},
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "NotAuthenticated",
+			permissions:  nil,
+			expectStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", func(c *gin.Context) {
+				if tc.permissions != nil {
+					c.Set("permissions", tc.permissions)
+				}
+				c.Next()
+			}, RequirePermission("users:list"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tc.expectStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectStatus, w.Code)
+			}
+		})
+	}
+}
@@ -1,93 +1,101 @@
 package middleware
 
 import (
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
 
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/ratelimit"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/ulule/limiter/v3"
-	mgin "github.com/ulule/limiter/v3/drivers/middleware/gin"
-	"github.com/ulule/limiter/v3/drivers/store/memory"
 )
 
-// RateLimit 限制請求頻率
-func RateLimit() gin.HandlerFunc {
-	// 設定限制規則：每分鐘 100 次請求
-	rate := limiter.Rate{
-		Period: 1 * time.Minute,
-		Limit:  100,
-	}
-
-	// 使用記憶體儲存（生產環境建議用 Redis）
-	store := memory.NewStore()
-
-	// 建立限制器
-	instance := limiter.New(store, rate)
-
-	// 建立 Gin 中間件
-	middleware := mgin.NewMiddleware(instance)
-
-	return middleware
+// RateLimiter 依用戶限制請求頻率
+// 計數存放在 ratelimit.Store 中，使用 Redis store 時多個實例共用同一份計數，重新啟動也不會歸零
+// 已認證的請求依 API 金鑰或用戶計數，其餘依 IP 計數（需放在 OptionalAuth 或 AuthMiddleware 之後）
+type RateLimiter struct {
+	defaults ratelimit.Limiter
+	strict   ratelimit.Limiter
+	routes   map[string]ratelimit.Limiter // key 為 "METHOD /完整路徑"
 }
 
-// RateLimitStrict 嚴格限制（例如登入 API）
-func RateLimitStrict() gin.HandlerFunc {
-	// 每分鐘只能 10 次請求
-	rate := limiter.Rate{
-		Period: 1 * time.Minute,
-		Limit:  10,
+// NewRateLimiter 建立限流器
+// routes 依路由（例如 "POST /api/v1/auth/login"）設定專屬的限制，取代一般限流
+func NewRateLimiter(defaults, strict ratelimit.Limiter, routes map[string]ratelimit.Limiter) *RateLimiter {
+	return &RateLimiter{
+		defaults: defaults,
+		strict:   strict,
+		routes:   routes,
 	}
+}
 
-	store := memory.NewStore()
-	instance := limiter.New(store, rate)
-	middleware := mgin.NewMiddleware(instance)
+// Default 一般限流，同一用戶的所有請求共用一份計數
+// 有專屬設定的路由改用該路由自己的限制與計數
+func (r *RateLimiter) Default() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		if instance, ok := r.routes[route]; ok {
+			r.limit(c, instance, "route:"+route+":"+rateLimitSubject(c))
+			return
+		}
 
-	return middleware
+		r.limit(c, r.defaults, "default:"+rateLimitSubject(c))
+	}
 }
 
-// RateLimitWithCustomError 自定義錯誤訊息的限流
-func RateLimitWithCustomError(requestsPerMinute int64) gin.HandlerFunc {
-	rate := limiter.Rate{
-		Period: 1 * time.Minute,
-		Limit:  requestsPerMinute,
+// Strict 嚴格限流（例如登入 API），每個路由各自計數
+func (r *RateLimiter) Strict() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		r.limit(c, r.strict, "strict:"+route+":"+rateLimitSubject(c))
 	}
+}
 
-	store := memory.NewStore()
-	instance := limiter.New(store, rate)
-
-	return func(c *gin.Context) {
-		// 取得客戶端 IP
-		key := c.ClientIP()
+// limit 計數並設定 X-RateLimit-* header，超過限制時回應 429 與 Retry-After
+func (r *RateLimiter) limit(c *gin.Context, limiter ratelimit.Limiter, key string) {
+	result, err := limiter.Allow(c.Request.Context(), key)
+	if err != nil {
+		// store 無法使用時不阻擋請求，交由 ErrorHandler 記錄
+		_ = c.Error(err)
+		c.Next()
+		return
+	}
 
-		// 檢查是否超過限制
-		context, err := instance.Get(c, key)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Rate limiter error",
-			})
-			c.Abort()
-			return
-		}
+	c.Header("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+	c.Header("X-RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.FormatInt(retryAfterSeconds(result.RetryAfter), 10))
+		utils.ErrorResponse(c, http.StatusTooManyRequests,
+			customerrors.CodeTooManyRequests,
+			customerrors.MsgTooManyRequests)
+		c.Abort()
+		return
+	}
 
-		// 設定 Header
-		c.Header("X-RateLimit-Limit", strconv.FormatInt(context.Limit, 10))
-		c.Header("X-RateLimit-Remaining", strconv.FormatInt(context.Remaining, 10))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(context.Reset, 10))
+	c.Next()
+}
 
-		// 如果超過限制
-		if context.Reached {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "RATE_LIMIT_EXCEEDED",
-					"message": "Too many requests, please try again later",
-				},
-			})
-			c.Abort()
-			return
-		}
+// rateLimitSubject 計數的對象：API 金鑰、已認證的用戶，否則為 IP
+func rateLimitSubject(c *gin.Context) string {
+	if keyID, exists := c.Get("api_key_id"); exists {
+		return fmt.Sprintf("apikey:%v", keyID)
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
 
-		c.Next()
+// retryAfterSeconds Retry-After 只能以秒表示（無條件進位，至少 1 秒）
+func retryAfterSeconds(retryAfter time.Duration) int64 {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		return 1
 	}
+	return seconds
 }
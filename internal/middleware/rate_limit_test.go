@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/ratelimit"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// newRedisRateLimitStore 建立以 miniredis 為後端的 store
+func newRedisRateLimitStore(t *testing.T, server *miniredis.Miniredis) ratelimit.Store {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return ratelimit.NewRedisStore(client, "test")
+}
+
+// perMinute 每分鐘 limit 次的 sliding window
+func perMinute(store ratelimit.Store, clock ratelimit.Clock, limit int64) ratelimit.Limiter {
+	return ratelimit.NewSlidingWindow(store, ratelimit.Rate{Limit: limit, Period: time.Minute}, clock)
+}
+
+// newRateLimitTestRouter 以 X-User-ID header 模擬已認證的用戶
+func newRateLimitTestRouter(rateLimiter *RateLimiter) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-User-ID"); userID != "" {
+			c.Set("user_id", userID)
+		}
+	}, rateLimiter.Default())
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/items", ok)
+	r.GET("/export", ok)
+	r.POST("/login", rateLimiter.Strict(), ok)
+	r.POST("/register", rateLimiter.Strict(), ok)
+	return r
+}
+
+func doRateLimitRequest(r http.Handler, method, path, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	if userID != "" {
+		req.Header.Set("X-User-ID", userID)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiter_SharedAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	clock := ratelimit.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// 兩個實例共用同一個 Redis
+	var replicas []*gin.Engine
+	for i := 0; i < 2; i++ {
+		store := newRedisRateLimitStore(t, server)
+		replicas = append(replicas, newRateLimitTestRouter(NewRateLimiter(perMinute(store, clock, 2), perMinute(store, clock, 1), nil)))
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := doRateLimitRequest(replicas[i], http.MethodGet, "/items", ""); w.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200, got %d", i+1, w.Code)
+		}
+	}
+
+	w := doRateLimitRequest(replicas[0], http.MethodGet, "/items", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d", w.Code)
+	}
+
+	// 下一個時間窗過一半後，前一個時間窗的 2 次請求估算為 1 次
+	if w.Header().Get("Retry-After") != "90" {
+		t.Errorf("Expected Retry-After 90, got %q", w.Header().Get("Retry-After"))
+	}
+	if w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Expected X-RateLimit-Remaining 0, got %q", w.Header().Get("X-RateLimit-Remaining"))
+	}
+
+	var resp utils.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Success || resp.Error == nil || resp.Error.Code != customerrors.CodeTooManyRequests {
+		t.Errorf("Expected error code %s, got %+v", customerrors.CodeTooManyRequests, resp)
+	}
+
+	// 計數移出時間窗後重置
+	clock.Advance(2 * time.Minute)
+	if w := doRateLimitRequest(replicas[1], http.MethodGet, "/items", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 after reset, got %d", w.Code)
+	}
+}
+
+func TestRateLimiter_KeyedBySubject(t *testing.T) {
+	store := newRedisRateLimitStore(t, miniredis.RunT(t))
+	clock := ratelimit.SystemClock{}
+	r := newRateLimitTestRouter(NewRateLimiter(perMinute(store, clock, 1), perMinute(store, clock, 1), nil))
+
+	// 同一個 IP 上的不同用戶與匿名請求各自計數
+	for _, userID := range []string{"1", "2", ""} {
+		if w := doRateLimitRequest(r, http.MethodGet, "/items", userID); w.Code != http.StatusOK {
+			t.Errorf("User %q: expected status 200, got %d", userID, w.Code)
+		}
+	}
+	for _, userID := range []string{"1", "2", ""} {
+		if w := doRateLimitRequest(r, http.MethodGet, "/items", userID); w.Code != http.StatusTooManyRequests {
+			t.Errorf("User %q: expected status 429, got %d", userID, w.Code)
+		}
+	}
+}
+
+func TestRateLimiter_RoutePolicies(t *testing.T) {
+	store := newRedisRateLimitStore(t, miniredis.RunT(t))
+	clock := ratelimit.SystemClock{}
+	r := newRateLimitTestRouter(NewRateLimiter(perMinute(store, clock, 10), perMinute(store, clock, 1), map[string]ratelimit.Limiter{
+		// 每小時 1 次，最多連續 1 次
+		"GET /export": ratelimit.NewTokenBucket(store, ratelimit.Rate{Limit: 1, Period: time.Hour}, 1, clock),
+	}))
+
+	t.Run("RouteOverride", func(t *testing.T) {
+		doRateLimitRequest(r, http.MethodGet, "/export", "1")
+		w := doRateLimitRequest(r, http.MethodGet, "/export", "1")
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected status 429, got %d", w.Code)
+		}
+		if w.Header().Get("X-RateLimit-Limit") != "1" {
+			t.Errorf("Expected route limit 1, got %q", w.Header().Get("X-RateLimit-Limit"))
+		}
+
+		// 其他路由不受影響
+		if w := doRateLimitRequest(r, http.MethodGet, "/items", "1"); w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("StrictPerRoute", func(t *testing.T) {
+		if w := doRateLimitRequest(r, http.MethodPost, "/login", ""); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if w := doRateLimitRequest(r, http.MethodPost, "/login", ""); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected status 429, got %d", w.Code)
+		}
+		if w := doRateLimitRequest(r, http.MethodPost, "/register", ""); w.Code != http.StatusOK {
+			t.Errorf("Expected separate counter for /register, got %d", w.Code)
+		}
+	})
+}
+
+func TestRateLimiter_StoreUnavailable(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := newRedisRateLimitStore(t, server)
+	clock := ratelimit.SystemClock{}
+	r := newRateLimitTestRouter(NewRateLimiter(perMinute(store, clock, 1), perMinute(store, clock, 1), nil))
+	server.Close()
+
+	// store 無法使用時不阻擋請求
+	if w := doRateLimitRequest(r, http.MethodGet, "/items", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
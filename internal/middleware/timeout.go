@@ -1,45 +1,278 @@
 package middleware
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
+// noWritten 與 gin 相同，尚未寫入 header 時 Size() 回傳 -1
+const noWritten = -1
+
 // Timeout 設定請求超時
+// handler 在原本的 goroutine 執行（panic 仍由 Recovery 處理），回應先寫入緩衝區：
+// 在期限內完成時才送出；超過期限時立即回應 503，之後 handler 的寫入一律捨棄
+// request 的 context 在期限到時取消，handler 與 repository 應以 c.Request.Context() 呼叫下層以便提早結束
+//
+// 期限自請求開始計算。路由群組或單一路由可再套用 Timeout 宣告自己的預算，取代外層的期限（可延長或縮短）：
+//
+//	r.Use(middleware.Timeout(30 * time.Second))
+//	users.GET("/profile/export", middleware.Timeout(5*time.Minute), handler.ExportData)
 func Timeout(timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 建立帶有超時的 context
-		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
-		defer cancel()
-
-		// 替換 request 的 context
-		c.Request = c.Request.WithContext(ctx)
+		if tw, ok := c.Writer.(*timeoutWriter); ok {
+			c.Request = c.Request.WithContext(tw.setBudget(timeout))
+			c.Next()
+			return
+		}
 
-		// 用 channel 來處理請求完成
-		finished := make(chan struct{})
+		original := c.Writer
+		tw := newTimeoutWriter(c.Request.Context(), original, timeout)
+		defer tw.release()
+		c.Request = c.Request.WithContext(tw.context())
+		c.Writer = tw
 
-		go func() {
-			c.Next()
-			finished <- struct{}{}
+		defer func() {
+			if p := recover(); p != nil {
+				// 尚未超時時由 Recovery 直接回應 500；已超時則 Recovery 的回應與 handler 的寫入一樣被捨棄
+				if !tw.finish(false) {
+					c.Writer = original
+				}
+				panic(p)
+			}
 		}()
 
-		select {
-		case <-finished:
-			// 請求正常完成
-			return
-		case <-ctx.Done():
-			// 請求超時
-			c.JSON(http.StatusRequestTimeout, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "REQUEST_TIMEOUT",
-					"message": "Request timeout",
-				},
-			})
-			c.Abort()
+		c.Next()
+
+		tw.finish(true)
+		c.Writer = original
+	}
+}
+
+// timeoutWriter 緩衝 handler 的回應，確保 handler 與超時回應只有一方寫入原本的 writer
+// handler 只寫入緩衝區；原本的 writer 只在持有 mu 時由 finish（handler 結束時）或 timeout（期限到時）使用
+type timeoutWriter struct {
+	parent context.Context // 套用 Timeout 前的 context
+	start  time.Time
+	dst    gin.ResponseWriter
+
+	mu          sync.Mutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+	stop        func() bool
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+	done        bool
+}
+
+func newTimeoutWriter(parent context.Context, dst gin.ResponseWriter, timeout time.Duration) *timeoutWriter {
+	tw := &timeoutWriter{
+		parent: parent,
+		start:  time.Now(),
+		dst:    dst,
+		header: make(http.Header),
+	}
+	tw.arm(timeout)
+	return tw
+}
+
+// arm 以 start 起算的期限建立 context，期限到時立即回應，不必等 handler 結束；呼叫時需持有 mu 或尚未共用
+func (tw *timeoutWriter) arm(timeout time.Duration) {
+	tw.ctx, tw.cancel = context.WithDeadline(tw.parent, tw.start.Add(timeout))
+	tw.stop = context.AfterFunc(tw.ctx, tw.timeout)
+}
+
+// setBudget 改用新的期限，回傳新的 context；已超時則維持原狀
+func (tw *timeoutWriter) setBudget(timeout time.Duration) context.Context {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.done {
+		return tw.ctx
+	}
+	tw.stop()
+	tw.cancel()
+	tw.arm(timeout)
+	return tw.ctx
+}
+
+func (tw *timeoutWriter) context() context.Context {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	return tw.ctx
+}
+
+// release 請求結束時釋放計時器
+func (tw *timeoutWriter) release() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.stop()
+	tw.cancel()
+}
+
+var _ gin.ResponseWriter = (*timeoutWriter)(nil)
+
+// finish handler 結束後呼叫，之後不再送出超時回應；flush 為 true 且未超時時送出緩衝的回應
+// 期限已過時一律視為超時（handler 可能在 timeout 取得鎖之前就因 context 取消而結束），回傳是否已超時
+func (tw *timeoutWriter) finish(flush bool) bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if !tw.done && !tw.timedOut && tw.deadlineExceeded() {
+		tw.writeTimeout()
+	}
+	tw.done = true
+	if tw.timedOut || !flush {
+		return tw.timedOut
+	}
+
+	dst := tw.dst.Header()
+	for key, values := range tw.header {
+		dst[key] = values
+	}
+	if tw.status != 0 {
+		tw.dst.WriteHeader(tw.status)
+	}
+	if tw.wroteHeader {
+		tw.dst.WriteHeaderNow()
+		if tw.body.Len() > 0 {
+			_, _ = tw.dst.Write(tw.body.Bytes())
 		}
 	}
+	return false
+}
+
+// timeout context 結束時呼叫，超過期限且 handler 尚未結束時回應 503
+// 用戶端中斷連線等其他原因取消時不需要回應
+func (tw *timeoutWriter) timeout() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.done || tw.timedOut || !tw.deadlineExceeded() {
+		return
+	}
+	tw.writeTimeout()
+}
+
+// deadlineExceeded 除了 ctx.Err() 也直接比對期限，計時器稍晚觸發時仍以期限為準
+func (tw *timeoutWriter) deadlineExceeded() bool {
+	if errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		return true
+	}
+	deadline, ok := tw.ctx.Deadline()
+	return ok && !time.Now().Before(deadline)
+}
+
+// writeTimeout 送出超時回應，呼叫時需持有 mu
+func (tw *timeoutWriter) writeTimeout() {
+	tw.timedOut = true
+
+	// 不可使用 gin.Context（handler 可能仍在使用），直接寫入原本的 writer
+	body, _ := json.Marshal(utils.Response{
+		Success: false,
+		Error: &utils.ErrorDetail{
+			Code:    customerrors.CodeRequestTimeout,
+			Message: customerrors.MsgRequestTimeout,
+		},
+	})
+	tw.dst.Header().Set("Content-Type", "application/json; charset=utf-8")
+	tw.dst.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = tw.dst.Write(body)
+	tw.dst.Flush()
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if code > 0 && !tw.wroteHeader {
+		tw.status = code
+	}
+}
+
+func (tw *timeoutWriter) WriteHeaderNow() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.wroteHeader = true
+}
+
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.body.Write(data)
+}
+
+func (tw *timeoutWriter) WriteString(s string) (int, error) {
+	return tw.Write([]byte(s))
+}
+
+// Status 超時後回報實際送出的 503，而非 handler 設定的狀態碼
+func (tw *timeoutWriter) Status() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return http.StatusServiceUnavailable
+	}
+	if tw.status == 0 {
+		return http.StatusOK
+	}
+	return tw.status
+}
+
+func (tw *timeoutWriter) Size() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if !tw.wroteHeader {
+		return noWritten
+	}
+	return tw.body.Len()
+}
+
+func (tw *timeoutWriter) Written() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	return tw.wroteHeader
+}
+
+// Flush 回應在 handler 結束後才送出，緩衝期間無法 flush
+func (tw *timeoutWriter) Flush() {}
+
+func (tw *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("hijacking is not supported within the timeout middleware")
+}
+
+func (tw *timeoutWriter) CloseNotify() <-chan bool {
+	return tw.dst.CloseNotify()
+}
+
+func (tw *timeoutWriter) Pusher() http.Pusher {
+	return nil
 }
@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// 以 -race 執行可確認 handler 與超時回應之間沒有 data race：
+//
+//	go test -race ./internal/middleware -run Timeout
+
+const testTimeout = 20 * time.Millisecond
+
+// newTimeoutTestRouter 依正式環境的順序套用 Recovery 與 Timeout
+func newTimeoutTestRouter(handler gin.HandlerFunc) *gin.Engine {
+	r := gin.New()
+	r.Use(Recovery(zap.NewNop()), Timeout(testTimeout))
+	r.GET("/test", handler)
+	return r
+}
+
+func doTimeoutRequest(r http.Handler) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	return w
+}
+
+func decodeErrorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var resp utils.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", w.Body.String(), err)
+	}
+	if resp.Error == nil {
+		return ""
+	}
+	return resp.Error.Code
+}
+
+func TestTimeout_FastHandler(t *testing.T) {
+	r := newTimeoutTestRouter(func(c *gin.Context) {
+		c.Header("X-Test", "ok")
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	w := doTimeoutRequest(r)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", w.Code)
+	}
+	if w.Header().Get("X-Test") != "ok" {
+		t.Errorf("Expected header X-Test to be passed through, got %q", w.Header().Get("X-Test"))
+	}
+	if strings.TrimSpace(w.Body.String()) != `{"id":1}` {
+		t.Errorf("Unexpected body %q", w.Body.String())
+	}
+}
+
+func TestTimeout_StatusWithoutBody(t *testing.T) {
+	r := newTimeoutTestRouter(func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	if w := doTimeoutRequest(r); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+}
+
+func TestTimeout_SlowHandler(t *testing.T) {
+	t.Run("ObservesContext", func(t *testing.T) {
+		ctxErr := make(chan error, 1)
+		r := newTimeoutTestRouter(func(c *gin.Context) {
+			// 模擬等待資料庫查詢：context 取消時查詢隨之結束
+			<-c.Request.Context().Done()
+			ctxErr <- c.Request.Context().Err()
+			c.JSON(http.StatusOK, gin.H{"late": true})
+		})
+
+		w := doTimeoutRequest(r)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status 503, got %d", w.Code)
+		}
+		if code := decodeErrorCode(t, w); code != customerrors.CodeRequestTimeout {
+			t.Errorf("Expected error code %s, got %q", customerrors.CodeRequestTimeout, code)
+		}
+		if err := <-ctxErr; !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected handler to observe deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("WritesDuringTimeout", func(t *testing.T) {
+		writeErr := make(chan error, 1)
+		r := newTimeoutTestRouter(func(c *gin.Context) {
+			// 不理會 context 的 handler 在超時前後持續寫入，直到寫入失敗
+			var err error
+			for start := time.Now(); err == nil && time.Since(start) < time.Second; {
+				c.Header("X-Late", "1")
+				c.Status(http.StatusOK)
+				_, err = c.Writer.WriteString("late")
+				_ = c.Writer.Status()
+				_ = c.Writer.Written()
+			}
+			writeErr <- err
+		})
+
+		w := doTimeoutRequest(r)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status 503, got %d", w.Code)
+		}
+		if strings.Contains(w.Body.String(), "late") || w.Header().Get("X-Late") != "" {
+			t.Errorf("Expected handler output to be discarded, got %q %v", w.Body.String(), w.Header())
+		}
+		if err := <-writeErr; !errors.Is(err, http.ErrHandlerTimeout) {
+			t.Errorf("Expected ErrHandlerTimeout after timeout, got %v", err)
+		}
+	})
+}
+
+func TestTimeout_Panic(t *testing.T) {
+	t.Run("BeforeTimeout", func(t *testing.T) {
+		r := newTimeoutTestRouter(func(c *gin.Context) {
+			c.Header("X-Partial", "1")
+			panic("boom")
+		})
+
+		w := doTimeoutRequest(r)
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d", w.Code)
+		}
+		if code := decodeErrorCode(t, w); code != "PANIC_ERROR" {
+			t.Errorf("Expected error code PANIC_ERROR, got %q", code)
+		}
+		if w.Header().Get("X-Partial") != "" {
+			t.Error("Expected buffered headers to be discarded on panic")
+		}
+	})
+
+	t.Run("AfterTimeout", func(t *testing.T) {
+		r := newTimeoutTestRouter(func(c *gin.Context) {
+			<-c.Request.Context().Done()
+			panic("boom")
+		})
+
+		w := doTimeoutRequest(r)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status 503, got %d", w.Code)
+		}
+		if code := decodeErrorCode(t, w); code != customerrors.CodeRequestTimeout {
+			t.Errorf("Expected error code %s, got %q", customerrors.CodeRequestTimeout, code)
+		}
+	})
+}
+
+func TestTimeout_RouteBudget(t *testing.T) {
+	// 等待期限或指定時間，先到者為準
+	wait := func(d time.Duration) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			select {
+			case <-c.Request.Context().Done():
+			case <-time.After(d):
+			}
+			c.Status(http.StatusOK)
+		}
+	}
+
+	r := gin.New()
+	r.Use(Recovery(zap.NewNop()), Timeout(testTimeout))
+	r.GET("/default", wait(time.Second))
+	r.GET("/export", Timeout(time.Second), wait(2*testTimeout))
+	slow := r.Group("/slow", Timeout(time.Minute))
+	slow.GET("/health", Timeout(testTimeout/2), wait(time.Second))
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{name: "Default", path: "/default", status: http.StatusServiceUnavailable},
+		{name: "LongerBudget", path: "/export", status: http.StatusOK},
+		{name: "ShorterBudget", path: "/slow/health", status: http.StatusServiceUnavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if w.Code != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+)
+
+// revokedTokenStore 記憶體版本的撤銷清單（僅適用單一實例部署）
+type revokedTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]time.Time // jti -> token 過期時間
+}
+
+var _ contract.RevokedTokenStore = (*revokedTokenStore)(nil)
+
+func NewRevokedTokenStore() contract.RevokedTokenStore {
+	return &revokedTokenStore{
+		tokens: make(map[string]time.Time),
+	}
+}
+
+func (s *revokedTokenStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneExpired()
+	s.tokens[jti] = expiresAt
+	return nil
+}
+
+func (s *revokedTokenStore) Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneExpired()
+	if _, ok := s.tokens[jti]; ok {
+		return false, nil
+	}
+	s.tokens[jti] = expiresAt
+	return true, nil
+}
+
+func (s *revokedTokenStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.tokens[jti]
+	return ok, nil
+}
+
+// pruneExpired 清除已過期的項目，避免無限成長，呼叫前必須持有寫入鎖
+// 過期的 token 本身已無法通過驗證，不需要再記錄
+func (s *revokedTokenStore) pruneExpired() {
+	now := time.Now()
+	for id, exp := range s.tokens {
+		if now.After(exp) {
+			delete(s.tokens, id)
+		}
+	}
+}
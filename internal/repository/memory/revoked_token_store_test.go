@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRevokedTokenStore_Consume(t *testing.T) {
+	ctx := context.Background()
+	store := NewRevokedTokenStore().(*revokedTokenStore)
+
+	if ok, err := store.Consume(ctx, "first", time.Now().Add(time.Hour)); err != nil || !ok {
+		t.Fatalf("Expected first use to succeed, got %v, %v", ok, err)
+	}
+	// 同一個 jti 只能使用一次
+	if ok, _ := store.Consume(ctx, "first", time.Now().Add(time.Hour)); ok {
+		t.Error("Expected second use to be rejected")
+	}
+}
+
+func TestRevokedTokenStore_ConsumePrunesExpired(t *testing.T) {
+	ctx := context.Background()
+	store := NewRevokedTokenStore().(*revokedTokenStore)
+
+	if _, err := store.Consume(ctx, "expired", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if _, err := store.Consume(ctx, "active", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+
+	// 只使用 Consume 時過期的 jti 也要被清除
+	if _, ok := store.tokens["expired"]; ok {
+		t.Error("Expected expired jti to be pruned")
+	}
+	if _, ok := store.tokens["active"]; !ok {
+		t.Error("Expected active jti to be kept")
+	}
+}
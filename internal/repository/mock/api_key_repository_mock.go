@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// MockAPIKeyRepository 以記憶體模擬 API 金鑰
+type MockAPIKeyRepository struct {
+	Keys  []*entity.APIKey
+	Error error
+}
+
+func NewMockAPIKeyRepository() *MockAPIKeyRepository {
+	return &MockAPIKeyRepository{}
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	key.ID = int32(len(m.Keys) + 1)
+	key.CreatedAt = time.Now()
+	copied := *key
+	m.Keys = append(m.Keys, &copied)
+	return nil
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	for _, key := range m.Keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *MockAPIKeyRepository) ListByUser(ctx context.Context, userID int32) ([]*entity.APIKey, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	keys := []*entity.APIKey{}
+	for _, key := range m.Keys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			copied := *key
+			keys = append(keys, &copied)
+		}
+	}
+	return keys, nil
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id, userID int32) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	for _, key := range m.Keys {
+		if key.ID == id && key.UserID == userID && key.RevokedAt == nil {
+			now := time.Now()
+			key.RevokedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockAPIKeyRepository) RecordUsage(ctx context.Context, id int32, ip string) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	for _, key := range m.Keys {
+		if key.ID == id {
+			now := time.Now()
+			key.LastUsedAt = &now
+			key.LastUsedIP = ip
+		}
+	}
+	return nil
+}
+
+func (m *MockAPIKeyRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	keys := []*entity.APIKey{}
+	for _, key := range m.Keys {
+		if key.UserID == userID {
+			copied := *key
+			keys = append(keys, &copied)
+		}
+	}
+	return keys, nil
+}
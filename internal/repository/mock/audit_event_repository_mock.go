@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// MockAuditEventRepository 以記憶體保存稽核事件（依寫入順序）
+type MockAuditEventRepository struct {
+	Events []*entity.AuditEvent
+	Error  error
+}
+
+func NewMockAuditEventRepository() *MockAuditEventRepository {
+	return &MockAuditEventRepository{}
+}
+
+func (m *MockAuditEventRepository) Create(ctx context.Context, event *entity.AuditEvent) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	copied := *event
+	copied.ID = int64(len(m.Events) + 1)
+	copied.CreatedAt = time.Now()
+	m.Events = append(m.Events, &copied)
+	return nil
+}
+
+func (m *MockAuditEventRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	events := []*entity.AuditEvent{}
+	for _, event := range m.Events {
+		if event.UserID == userID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,115 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// MockMFARepository 以記憶體模擬兩步驟驗證設定與備用碼
+type MockMFARepository struct {
+	Settings      map[int32]*entity.UserMFA
+	RecoveryCodes map[int32]map[string]bool // userID -> code hash -> 是否已使用
+	Error         error
+}
+
+func NewMockMFARepository() *MockMFARepository {
+	return &MockMFARepository{
+		Settings:      make(map[int32]*entity.UserMFA),
+		RecoveryCodes: make(map[int32]map[string]bool),
+	}
+}
+
+func (m *MockMFARepository) Get(ctx context.Context, userID int32) (*entity.UserMFA, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	mfa, ok := m.Settings[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *mfa
+	return &copied, nil
+}
+
+func (m *MockMFARepository) Save(ctx context.Context, mfa *entity.UserMFA) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	now := time.Now()
+	mfa.EnabledAt = nil
+	mfa.LastUsedStep = 0
+	mfa.CreatedAt = now
+	mfa.UpdatedAt = now
+	copied := *mfa
+	m.Settings[mfa.UserID] = &copied
+	return nil
+}
+
+func (m *MockMFARepository) Enable(ctx context.Context, userID int32) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	mfa, ok := m.Settings[userID]
+	if !ok || mfa.EnabledAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	mfa.EnabledAt = &now
+	return true, nil
+}
+
+func (m *MockMFARepository) MarkStepUsed(ctx context.Context, userID int32, step int64) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	mfa, ok := m.Settings[userID]
+	if !ok || mfa.LastUsedStep >= step {
+		return false, nil
+	}
+	mfa.LastUsedStep = step
+	return true, nil
+}
+
+func (m *MockMFARepository) Delete(ctx context.Context, userID int32) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	delete(m.Settings, userID)
+	delete(m.RecoveryCodes, userID)
+	return nil
+}
+
+func (m *MockMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID int32, codeHashes []string) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	codes := make(map[string]bool, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		codes[codeHash] = false
+	}
+	m.RecoveryCodes[userID] = codes
+	return nil
+}
+
+func (m *MockMFARepository) UseRecoveryCode(ctx context.Context, userID int32, codeHash string) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	used, ok := m.RecoveryCodes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	m.RecoveryCodes[userID][codeHash] = true
+	return true, nil
+}
+
+func (m *MockMFARepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	mfa, err := m.Get(ctx, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return mfa, err
+}
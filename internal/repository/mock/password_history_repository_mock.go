@@ -0,0 +1,45 @@
+package mock
+
+import "context"
+
+// MockPasswordHistoryRepository 以記憶體模擬密碼歷史
+type MockPasswordHistoryRepository struct {
+	Hashes map[int32][]string // key: user ID，由舊到新
+	Error  error
+}
+
+func NewMockPasswordHistoryRepository() *MockPasswordHistoryRepository {
+	return &MockPasswordHistoryRepository{
+		Hashes: make(map[int32][]string),
+	}
+}
+
+func (m *MockPasswordHistoryRepository) Add(ctx context.Context, userID int32, passwordHash string) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	m.Hashes[userID] = append(m.Hashes[userID], passwordHash)
+	return nil
+}
+
+func (m *MockPasswordHistoryRepository) ListRecent(ctx context.Context, userID int32, limit int32) ([]string, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	hashes := m.Hashes[userID]
+	recent := []string{}
+	for i := len(hashes) - 1; i >= 0 && int32(len(recent)) < limit; i-- {
+		recent = append(recent, hashes[i])
+	}
+	return recent, nil
+}
+
+func (m *MockPasswordHistoryRepository) Prune(ctx context.Context, userID int32, keep int32) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	if hashes := m.Hashes[userID]; int32(len(hashes)) > keep {
+		m.Hashes[userID] = hashes[int32(len(hashes))-keep:]
+	}
+	return nil
+}
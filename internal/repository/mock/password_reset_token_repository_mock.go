@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// MockPasswordResetTokenRepository 以記憶體模擬密碼重設 token 儲存
+type MockPasswordResetTokenRepository struct {
+	Tokens map[string]*entity.PasswordResetToken // key: token hash
+	Error  error
+
+	nextID int32
+}
+
+func NewMockPasswordResetTokenRepository() *MockPasswordResetTokenRepository {
+	return &MockPasswordResetTokenRepository{
+		Tokens: make(map[string]*entity.PasswordResetToken),
+	}
+}
+
+func (m *MockPasswordResetTokenRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	m.nextID++
+	token.ID = m.nextID
+	token.CreatedAt = time.Now()
+	m.Tokens[token.TokenHash] = token
+	return nil
+}
+
+func (m *MockPasswordResetTokenRepository) GetValid(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	token, ok := m.Tokens[tokenHash]
+	if !ok || token.UsedAt != nil || !token.ExpiresAt.After(time.Now()) {
+		return nil, sql.ErrNoRows
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (m *MockPasswordResetTokenRepository) Consume(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	token, ok := m.Tokens[tokenHash]
+	if !ok || token.UsedAt != nil || !token.ExpiresAt.After(time.Now()) {
+		return nil, sql.ErrNoRows
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	copied := *token
+	return &copied, nil
+}
+
+func (m *MockPasswordResetTokenRepository) DeleteAllForUser(ctx context.Context, userID int32) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	for hash, token := range m.Tokens {
+		if token.UserID == userID {
+			delete(m.Tokens, hash)
+		}
+	}
+	return nil
+}
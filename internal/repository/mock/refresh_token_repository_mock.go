@@ -0,0 +1,81 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// MockRefreshTokenRepository 以記憶體模擬 refresh token 儲存
+type MockRefreshTokenRepository struct {
+	Tokens map[string]*entity.RefreshToken // key: token hash
+	Error  error
+
+	nextID int32
+}
+
+func NewMockRefreshTokenRepository() *MockRefreshTokenRepository {
+	return &MockRefreshTokenRepository{
+		Tokens: make(map[string]*entity.RefreshToken),
+	}
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	m.nextID++
+	token.ID = m.nextID
+	token.CreatedAt = time.Now()
+	m.Tokens[token.TokenHash] = token
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	token, ok := m.Tokens[tokenHash]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id int32) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	for _, token := range m.Tokens {
+		if token.ID == id && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return m.revokeWhere(func(token *entity.RefreshToken) bool { return token.FamilyID == familyID })
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int32) error {
+	return m.revokeWhere(func(token *entity.RefreshToken) bool { return token.UserID == userID })
+}
+
+func (m *MockRefreshTokenRepository) revokeWhere(match func(*entity.RefreshToken) bool) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	now := time.Now()
+	for _, token := range m.Tokens {
+		if match(token) && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// MockRoleRepository 以記憶體模擬角色與權限
+type MockRoleRepository struct {
+	UserRoles       map[int32][]string
+	RolePermissions map[string][]string
+	Error           error
+}
+
+// NewMockRoleRepository 建立包含預設角色（admin、user）的 mock
+func NewMockRoleRepository() *MockRoleRepository {
+	return &MockRoleRepository{
+		UserRoles: make(map[int32][]string),
+		RolePermissions: map[string][]string{
+			entity.RoleAdmin: {
+				entity.PermissionUsersDelete,
+				entity.PermissionUsersImpersonate,
+				entity.PermissionUsersList,
+				entity.PermissionUsersRestore,
+				entity.PermissionUsersUnlock,
+				entity.PermissionUsersUpdateRole,
+			},
+			entity.RoleUser: {},
+		},
+	}
+}
+
+func (m *MockRoleRepository) GetUserRoles(ctx context.Context, userID int32) ([]string, error) {
+	return m.UserRoles[userID], m.Error
+}
+
+func (m *MockRoleRepository) GetRolePermissions(ctx context.Context, roleName string) ([]string, error) {
+	return m.RolePermissions[roleName], m.Error
+}
+
+func (m *MockRoleRepository) AssignRole(ctx context.Context, userID int32, roleName string) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	if _, ok := m.RolePermissions[roleName]; !ok {
+		return sql.ErrNoRows
+	}
+	for _, role := range m.UserRoles[userID] {
+		if role == roleName {
+			return nil
+		}
+	}
+	m.UserRoles[userID] = append(m.UserRoles[userID], roleName)
+	return nil
+}
+
+func (m *MockRoleRepository) SetUserRole(ctx context.Context, userID int32, roleName string) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	if _, ok := m.RolePermissions[roleName]; !ok {
+		return sql.ErrNoRows
+	}
+	m.UserRoles[userID] = []string{roleName}
+	return nil
+}
+
+func (m *MockRoleRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	return m.GetUserRoles(ctx, userID)
+}
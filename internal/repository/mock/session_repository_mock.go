@@ -0,0 +1,146 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// MockSessionRepository 以記憶體模擬登入 session
+// ListActiveByUser 只依 session 本身判斷，不檢查 refresh token
+type MockSessionRepository struct {
+	Sessions map[string]*entity.UserSession // key: session ID
+	Error    error
+}
+
+func NewMockSessionRepository() *MockSessionRepository {
+	return &MockSessionRepository{
+		Sessions: make(map[string]*entity.UserSession),
+	}
+}
+
+func (m *MockSessionRepository) Create(ctx context.Context, session *entity.UserSession) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	now := time.Now()
+	session.CreatedAt = now
+	session.LastSeenAt = now
+	copied := *session
+	m.Sessions[session.ID] = &copied
+	return nil
+}
+
+func (m *MockSessionRepository) GetByID(ctx context.Context, id string) (*entity.UserSession, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	session, ok := m.Sessions[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (m *MockSessionRepository) ListActiveByUser(ctx context.Context, userID int32) ([]*entity.UserSession, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	sessions := []*entity.UserSession{}
+	for _, session := range m.Sessions {
+		if session.UserID == userID && session.IsActive(time.Now()) {
+			copied := *session
+			sessions = append(sessions, &copied)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+func (m *MockSessionRepository) Touch(ctx context.Context, id, ip string) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	if session, ok := m.Sessions[id]; ok {
+		session.LastSeenAt = time.Now()
+		session.IP = ip
+	}
+	return nil
+}
+
+func (m *MockSessionRepository) Extend(ctx context.Context, id, ip string, expiresAt time.Time) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	session, ok := m.Sessions[id]
+	if !ok {
+		return false, nil
+	}
+	session.LastSeenAt = time.Now()
+	session.IP = ip
+	session.ExpiresAt = expiresAt
+	return true, nil
+}
+
+func (m *MockSessionRepository) Revoke(ctx context.Context, id string, userID int32) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	session, ok := m.Sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	return true, nil
+}
+
+func (m *MockSessionRepository) RevokeAllForUser(ctx context.Context, userID int32) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	now := time.Now()
+	for _, session := range m.Sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *MockSessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	if m.Error != nil {
+		return 0, m.Error
+	}
+	var deleted int64
+	for id, session := range m.Sessions {
+		if !session.IsActive(time.Now()) {
+			delete(m.Sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *MockSessionRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	sessions := []*entity.UserSession{}
+	for _, session := range m.Sessions {
+		if session.UserID == userID {
+			copied := *session
+			sessions = append(sessions, &copied)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
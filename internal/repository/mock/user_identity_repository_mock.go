@@ -0,0 +1,71 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// MockUserIdentityRepository 以記憶體模擬外部身分連結
+type MockUserIdentityRepository struct {
+	Identities []*entity.UserIdentity
+	Error      error
+}
+
+func NewMockUserIdentityRepository() *MockUserIdentityRepository {
+	return &MockUserIdentityRepository{}
+}
+
+func (m *MockUserIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	identity.ID = int32(len(m.Identities) + 1)
+	identity.CreatedAt = time.Now()
+	copied := *identity
+	m.Identities = append(m.Identities, &copied)
+	return nil
+}
+
+func (m *MockUserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	for _, identity := range m.Identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *MockUserIdentityRepository) RecordLogin(ctx context.Context, id int32, email string) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	for _, identity := range m.Identities {
+		if identity.ID == id {
+			now := time.Now()
+			identity.Email = email
+			identity.LastLoginAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *MockUserIdentityRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	identities := []*entity.UserIdentity{}
+	for _, identity := range m.Identities {
+		if identity.UserID == userID {
+			copied := *identity
+			identities = append(identities, &copied)
+		}
+	}
+	return identities, nil
+}
@@ -2,21 +2,33 @@ package mock
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
 )
 
+// SimpleMockUserRepository 只存放單一用戶
+// User 已刪除（DeletedAt 不為 nil）時，查詢未刪除帳號的方法回傳 sql.ErrNoRows
 type SimpleMockUserRepository struct {
 	User  *entity.User
 	Error error
 
+	// ReservedUsernames 保留中的 username 與保留的用戶 ID（不模擬到期）
+	ReservedUsernames map[string]int32
+
 	// 用於更精確控制的函數
+	CreateFunc        func(ctx context.Context, user *entity.User) error
 	GetByIDFunc       func(ctx context.Context, id int32) (*entity.User, error)
 	GetByEmailFunc    func(ctx context.Context, email string) (*entity.User, error)
 	GetByUsernameFunc func(ctx context.Context, username string) (*entity.User, error)
+	UpdateFunc        func(ctx context.Context, user *entity.User) error
 }
 
 func (m *SimpleMockUserRepository) Create(ctx context.Context, user *entity.User) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, user)
+	}
 	return m.Error
 }
 
@@ -24,21 +36,21 @@ func (m *SimpleMockUserRepository) GetByID(ctx context.Context, id int32) (*enti
 	if m.GetByIDFunc != nil {
 		return m.GetByIDFunc(ctx, id)
 	}
-	return m.User, m.Error
+	return m.activeUser()
 }
 
 func (m *SimpleMockUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	if m.GetByEmailFunc != nil {
 		return m.GetByEmailFunc(ctx, email)
 	}
-	return m.User, m.Error
+	return m.activeUser()
 }
 
 func (m *SimpleMockUserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
 	if m.GetByUsernameFunc != nil {
 		return m.GetByUsernameFunc(ctx, username)
 	}
-	return m.User, m.Error
+	return m.activeUser()
 }
 
 func (m *SimpleMockUserRepository) List(ctx context.Context, limit, offset int32) ([]*entity.User, error) {
@@ -49,9 +61,188 @@ func (m *SimpleMockUserRepository) List(ctx context.Context, limit, offset int32
 }
 
 func (m *SimpleMockUserRepository) Update(ctx context.Context, user *entity.User) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, user)
+	}
 	return m.Error
 }
 
-func (m *SimpleMockUserRepository) Delete(ctx context.Context, id int32) error {
+func (m *SimpleMockUserRepository) UpdatePasswordHash(ctx context.Context, id int32, oldHash, newHash string) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	user, err := m.activeUser()
+	if err != nil || user == nil || user.ID != id || user.PasswordHash != oldHash {
+		return false, nil
+	}
+	user.PasswordHash = newHash
+	return true, nil
+}
+
+func (m *SimpleMockUserRepository) IncrementTokenVersion(ctx context.Context, id int32) (int32, error) {
+	if m.User != nil {
+		m.User.TokenVersion++
+		return m.User.TokenVersion, m.Error
+	}
+	return 0, m.Error
+}
+
+func (m *SimpleMockUserRepository) MarkEmailVerified(ctx context.Context, id int32, email string) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	if m.User == nil || m.User.Email != email || m.User.EmailVerifiedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	m.User.EmailVerifiedAt = &now
+	return true, nil
+}
+
+func (m *SimpleMockUserRepository) RecordLoginFailure(ctx context.Context, id int32) (int32, error) {
+	if m.User != nil {
+		m.User.FailedLoginAttempts++
+		return m.User.FailedLoginAttempts, m.Error
+	}
+	return 0, m.Error
+}
+
+func (m *SimpleMockUserRepository) Lock(ctx context.Context, id int32, until time.Time) error {
+	if m.User != nil {
+		m.User.LockedUntil = &until
+	}
+	return m.Error
+}
+
+func (m *SimpleMockUserRepository) ResetLoginFailures(ctx context.Context, id int32) error {
+	if m.User != nil {
+		m.User.FailedLoginAttempts = 0
+		m.User.LockedUntil = nil
+	}
 	return m.Error
 }
+
+func (m *SimpleMockUserRepository) SetPendingEmail(ctx context.Context, id int32, email string) error {
+	if m.User != nil {
+		m.User.PendingEmail = email
+	}
+	return m.Error
+}
+
+func (m *SimpleMockUserRepository) ConfirmEmailChange(ctx context.Context, id int32, email string) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	if m.User == nil || m.User.PendingEmail == "" || m.User.PendingEmail != email {
+		return false, nil
+	}
+	now := time.Now()
+	m.User.Email = m.User.PendingEmail
+	m.User.PendingEmail = ""
+	m.User.EmailVerifiedAt = &now
+	return true, nil
+}
+
+func (m *SimpleMockUserRepository) CancelEmailChange(ctx context.Context, id int32, originalEmail string) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	if m.User == nil || m.User.IsDeleted() {
+		return sql.ErrNoRows
+	}
+	if m.User.Email != originalEmail || m.User.EmailVerifiedAt == nil {
+		now := time.Now()
+		m.User.EmailVerifiedAt = &now
+	}
+	m.User.Email = originalEmail
+	m.User.PendingEmail = ""
+	return nil
+}
+
+func (m *SimpleMockUserRepository) ChangeUsername(ctx context.Context, id int32, oldUsername, newUsername string, reservedUntil time.Time) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	if m.User == nil || m.User.Username != oldUsername {
+		return sql.ErrNoRows
+	}
+	if m.ReservedUsernames == nil {
+		m.ReservedUsernames = map[string]int32{}
+	}
+	m.ReservedUsernames[oldUsername] = id
+	if m.ReservedUsernames[newUsername] == id {
+		delete(m.ReservedUsernames, newUsername)
+	}
+	now := time.Now()
+	m.User.Username = newUsername
+	m.User.UsernameChangedAt = &now
+	return nil
+}
+
+func (m *SimpleMockUserRepository) UsernameReserved(ctx context.Context, username string, exceptUserID int32) (bool, error) {
+	owner, ok := m.ReservedUsernames[username]
+	return ok && owner != exceptUserID, m.Error
+}
+
+func (m *SimpleMockUserRepository) SetAvatar(ctx context.Context, id int32, key string) error {
+	if m.User != nil {
+		m.User.AvatarKey = key
+		m.User.AvatarURL = ""
+	}
+	return m.Error
+}
+
+func (m *SimpleMockUserRepository) Delete(ctx context.Context, id int32) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	if m.User == nil || m.User.IsDeleted() {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	m.User.DeletedAt = &now
+	return nil
+}
+
+func (m *SimpleMockUserRepository) GetDeletedByID(ctx context.Context, id int32) (*entity.User, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	if m.User == nil || m.User.ID != id || !m.User.IsDeleted() {
+		return nil, sql.ErrNoRows
+	}
+	return m.User, nil
+}
+
+func (m *SimpleMockUserRepository) Restore(ctx context.Context, id int32) (*entity.User, error) {
+	user, err := m.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user.DeletedAt = nil
+	return user, nil
+}
+
+func (m *SimpleMockUserRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	if m.User == nil || !m.User.IsDeleted() || !m.User.DeletedAt.Before(deletedBefore) {
+		return nil, nil
+	}
+	avatarKey := m.User.AvatarKey
+	m.User = nil
+	return []string{avatarKey}, nil
+}
+
+func (m *SimpleMockUserRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	return m.GetByID(ctx, userID)
+}
+
+// activeUser 回傳未刪除的 User
+func (m *SimpleMockUserRepository) activeUser() (*entity.User, error) {
+	if m.Error == nil && m.User != nil && m.User.IsDeleted() {
+		return nil, sql.ErrNoRows
+	}
+	return m.User, m.Error
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+var _ contract.APIKeyRepository = (*apiKeyRepository)(nil)
+
+func NewAPIKeyRepository(db *sql.DB) contract.APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+func (r *apiKeyRepository) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	queries := r.getQueries(ctx)
+
+	params := sqlc.CreateAPIKeyParams{
+		UserID:  key.UserID,
+		Name:    key.Name,
+		Prefix:  key.Prefix,
+		KeyHash: key.KeyHash,
+		Scopes:  key.Scopes,
+	}
+	if params.Scopes == nil {
+		params.Scopes = []string{}
+	}
+	if key.ExpiresAt != nil {
+		params.ExpiresAt = sql.NullTime{Time: *key.ExpiresAt, Valid: true}
+	}
+
+	created, err := queries.CreateAPIKey(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	key.ID = created.ID
+	key.CreatedAt = created.CreatedAt
+	return nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	queries := r.getQueries(ctx)
+
+	row, err := queries.GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAPIKeyEntity(row), nil
+}
+
+// ExportUserData 匯出所有金鑰的資訊（包含已撤銷的），金鑰雜湊不會序列化
+func (r *apiKeyRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	queries := r.getQueries(ctx)
+
+	rows, err := queries.ExportUserAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*entity.APIKey, len(rows))
+	for i, row := range rows {
+		keys[i] = toAPIKeyEntity(row)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userID int32) ([]*entity.APIKey, error) {
+	queries := r.getQueries(ctx)
+
+	rows, err := queries.ListUserAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*entity.APIKey, len(rows))
+	for i, row := range rows {
+		keys[i] = toAPIKeyEntity(row)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id, userID int32) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.RevokeAPIKey(ctx, sqlc.RevokeAPIKeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *apiKeyRepository) RecordUsage(ctx context.Context, id int32, ip string) error {
+	queries := r.getQueries(ctx)
+	return queries.UpdateAPIKeyLastUsed(ctx, sqlc.UpdateAPIKeyLastUsedParams{
+		ID:         id,
+		LastUsedIp: sql.NullString{String: ip, Valid: ip != ""},
+	})
+}
+
+func toAPIKeyEntity(row sqlc.ApiKey) *entity.APIKey {
+	key := &entity.APIKey{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		KeyHash:    row.KeyHash,
+		Scopes:     row.Scopes,
+		LastUsedIP: row.LastUsedIp.String,
+		CreatedAt:  row.CreatedAt,
+	}
+	if row.ExpiresAt.Valid {
+		expiresAt := row.ExpiresAt.Time
+		key.ExpiresAt = &expiresAt
+	}
+	if row.LastUsedAt.Valid {
+		lastUsedAt := row.LastUsedAt.Time
+		key.LastUsedAt = &lastUsedAt
+	}
+	if row.RevokedAt.Valid {
+		revokedAt := row.RevokedAt.Time
+		key.RevokedAt = &revokedAt
+	}
+	return key
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type auditEventRepository struct {
+	db *sql.DB
+}
+
+var _ contract.AuditEventRepository = (*auditEventRepository)(nil)
+
+func NewAuditEventRepository(db *sql.DB) contract.AuditEventRepository {
+	return &auditEventRepository{
+		db: db,
+	}
+}
+
+func (r *auditEventRepository) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *auditEventRepository) Create(ctx context.Context, event *entity.AuditEvent) error {
+	queries := r.getQueries(ctx)
+
+	var actorID sql.NullInt32
+	if event.ActorID != nil {
+		actorID = sql.NullInt32{Int32: *event.ActorID, Valid: true}
+	}
+
+	return queries.CreateAuditEvent(ctx, sqlc.CreateAuditEventParams{
+		UserID:    event.UserID,
+		ActorID:   actorID,
+		Action:    event.Action,
+		Method:    event.Method,
+		Path:      event.Path,
+		Status:    int32(event.Status),
+		Ip:        event.IP,
+		RequestID: event.RequestID,
+	})
+}
+
+// ExportUserData 匯出影響該用戶的所有稽核事件
+func (r *auditEventRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	queries := r.getQueries(ctx)
+
+	rows, err := queries.ExportAuditEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*entity.AuditEvent, len(rows))
+	for i, row := range rows {
+		events[i] = toAuditEventEntity(row)
+	}
+	return events, nil
+}
+
+func toAuditEventEntity(row sqlc.AuditEvent) *entity.AuditEvent {
+	event := &entity.AuditEvent{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Action:    row.Action,
+		Method:    row.Method,
+		Path:      row.Path,
+		Status:    int(row.Status),
+		IP:        row.Ip,
+		RequestID: row.RequestID,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.ActorID.Valid {
+		actorID := row.ActorID.Int32
+		event.ActorID = &actorID
+	}
+	return event
+}
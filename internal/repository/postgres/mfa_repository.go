@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type mfaRepository struct {
+	db *sql.DB
+}
+
+var _ contract.MFARepository = (*mfaRepository)(nil)
+
+func NewMFARepository(db *sql.DB) contract.MFARepository {
+	return &mfaRepository{
+		db: db,
+	}
+}
+
+func (r *mfaRepository) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *mfaRepository) Get(ctx context.Context, userID int32) (*entity.UserMFA, error) {
+	queries := r.getQueries(ctx)
+
+	row, err := queries.GetUserMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserMFAEntity(row), nil
+}
+
+// ExportUserData 匯出兩步驟驗證的狀態（TOTP secret 不會序列化），未設定時回傳 nil
+func (r *mfaRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	mfa, err := r.Get(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return mfa, nil
+}
+
+func (r *mfaRepository) Save(ctx context.Context, mfa *entity.UserMFA) error {
+	queries := r.getQueries(ctx)
+
+	saved, err := queries.UpsertUserMFA(ctx, sqlc.UpsertUserMFAParams{
+		UserID: mfa.UserID,
+		Secret: mfa.Secret,
+	})
+	if err != nil {
+		return err
+	}
+
+	*mfa = *toUserMFAEntity(saved)
+	return nil
+}
+
+func (r *mfaRepository) Enable(ctx context.Context, userID int32) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.EnableUserMFA(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *mfaRepository) MarkStepUsed(ctx context.Context, userID int32, step int64) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.UpdateUserMFALastUsedStep(ctx, sqlc.UpdateUserMFALastUsedStepParams{
+		UserID:       userID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *mfaRepository) Delete(ctx context.Context, userID int32) error {
+	queries := r.getQueries(ctx)
+
+	if err := queries.DeleteUserMFARecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+	return queries.DeleteUserMFA(ctx, userID)
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID int32, codeHashes []string) error {
+	queries := r.getQueries(ctx)
+
+	if err := queries.DeleteUserMFARecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+	for _, codeHash := range codeHashes {
+		if err := queries.CreateMFARecoveryCode(ctx, sqlc.CreateMFARecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: codeHash,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID int32, codeHash string) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.UseMFARecoveryCode(ctx, sqlc.UseMFARecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: codeHash,
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func toUserMFAEntity(row sqlc.UserMfa) *entity.UserMFA {
+	mfa := &entity.UserMFA{
+		UserID:       row.UserID,
+		Secret:       row.Secret,
+		LastUsedStep: row.LastUsedStep,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+	if row.EnabledAt.Valid {
+		enabledAt := row.EnabledAt.Time
+		mfa.EnabledAt = &enabledAt
+	}
+	return mfa
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type passwordHistoryRepository struct {
+	db *sql.DB
+}
+
+var _ contract.PasswordHistoryRepository = (*passwordHistoryRepository)(nil)
+
+func NewPasswordHistoryRepository(db *sql.DB) contract.PasswordHistoryRepository {
+	return &passwordHistoryRepository{
+		db: db,
+	}
+}
+
+func (r *passwordHistoryRepository) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *passwordHistoryRepository) Add(ctx context.Context, userID int32, passwordHash string) error {
+	queries := r.getQueries(ctx)
+	return queries.CreatePasswordHistory(ctx, sqlc.CreatePasswordHistoryParams{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+	})
+}
+
+func (r *passwordHistoryRepository) ListRecent(ctx context.Context, userID int32, limit int32) ([]string, error) {
+	queries := r.getQueries(ctx)
+	return queries.ListRecentPasswordHashes(ctx, sqlc.ListRecentPasswordHashesParams{
+		UserID: userID,
+		Limit:  limit,
+	})
+}
+
+func (r *passwordHistoryRepository) Prune(ctx context.Context, userID int32, keep int32) error {
+	queries := r.getQueries(ctx)
+	return queries.PrunePasswordHistory(ctx, sqlc.PrunePasswordHistoryParams{
+		UserID: userID,
+		Limit:  keep,
+	})
+}
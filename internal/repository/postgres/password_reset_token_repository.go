@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type passwordResetTokenRepository struct {
+	db *sql.DB
+}
+
+var _ contract.PasswordResetTokenRepository = (*passwordResetTokenRepository)(nil)
+
+func NewPasswordResetTokenRepository(db *sql.DB) contract.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{
+		db: db,
+	}
+}
+
+func (r *passwordResetTokenRepository) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *passwordResetTokenRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	queries := r.getQueries(ctx)
+
+	created, err := queries.CreatePasswordResetToken(ctx, sqlc.CreatePasswordResetTokenParams{
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	token.ID = created.ID
+	token.CreatedAt = created.CreatedAt
+	return nil
+}
+
+func (r *passwordResetTokenRepository) GetValid(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	queries := r.getQueries(ctx)
+
+	row, err := queries.GetValidPasswordResetToken(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPasswordResetTokenEntity(row), nil
+}
+
+func (r *passwordResetTokenRepository) Consume(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	queries := r.getQueries(ctx)
+
+	row, err := queries.ConsumePasswordResetToken(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPasswordResetTokenEntity(row), nil
+}
+
+func (r *passwordResetTokenRepository) DeleteAllForUser(ctx context.Context, userID int32) error {
+	queries := r.getQueries(ctx)
+	return queries.DeleteUserPasswordResetTokens(ctx, userID)
+}
+
+func toPasswordResetTokenEntity(row sqlc.PasswordResetToken) *entity.PasswordResetToken {
+	token := &entity.PasswordResetToken{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		TokenHash: row.TokenHash,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.UsedAt.Valid {
+		usedAt := row.UsedAt.Time
+		token.UsedAt = &usedAt
+	}
+	return token
+}
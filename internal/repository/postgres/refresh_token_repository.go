@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type refreshTokenRepository struct {
+	db *sql.DB
+}
+
+var _ contract.RefreshTokenRepository = (*refreshTokenRepository)(nil)
+
+func NewRefreshTokenRepository(db *sql.DB) contract.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db: db,
+	}
+}
+
+func (r *refreshTokenRepository) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	queries := r.getQueries(ctx)
+
+	created, err := queries.CreateRefreshToken(ctx, sqlc.CreateRefreshTokenParams{
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		FamilyID:  token.FamilyID,
+		ExpiresAt: token.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	token.ID = created.ID
+	token.CreatedAt = created.CreatedAt
+	return nil
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	queries := r.getQueries(ctx)
+
+	row, err := queries.GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRefreshTokenEntity(row), nil
+}
+
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id int32) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.RevokeRefreshToken(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	queries := r.getQueries(ctx)
+	return queries.RevokeRefreshTokenFamily(ctx, familyID)
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int32) error {
+	queries := r.getQueries(ctx)
+	return queries.RevokeUserRefreshTokens(ctx, userID)
+}
+
+func toRefreshTokenEntity(row sqlc.RefreshToken) *entity.RefreshToken {
+	token := &entity.RefreshToken{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		TokenHash: row.TokenHash,
+		FamilyID:  row.FamilyID,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.RevokedAt.Valid {
+		revokedAt := row.RevokedAt.Time
+		token.RevokedAt = &revokedAt
+	}
+	return token
+}
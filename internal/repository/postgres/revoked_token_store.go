@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type revokedTokenStore struct {
+	db *sql.DB
+}
+
+var _ contract.RevokedTokenStore = (*revokedTokenStore)(nil)
+
+func NewRevokedTokenStore(db *sql.DB) contract.RevokedTokenStore {
+	return &revokedTokenStore{
+		db: db,
+	}
+}
+
+func (r *revokedTokenStore) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *revokedTokenStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	queries := r.getQueries(ctx)
+
+	if err := queries.RevokeToken(ctx, sqlc.RevokeTokenParams{
+		Jti:       jti,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return err
+	}
+
+	// 順便清除已過期的項目，避免資料表無限成長
+	return queries.DeleteExpiredRevokedTokens(ctx)
+}
+
+func (r *revokedTokenStore) Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	rows, err := queries.ConsumeToken(ctx, sqlc.ConsumeTokenParams{
+		Jti:       jti,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *revokedTokenStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	queries := r.getQueries(ctx)
+	return queries.IsTokenRevoked(ctx, jti)
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+var _ contract.RoleRepository = (*roleRepository)(nil)
+
+func NewRoleRepository(db *sql.DB) contract.RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+func (r *roleRepository) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *roleRepository) GetUserRoles(ctx context.Context, userID int32) ([]string, error) {
+	queries := r.getQueries(ctx)
+	return queries.ListUserRoleNames(ctx, userID)
+}
+
+// ExportUserData 匯出用戶的角色名稱
+func (r *roleRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	return r.GetUserRoles(ctx, userID)
+}
+
+func (r *roleRepository) GetRolePermissions(ctx context.Context, roleName string) ([]string, error) {
+	queries := r.getQueries(ctx)
+	return queries.ListRolePermissionNames(ctx, roleName)
+}
+
+func (r *roleRepository) AssignRole(ctx context.Context, userID int32, roleName string) error {
+	queries := r.getQueries(ctx)
+
+	role, err := queries.GetRoleByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	return queries.AddUserRole(ctx, sqlc.AddUserRoleParams{
+		UserID: userID,
+		RoleID: role.ID,
+	})
+}
+
+func (r *roleRepository) SetUserRole(ctx context.Context, userID int32, roleName string) error {
+	queries := r.getQueries(ctx)
+
+	role, err := queries.GetRoleByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	// 刪除與新增在同一個 SQL 敘述中完成
+	return queries.ReplaceUserRoles(ctx, sqlc.ReplaceUserRolesParams{
+		UserID: userID,
+		RoleID: role.ID,
+	})
+}
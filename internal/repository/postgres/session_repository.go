@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type sessionRepository struct {
+	db *sql.DB
+}
+
+var _ contract.SessionRepository = (*sessionRepository)(nil)
+
+func NewSessionRepository(db *sql.DB) contract.SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (r *sessionRepository) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *entity.UserSession) error {
+	queries := r.getQueries(ctx)
+
+	created, err := queries.CreateUserSession(ctx, sqlc.CreateUserSessionParams{
+		ID:        session.ID,
+		UserID:    session.UserID,
+		UserAgent: session.UserAgent,
+		Ip:        session.IP,
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	session.CreatedAt = created.CreatedAt
+	session.LastSeenAt = created.LastSeenAt
+	return nil
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id string) (*entity.UserSession, error) {
+	queries := r.getQueries(ctx)
+
+	row, err := queries.GetUserSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserSessionEntity(row), nil
+}
+
+// ExportUserData 匯出所有 session，包含已過期與已撤銷但尚未清除的
+func (r *sessionRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	queries := r.getQueries(ctx)
+
+	rows, err := queries.ExportUserSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entity.UserSession, len(rows))
+	for i, row := range rows {
+		sessions[i] = toUserSessionEntity(row)
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) ListActiveByUser(ctx context.Context, userID int32) ([]*entity.UserSession, error) {
+	queries := r.getQueries(ctx)
+
+	rows, err := queries.ListActiveUserSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entity.UserSession, len(rows))
+	for i, row := range rows {
+		sessions[i] = toUserSessionEntity(row)
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, id, ip string) error {
+	queries := r.getQueries(ctx)
+	return queries.TouchUserSession(ctx, sqlc.TouchUserSessionParams{
+		ID: id,
+		Ip: ip,
+	})
+}
+
+func (r *sessionRepository) Extend(ctx context.Context, id, ip string, expiresAt time.Time) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.ExtendUserSession(ctx, sqlc.ExtendUserSessionParams{
+		ID:        id,
+		Ip:        ip,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, id string, userID int32) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.RevokeUserSession(ctx, sqlc.RevokeUserSessionParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *sessionRepository) RevokeAllForUser(ctx context.Context, userID int32) error {
+	queries := r.getQueries(ctx)
+	return queries.RevokeAllUserSessions(ctx, userID)
+}
+
+func (r *sessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	queries := r.getQueries(ctx)
+	return queries.DeleteExpiredUserSessions(ctx)
+}
+
+func toUserSessionEntity(row sqlc.UserSession) *entity.UserSession {
+	session := &entity.UserSession{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		UserAgent:  row.UserAgent,
+		IP:         row.Ip,
+		CreatedAt:  row.CreatedAt,
+		LastSeenAt: row.LastSeenAt,
+		ExpiresAt:  row.ExpiresAt,
+	}
+	if row.RevokedAt.Valid {
+		revokedAt := row.RevokedAt.Time
+		session.RevokedAt = &revokedAt
+	}
+	return session
+}
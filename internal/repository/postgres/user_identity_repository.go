@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/db/sqlc"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+)
+
+type userIdentityRepository struct {
+	db *sql.DB
+}
+
+var _ contract.UserIdentityRepository = (*userIdentityRepository)(nil)
+
+func NewUserIdentityRepository(db *sql.DB) contract.UserIdentityRepository {
+	return &userIdentityRepository{
+		db: db,
+	}
+}
+
+func (r *userIdentityRepository) getQueries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := database.GetTx(ctx); ok {
+		return sqlc.New(tx)
+	}
+	return sqlc.New(r.db)
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	queries := r.getQueries(ctx)
+
+	created, err := queries.CreateUserIdentity(ctx, sqlc.CreateUserIdentityParams{
+		UserID:   identity.UserID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return err
+	}
+
+	identity.ID = created.ID
+	identity.CreatedAt = created.CreatedAt
+	return nil
+}
+
+func (r *userIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	queries := r.getQueries(ctx)
+
+	row, err := queries.GetUserIdentity(ctx, sqlc.GetUserIdentityParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserIdentityEntity(row), nil
+}
+
+// ExportUserData 匯出已連結的外部身分
+func (r *userIdentityRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	queries := r.getQueries(ctx)
+
+	rows, err := queries.ListUserIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]*entity.UserIdentity, len(rows))
+	for i, row := range rows {
+		identities[i] = toUserIdentityEntity(row)
+	}
+	return identities, nil
+}
+
+func (r *userIdentityRepository) RecordLogin(ctx context.Context, id int32, email string) error {
+	queries := r.getQueries(ctx)
+	return queries.UpdateUserIdentityLogin(ctx, sqlc.UpdateUserIdentityLoginParams{
+		ID:    id,
+		Email: email,
+	})
+}
+
+func toUserIdentityEntity(row sqlc.UserIdentity) *entity.UserIdentity {
+	identity := &entity.UserIdentity{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Provider:  row.Provider,
+		Subject:   row.Subject,
+		Email:     row.Email,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.LastLoginAt.Valid {
+		lastLoginAt := row.LastLoginAt.Time
+		identity.LastLoginAt = &lastLoginAt
+	}
+	return identity
+}
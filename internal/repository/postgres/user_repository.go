@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/dinosaur1258/GolangFramework/db/sqlc"
 	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
@@ -50,6 +51,7 @@ func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 	user.ID = createdUser.ID
 	user.CreatedAt = createdUser.CreatedAt
 	user.UpdatedAt = createdUser.UpdatedAt
+	user.TokenVersion = createdUser.TokenVersion
 
 	return nil
 }
@@ -62,14 +64,7 @@ func (r *userRepository) GetByID(ctx context.Context, id int32) (*entity.User, e
 		return nil, err
 	}
 
-	return &entity.User{
-		ID:           sqlcUser.ID,
-		Username:     sqlcUser.Username,
-		Email:        sqlcUser.Email,
-		PasswordHash: sqlcUser.PasswordHash,
-		CreatedAt:    sqlcUser.CreatedAt,
-		UpdatedAt:    sqlcUser.UpdatedAt,
-	}, nil
+	return toUserEntity(sqlcUser), nil
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
@@ -80,14 +75,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 		return nil, err
 	}
 
-	return &entity.User{
-		ID:           sqlcUser.ID,
-		Username:     sqlcUser.Username,
-		Email:        sqlcUser.Email,
-		PasswordHash: sqlcUser.PasswordHash,
-		CreatedAt:    sqlcUser.CreatedAt,
-		UpdatedAt:    sqlcUser.UpdatedAt,
-	}, nil
+	return toUserEntity(sqlcUser), nil
 }
 
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
@@ -98,14 +86,7 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*e
 		return nil, err
 	}
 
-	return &entity.User{
-		ID:           sqlcUser.ID,
-		Username:     sqlcUser.Username,
-		Email:        sqlcUser.Email,
-		PasswordHash: sqlcUser.PasswordHash,
-		CreatedAt:    sqlcUser.CreatedAt,
-		UpdatedAt:    sqlcUser.UpdatedAt,
-	}, nil
+	return toUserEntity(sqlcUser), nil
 }
 
 func (r *userRepository) List(ctx context.Context, limit, offset int32) ([]*entity.User, error) {
@@ -123,14 +104,7 @@ func (r *userRepository) List(ctx context.Context, limit, offset int32) ([]*enti
 
 	users := make([]*entity.User, len(sqlcUsers))
 	for i, sqlcUser := range sqlcUsers {
-		users[i] = &entity.User{
-			ID:           sqlcUser.ID,
-			Username:     sqlcUser.Username,
-			Email:        sqlcUser.Email,
-			PasswordHash: sqlcUser.PasswordHash,
-			CreatedAt:    sqlcUser.CreatedAt,
-			UpdatedAt:    sqlcUser.UpdatedAt,
-		}
+		users[i] = toUserEntity(sqlcUser)
 	}
 
 	return users, nil
@@ -144,6 +118,16 @@ func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 		Username:     user.Username,
 		Email:        user.Email,
 		PasswordHash: user.PasswordHash,
+
+		DisplayName:         nullString(user.DisplayName),
+		Bio:                 nullString(user.Bio),
+		Locale:              nullString(user.Locale),
+		Timezone:            nullString(user.Timezone),
+		AvatarUrl:           nullString(user.AvatarURL),
+		PublicProfileFields: user.PublicFields,
+	}
+	if params.PublicProfileFields == nil {
+		params.PublicProfileFields = []string{}
 	}
 
 	updatedUser, err := queries.UpdateUser(ctx, params)
@@ -155,7 +139,235 @@ func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	return nil
 }
 
+// UpdatePasswordHash 只寫入密碼雜湊，不會以舊的資料覆蓋同時間變更的其他欄位
+func (r *userRepository) UpdatePasswordHash(ctx context.Context, id int32, oldHash, newHash string) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.UpdateUserPasswordHash(ctx, sqlc.UpdateUserPasswordHashParams{
+		NewHash: newHash,
+		ID:      id,
+		OldHash: oldHash,
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// IncrementTokenVersion 遞增 token 版本，使該用戶所有既有的 access token 失效
+func (r *userRepository) IncrementTokenVersion(ctx context.Context, id int32) (int32, error) {
+	queries := r.getQueries(ctx)
+	return queries.IncrementUserTokenVersion(ctx, id)
+}
+
+// MarkEmailVerified 標記 Email 已驗證
+// 只在 Email 未變更且尚未驗證時生效，已驗證過則回傳 false
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id int32, email string) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.MarkUserEmailVerified(ctx, sqlc.MarkUserEmailVerifiedParams{
+		ID:    id,
+		Email: email,
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// RecordLoginFailure 遞增連續登入失敗次數（在資料庫中累加，避免併發請求互相覆蓋）
+func (r *userRepository) RecordLoginFailure(ctx context.Context, id int32) (int32, error) {
+	queries := r.getQueries(ctx)
+	return queries.RecordUserLoginFailure(ctx, id)
+}
+
+// Lock 鎖定帳號至指定時間
+func (r *userRepository) Lock(ctx context.Context, id int32, until time.Time) error {
+	queries := r.getQueries(ctx)
+	return queries.LockUser(ctx, sqlc.LockUserParams{
+		ID:          id,
+		LockedUntil: sql.NullTime{Time: until, Valid: true},
+	})
+}
+
+// ResetLoginFailures 清除登入失敗次數並解除鎖定
+func (r *userRepository) ResetLoginFailures(ctx context.Context, id int32) error {
+	queries := r.getQueries(ctx)
+	return queries.ResetUserLoginFailures(ctx, id)
+}
+
+func (r *userRepository) SetPendingEmail(ctx context.Context, id int32, email string) error {
+	queries := r.getQueries(ctx)
+	return queries.SetUserPendingEmail(ctx, sqlc.SetUserPendingEmailParams{
+		ID:           id,
+		PendingEmail: nullString(email),
+	})
+}
+
+// ConfirmEmailChange 新 Email 已由確認信驗證，一併標記為已驗證
+func (r *userRepository) ConfirmEmailChange(ctx context.Context, id int32, email string) (bool, error) {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.ConfirmUserEmailChange(ctx, sqlc.ConfirmUserEmailChangeParams{
+		ID:           id,
+		PendingEmail: nullString(email),
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// CancelEmailChange 開啟寄到原 Email 的取消連結即證明擁有原 Email，一併標記為已驗證
+func (r *userRepository) CancelEmailChange(ctx context.Context, id int32, originalEmail string) error {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.CancelUserEmailChange(ctx, sqlc.CancelUserEmailChangeParams{
+		ID:    id,
+		Email: originalEmail,
+	})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ChangeUsername 變更 username 與保留舊名稱在同一個 SQL 陳述式中完成
+func (r *userRepository) ChangeUsername(ctx context.Context, id int32, oldUsername, newUsername string, reservedUntil time.Time) error {
+	queries := r.getQueries(ctx)
+
+	affected, err := queries.ChangeUsername(ctx, sqlc.ChangeUsernameParams{
+		ReservedUntil: reservedUntil,
+		ID:            id,
+		OldUsername:   oldUsername,
+		NewUsername:   newUsername,
+	})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *userRepository) UsernameReserved(ctx context.Context, username string, exceptUserID int32) (bool, error) {
+	queries := r.getQueries(ctx)
+	return queries.IsUsernameReserved(ctx, sqlc.IsUsernameReservedParams{
+		Username: username,
+		UserID:   exceptUserID,
+	})
+}
+
+func (r *userRepository) SetAvatar(ctx context.Context, id int32, key string) error {
+	queries := r.getQueries(ctx)
+	return queries.SetUserAvatar(ctx, sqlc.SetUserAvatarParams{
+		ID:        id,
+		AvatarKey: nullString(key),
+	})
+}
+
+// Delete 軟刪除帳號，資料保留到 PurgeDeleted 永久刪除為止
 func (r *userRepository) Delete(ctx context.Context, id int32) error {
 	queries := r.getQueries(ctx) // 智能選擇
-	return queries.DeleteUser(ctx, id)
+
+	affected, err := queries.SoftDeleteUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *userRepository) GetDeletedByID(ctx context.Context, id int32) (*entity.User, error) {
+	queries := r.getQueries(ctx)
+
+	sqlcUser, err := queries.GetDeletedUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserEntity(sqlcUser), nil
+}
+
+func (r *userRepository) Restore(ctx context.Context, id int32) (*entity.User, error) {
+	queries := r.getQueries(ctx)
+
+	sqlcUser, err := queries.RestoreUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserEntity(sqlcUser), nil
+}
+
+// PurgeDeleted 永久刪除帳號，關聯資料由外鍵 ON DELETE CASCADE 一併刪除
+func (r *userRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	queries := r.getQueries(ctx)
+
+	avatarKeys, err := queries.PurgeDeletedUsers(ctx, sql.NullTime{Time: deletedBefore, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(avatarKeys))
+	for i, key := range avatarKeys {
+		keys[i] = key.String
+	}
+	return keys, nil
+}
+
+// ExportUserData 匯出帳號資料（密碼雜湊不會序列化）
+func (r *userRepository) ExportUserData(ctx context.Context, userID int32) (interface{}, error) {
+	return r.GetByID(ctx, userID)
+}
+
+// nullString 空字串存為 NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func toUserEntity(sqlcUser sqlc.User) *entity.User {
+	user := &entity.User{
+		ID:           sqlcUser.ID,
+		Username:     sqlcUser.Username,
+		Email:        sqlcUser.Email,
+		PasswordHash: sqlcUser.PasswordHash,
+		CreatedAt:    sqlcUser.CreatedAt,
+		UpdatedAt:    sqlcUser.UpdatedAt,
+		TokenVersion: sqlcUser.TokenVersion,
+
+		PendingEmail:        sqlcUser.PendingEmail.String,
+		FailedLoginAttempts: sqlcUser.FailedLoginAttempts,
+
+		DisplayName:  sqlcUser.DisplayName.String,
+		Bio:          sqlcUser.Bio.String,
+		Locale:       sqlcUser.Locale.String,
+		Timezone:     sqlcUser.Timezone.String,
+		AvatarURL:    sqlcUser.AvatarUrl.String,
+		AvatarKey:    sqlcUser.AvatarKey.String,
+		PublicFields: sqlcUser.PublicProfileFields,
+	}
+	if sqlcUser.EmailVerifiedAt.Valid {
+		verifiedAt := sqlcUser.EmailVerifiedAt.Time
+		user.EmailVerifiedAt = &verifiedAt
+	}
+	if sqlcUser.LockedUntil.Valid {
+		lockedUntil := sqlcUser.LockedUntil.Time
+		user.LockedUntil = &lockedUntil
+	}
+	if sqlcUser.UsernameChangedAt.Valid {
+		changedAt := sqlcUser.UsernameChangedAt.Time
+		user.UsernameChangedAt = &changedAt
+	}
+	if sqlcUser.DeletedAt.Valid {
+		deletedAt := sqlcUser.DeletedAt.Time
+		user.DeletedAt = &deletedAt
+	}
+	return user
 }
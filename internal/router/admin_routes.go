@@ -0,0 +1,26 @@
+package router
+
+import (
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/handler"
+	"github.com/dinosaur1258/GolangFramework/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAdminRoutes 設定管理員路由（全部需要認證與對應權限）
+func SetupAdminRoutes(rg *gin.RouterGroup, adminHandler *handler.AdminHandler, authMiddleware gin.HandlerFunc) {
+	admin := rg.Group("/admin")
+	admin.Use(authMiddleware)
+	{
+		users := admin.Group("/users")
+		{
+			users.PUT("/:id/role", middleware.RequirePermission(entity.PermissionUsersUpdateRole), adminHandler.UpdateUserRole) // 變更角色
+			users.POST("/:id/unlock", middleware.RequirePermission(entity.PermissionUsersUnlock), adminHandler.UnlockUser)      // 解除帳號鎖定
+			users.DELETE("/:id", middleware.RequirePermission(entity.PermissionUsersDelete), adminHandler.DeleteUser)           // 強制刪除用戶
+			users.POST("/:id/restore", middleware.RequirePermission(entity.PermissionUsersRestore), adminHandler.RestoreUser)   // 復原已刪除的用戶
+
+			// 代登入：不可在代登入期間再代登入其他用戶
+			users.POST("/:id/impersonate", middleware.RejectImpersonation(), middleware.RequirePermission(entity.PermissionUsersImpersonate), adminHandler.Impersonate)
+		}
+	}
+}
@@ -7,11 +7,51 @@ import (
 )
 
 // SetupAuthRoutes 設定認證相關路由
-func SetupAuthRoutes(rg *gin.RouterGroup, userHandler *handler.UserHandler, authHandler *handler.AuthHandler) {
+func SetupAuthRoutes(rg *gin.RouterGroup, userHandler *handler.UserHandler, authHandler *handler.AuthHandler, mfaHandler *handler.MFAHandler, oidcHandler *handler.OIDCHandler, authMiddleware, strictRateLimit gin.HandlerFunc) {
 	auth := rg.Group("/auth")
 	{
-		// 註冊和登入使用嚴格限流（每分鐘 10 次）
-		auth.POST("/register", middleware.RateLimitStrict(), authHandler.Register)
-		auth.POST("/login", middleware.RateLimitStrict(), authHandler.Login)
+		// 註冊和登入使用嚴格限流（每個路由各自計數）
+		auth.POST("/register", strictRateLimit, authHandler.Register)
+		auth.POST("/login", strictRateLimit, authHandler.Login)
+
+		// Email 驗證（重新寄送使用嚴格限流，避免被用來大量寄信）
+		auth.POST("/verify-email", strictRateLimit, authHandler.VerifyEmail)
+		auth.POST("/resend-verification", strictRateLimit, authHandler.ResendVerification)
+
+		// 變更 Email：確認連結寄到新 Email，取消連結寄到原 Email（申請變更在 /users/profile/email）
+		auth.POST("/email-change/confirm", strictRateLimit, userHandler.ConfirmEmailChange)
+		auth.POST("/email-change/cancel", strictRateLimit, userHandler.CancelEmailChange)
+
+		// 忘記密碼 / 重設密碼
+		auth.POST("/forgot-password", strictRateLimit, authHandler.ForgotPassword)
+		auth.POST("/reset-password", strictRateLimit, authHandler.ResetPassword)
+
+		// 免密碼登入連結（另外在 usecase 以 Email 限制寄送次數）
+		auth.POST("/magic-link", strictRateLimit, authHandler.RequestMagicLink)
+		auth.POST("/magic-link/consume", strictRateLimit, authHandler.ConsumeMagicLink)
+
+		// 兩步驟驗證（驗證碼相關操作使用嚴格限流）
+		mfa := auth.Group("/mfa")
+		{
+			mfa.POST("/verify", strictRateLimit, authHandler.VerifyMFA) // 登入第二步
+			mfa.POST("/setup", authMiddleware, middleware.RejectAPIKey(), middleware.RejectImpersonation(), mfaHandler.Setup)
+			mfa.POST("/enable", authMiddleware, middleware.RejectAPIKey(), middleware.RejectImpersonation(), strictRateLimit, mfaHandler.Enable)
+			mfa.POST("/disable", authMiddleware, middleware.RejectAPIKey(), middleware.RejectImpersonation(), strictRateLimit, mfaHandler.Disable)
+			mfa.POST("/recovery-codes", authMiddleware, middleware.RejectAPIKey(), middleware.RejectImpersonation(), strictRateLimit, mfaHandler.RegenerateRecoveryCodes)
+		}
+
+		// 外部身分登入（OpenID Connect）
+		oidc := auth.Group("/oidc/:provider")
+		{
+			oidc.GET("/login", oidcHandler.Login)
+			oidc.GET("/callback", strictRateLimit, oidcHandler.Callback)
+		}
+
+		// 以 refresh token 換發新的 token
+		auth.POST("/refresh", authHandler.Refresh)
+
+		// 登出（需要認證）
+		auth.POST("/logout", authMiddleware, authHandler.Logout)
+		auth.POST("/logout-all", authMiddleware, middleware.RejectImpersonation(), authHandler.LogoutAll)
 	}
 }
@@ -1,8 +1,10 @@
 package router
 
 import (
+	"net/http"
 	"time"
 
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
 	"github.com/dinosaur1258/GolangFramework/internal/handler"
 	"github.com/dinosaur1258/GolangFramework/internal/middleware"
 	"github.com/dinosaur1258/GolangFramework/internal/service"
@@ -13,27 +15,67 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// 請求超時預算（自請求開始計算）
+// 全域使用 defaultRequestTimeout，路由群組或單一路由以 middleware.Timeout 宣告自己的預算（取代全域的期限）
+const (
+	defaultRequestTimeout = 30 * time.Second
+	healthCheckTimeout    = 2 * time.Second // 負載平衡器的健康檢查應快速失敗
+	uploadTimeout         = 2 * time.Minute // 上傳需要讀取請求內容並處理圖片
+	exportTimeout         = 5 * time.Minute // 匯出需要查詢大量資料
+)
+
 // SetupRouter 設定主路由
-func SetupRouter(userHandler *handler.UserHandler, authHandler *handler.AuthHandler, jwtService *service.JWTService) *gin.Engine {
+func SetupRouter(
+	userHandler *handler.UserHandler,
+	authHandler *handler.AuthHandler,
+	mfaHandler *handler.MFAHandler,
+	oidcHandler *handler.OIDCHandler,
+	adminHandler *handler.AdminHandler,
+	apiKeyHandler *handler.APIKeyHandler,
+	sessionHandler *handler.SessionHandler,
+	jwksHandler *handler.JWKSHandler,
+	media http.Handler, // 本機儲存的檔案下載（使用 S3 時為 nil）
+	jwtService *service.JWTService,
+	tokenRevocation *service.TokenRevocationService,
+	authorization *service.AuthorizationService,
+	apiKeys *service.APIKeyService,
+	sessions *service.SessionService,
+	auditEvents contract.AuditEventRepository,
+	rateLimiter *middleware.RateLimiter,
+) *gin.Engine {
 	r := gin.New()
 
+	// 認證中間件（各模組共用）
+	authMiddleware := middleware.AuthMiddleware(jwtService, tokenRevocation, authorization, apiKeys, sessions)
+
 	// 全域中間件（按順序執行）
-	r.Use(middleware.Recovery(logger.Log))      // 1. Panic 恢復（整合日誌）
-	r.Use(middleware.RequestID())               // 2. Request ID
-	r.Use(middleware.RequestLogger(logger.Log)) // 3. 請求日誌（取代 gin.Logger()）
-	r.Use(middleware.CORS())                    // 4. CORS
-	r.Use(middleware.Timeout(30 * time.Second)) // 5. 超時控制
-	r.Use(middleware.ErrorHandler(logger.Log))  // 6. 錯誤處理（整合日誌）
+	r.Use(middleware.Recovery(logger.Log))                        // 1. Panic 恢復（整合日誌）
+	r.Use(middleware.RequestID())                                 // 2. Request ID
+	r.Use(middleware.RequestLogger(logger.Log))                   // 3. 請求日誌（取代 gin.Logger()）
+	r.Use(middleware.AuditImpersonation(auditEvents, logger.Log)) // 4. 代登入的請求寫入稽核事件
+	r.Use(middleware.CORS())                                      // 5. CORS
+	r.Use(middleware.Timeout(defaultRequestTimeout))              // 6. 超時控制（預設預算）
+	r.Use(middleware.ErrorHandler(logger.Log))                    // 7. 錯誤處理（整合日誌）
 
 	// Swagger 文檔路由
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// JWT 驗證公鑰（供其他服務驗證 token）
+	r.GET("/.well-known/jwks.json", jwksHandler.JWKS)
+
+	// 上傳檔案下載（簽章網址）
+	if media != nil {
+		r.GET("/media/*key", gin.WrapH(http.StripPrefix("/media", media)))
+	}
+
 	// API v1 群組
 	v1 := r.Group("/api/v1")
-	v1.Use(middleware.RateLimit()) // API 群組使用一般限流
+	// 先解析認證資訊，已登入的請求依用戶限流，其餘依 IP
+	v1.Use(middleware.OptionalAuth(jwtService, tokenRevocation, authorization, apiKeys, sessions))
+	v1.Use(rateLimiter.Default()) // API 群組使用一般限流
 	{
 		// 健康檢查
-		v1.GET("/health", func(c *gin.Context) {
+		v1.GET("/health", middleware.Timeout(healthCheckTimeout), func(c *gin.Context) {
 			c.JSON(200, gin.H{
 				"status":  "ok",
 				"message": "Server is running",
@@ -41,8 +83,9 @@ func SetupRouter(userHandler *handler.UserHandler, authHandler *handler.AuthHand
 		})
 
 		// 註冊各模組路由
-		SetupAuthRoutes(v1, userHandler, authHandler)
-		SetupUserRoutes(v1, userHandler, jwtService)
+		SetupAuthRoutes(v1, userHandler, authHandler, mfaHandler, oidcHandler, authMiddleware, rateLimiter.Strict())
+		SetupUserRoutes(v1, userHandler, apiKeyHandler, sessionHandler, authMiddleware)
+		SetupAdminRoutes(v1, adminHandler, authMiddleware)
 	}
 
 	return r
@@ -1,14 +1,14 @@
 package router
 
 import (
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
 	"github.com/dinosaur1258/GolangFramework/internal/handler"
 	"github.com/dinosaur1258/GolangFramework/internal/middleware"
-	"github.com/dinosaur1258/GolangFramework/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 // SetupUserRoutes 設定用戶相關路由
-func SetupUserRoutes(rg *gin.RouterGroup, userHandler *handler.UserHandler, jwtService *service.JWTService) {
+func SetupUserRoutes(rg *gin.RouterGroup, userHandler *handler.UserHandler, apiKeyHandler *handler.APIKeyHandler, sessionHandler *handler.SessionHandler, authMiddleware gin.HandlerFunc) {
 	users := rg.Group("/users")
 	{
 		// 公開路由：查看用戶資料
@@ -16,18 +16,41 @@ func SetupUserRoutes(rg *gin.RouterGroup, userHandler *handler.UserHandler, jwtS
 
 		// 需要認證的路由
 		protected := users.Group("")
-		protected.Use(middleware.AuthMiddleware(jwtService))
+		protected.Use(authMiddleware)
 		{
 			// 個人資料管理
-			protected.GET("/profile", userHandler.GetProfile)    // 取得個人資料
-			protected.PUT("/profile", userHandler.UpdateProfile) // 更新個人資料
-			protected.DELETE("/profile", userHandler.DeleteUser) // 刪除帳號
+			protected.GET("/profile", userHandler.GetProfile)                                                                             // 取得個人資料
+			protected.PATCH("/profile", middleware.RejectAPIKey(), userHandler.UpdateProfile)                                             // 更新個人資料（未提供的欄位不變）
+			protected.PUT("/profile", middleware.RejectAPIKey(), userHandler.UpdateProfile)                                               // 同 PATCH（保留相容）
+			protected.DELETE("/profile", middleware.RejectAPIKey(), middleware.RejectImpersonation(), userHandler.DeleteUser)             // 刪除帳號
+			protected.POST("/profile/email", middleware.RejectAPIKey(), middleware.RejectImpersonation(), userHandler.RequestEmailChange) // 變更 Email（需由新 Email 確認）
+
+			// 上傳與匯出耗時較長，使用自己的超時預算
+			protected.PUT("/profile/avatar", middleware.Timeout(uploadTimeout), middleware.RejectAPIKey(), userHandler.UploadAvatar)                                 // 上傳頭像
+			protected.GET("/profile/export", middleware.Timeout(exportTimeout), middleware.RejectAPIKey(), middleware.RejectImpersonation(), userHandler.ExportData) // 匯出個人資料
 
 			// 密碼管理
-			protected.PUT("/password", userHandler.ChangePassword) // 修改密碼
+			protected.PUT("/password", middleware.RejectAPIKey(), middleware.RejectImpersonation(), userHandler.ChangePassword) // 修改密碼
+
+			// 已登入的裝置
+			sessions := protected.Group("/sessions")
+			sessions.Use(middleware.RejectAPIKey())
+			{
+				sessions.GET("", sessionHandler.List)
+				sessions.DELETE("/:id", middleware.RejectImpersonation(), sessionHandler.Revoke)
+			}
+
+			// 個人 API 金鑰（只能以登入 token 管理，避免外洩的金鑰建立新金鑰）
+			apiKeys := protected.Group("/api-keys")
+			apiKeys.Use(middleware.RejectAPIKey(), middleware.RejectImpersonation())
+			{
+				apiKeys.GET("", apiKeyHandler.List)
+				apiKeys.POST("", apiKeyHandler.Create)
+				apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+			}
 
 			// 用戶列表（管理用）
-			protected.GET("", userHandler.ListUsers) // 列出所有用戶
+			protected.GET("", middleware.RequirePermission(entity.PermissionUsersList), userHandler.ListUsers) // 列出所有用戶
 		}
 	}
 }
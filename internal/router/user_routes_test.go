@@ -0,0 +1,51 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+func TestSetupUserRoutes_RejectsAPIKeyOnProfileWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 模擬以沒有任何 scope 的 API 金鑰認證
+	apiKeyAuth := func(c *gin.Context) {
+		c.Set("user_id", int32(1))
+		c.Set("permissions", []string{})
+		c.Set("api_key_id", int32(1))
+		c.Next()
+	}
+
+	router := gin.New()
+	SetupUserRoutes(router.Group(""), nil, nil, nil, apiKeyAuth)
+
+	testCases := []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodPatch, path: "/users/profile"},
+		{method: http.MethodPut, path: "/users/profile"},
+		{method: http.MethodPut, path: "/users/profile/avatar"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"display_name":"hijacked"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusForbidden {
+				t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), customerrors.CodeAPIKeyNotAllowed) {
+				t.Errorf("Expected error code %s, got %s", customerrors.CodeAPIKeyNotAllowed, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Action token 用途（寫入 audience，避免不同用途的 token 被混用）
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposeMFAPending        = "mfa_pending"         // 已通過密碼驗證、等待兩步驟驗證碼
+	PurposeMagicLink         = "magic_link"          // 免密碼登入連結
+	PurposeEmailChange       = "email_change"        // 寄到新 Email 的確認連結，Email 為新 Email
+	PurposeEmailChangeCancel = "email_change_cancel" // 寄到原 Email 的取消連結，Email 為原 Email
+)
+
+var (
+	ErrInvalidActionToken = errors.New("invalid action token")
+	ErrActionTokenExpired = errors.New("action token expired")
+)
+
+// ActionTokenService 簽發一次性操作用的短效 token（例如 Email 驗證連結）
+// 使用獨立的 HMAC 密鑰，與 access token 分開
+type ActionTokenService struct {
+	secretKey []byte
+}
+
+// ActionClaims action token 內容
+// Email 用來綁定簽發當下的 Email，變更 Email 後舊 token 即失效
+type ActionClaims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+func NewActionTokenService(secretKey string) *ActionTokenService {
+	return &ActionTokenService{
+		secretKey: []byte(secretKey),
+	}
+}
+
+// GenerateToken 簽發指定用途的 token
+func (s *ActionTokenService) GenerateToken(purpose string, userID int32, email string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := ActionClaims{
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   strconv.FormatInt(int64(userID), 10),
+			Audience:  jwt.ClaimStrings{purpose},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.secretKey)
+}
+
+// ValidateToken 驗證 token 的簽章、用途與效期，回傳 userID 與 claims
+func (s *ActionTokenService) ValidateToken(tokenString, purpose string) (int32, *ActionClaims, error) {
+	claims := &ActionClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.secretKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(purpose),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return 0, nil, ErrActionTokenExpired
+		}
+		return 0, nil, ErrInvalidActionToken
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 32)
+	if err != nil {
+		return 0, nil, ErrInvalidActionToken
+	}
+
+	return int32(userID), claims, nil
+}
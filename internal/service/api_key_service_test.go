@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+)
+
+func newTestAPIKeyService() (*APIKeyService, *mock.MockAPIKeyRepository) {
+	repo := mock.NewMockAPIKeyRepository()
+	userRepo := &mock.SimpleMockUserRepository{
+		User: &entity.User{ID: 1, Username: "testuser", Email: "test@example.com"},
+	}
+	roleRepo := mock.NewMockRoleRepository()
+	roleRepo.UserRoles[1] = []string{entity.RoleAdmin}
+	return NewAPIKeyService(repo, userRepo, roleRepo), repo
+}
+
+// createKey 產生金鑰並存入 repository，回傳原始金鑰
+func createKey(t *testing.T, svc *APIKeyService, repo *mock.MockAPIKeyRepository, modify func(*entity.APIKey)) string {
+	t.Helper()
+
+	rawKey, prefix, keyHash, err := svc.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	key := &entity.APIKey{UserID: 1, Name: "ci", Prefix: prefix, KeyHash: keyHash}
+	if modify != nil {
+		modify(key)
+	}
+	if err := repo.Create(context.Background(), key); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	return rawKey
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	svc, _ := newTestAPIKeyService()
+
+	rawKey, prefix, keyHash, err := svc.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	if !strings.HasPrefix(rawKey, APIKeyPrefix) || !strings.HasPrefix(rawKey, prefix) {
+		t.Errorf("Expected key %q to start with %q", rawKey, prefix)
+	}
+	if keyHash != utils.HashToken(rawKey) || strings.Contains(keyHash, rawKey) {
+		t.Error("Expected only the SHA-256 hash of the key to be returned for storage")
+	}
+
+	other, _, _, _ := svc.GenerateKey()
+	if other == rawKey {
+		t.Error("Expected generated keys to be unique")
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	svc, repo := newTestAPIKeyService()
+	rawKey := createKey(t, svc, repo, nil)
+
+	principal, err := svc.Authenticate(context.Background(), rawKey)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if principal.User.ID != 1 || principal.Key.ID != 1 {
+		t.Errorf("Unexpected principal user %d key %d", principal.User.ID, principal.Key.ID)
+	}
+	if len(principal.Roles) != 1 || principal.Roles[0] != entity.RoleAdmin {
+		t.Errorf("Expected admin role, got %v", principal.Roles)
+	}
+}
+
+func TestAuthenticateAPIKey_Invalid(t *testing.T) {
+	svc, repo := newTestAPIKeyService()
+	past := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name   string
+		rawKey string
+	}{
+		{name: "Unknown", rawKey: APIKeyPrefix + "unknown"},
+		{name: "WrongPrefix", rawKey: "not-an-api-key"},
+		{name: "Revoked", rawKey: createKey(t, svc, repo, func(k *entity.APIKey) { k.RevokedAt = &past })},
+		{name: "Expired", rawKey: createKey(t, svc, repo, func(k *entity.APIKey) { k.ExpiresAt = &past })},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := svc.Authenticate(context.Background(), tc.rawKey); err != ErrInvalidAPIKey {
+				t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordAPIKeyUsage(t *testing.T) {
+	svc, repo := newTestAPIKeyService()
+	createKey(t, svc, repo, nil)
+	ctx := context.Background()
+
+	if err := svc.RecordUsage(ctx, repo.Keys[0], "10.0.0.1"); err != nil {
+		t.Fatalf("RecordUsage failed: %v", err)
+	}
+	if repo.Keys[0].LastUsedAt == nil || repo.Keys[0].LastUsedIP != "10.0.0.1" {
+		t.Fatalf("Expected usage to be recorded, got %+v", repo.Keys[0])
+	}
+
+	// 同一個 IP 在間隔內不重複寫入
+	recordedAt := time.Now().Add(-time.Second)
+	repo.Keys[0].LastUsedAt = &recordedAt
+	key := *repo.Keys[0]
+	if err := svc.RecordUsage(ctx, &key, "10.0.0.1"); err != nil {
+		t.Fatalf("RecordUsage failed: %v", err)
+	}
+	if !repo.Keys[0].LastUsedAt.Equal(recordedAt) {
+		t.Error("Expected usage within the interval to be skipped")
+	}
+
+	// IP 改變時立即更新
+	if err := svc.RecordUsage(ctx, &key, "10.0.0.2"); err != nil {
+		t.Fatalf("RecordUsage failed: %v", err)
+	}
+	if repo.Keys[0].LastUsedIP != "10.0.0.2" {
+		t.Errorf("Expected new IP to be recorded, got %s", repo.Keys[0].LastUsedIP)
+	}
+}
+
+func TestRestrictToScopes(t *testing.T) {
+	permissions := map[string]bool{
+		entity.PermissionUsersList:   true,
+		entity.PermissionUsersDelete: true,
+	}
+
+	restricted := RestrictToScopes(permissions, []string{entity.PermissionUsersList, entity.PermissionUsersUnlock})
+
+	if len(restricted) != 1 || !restricted[entity.PermissionUsersList] {
+		t.Errorf("Expected only users:list, got %v", restricted)
+	}
+	if len(RestrictToScopes(permissions, nil)) != 0 {
+		t.Error("Expected a key without scopes to have no permissions")
+	}
+}
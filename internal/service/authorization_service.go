@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+)
+
+// AuthorizationService 將角色解析為權限，並在記憶體中快取一段時間
+type AuthorizationService struct {
+	roleRepo contract.RoleRepository
+	cacheTTL time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]cachedPermissions // key: 角色名稱
+}
+
+type cachedPermissions struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+func NewAuthorizationService(roleRepo contract.RoleRepository, cacheTTL time.Duration) *AuthorizationService {
+	return &AuthorizationService{
+		roleRepo: roleRepo,
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]cachedPermissions),
+	}
+}
+
+// Permissions 回傳多個角色合併後的權限集合
+func (s *AuthorizationService) Permissions(ctx context.Context, roles []string) (map[string]bool, error) {
+	permissions := make(map[string]bool)
+
+	for _, role := range roles {
+		rolePermissions, err := s.rolePermissions(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		for _, permission := range rolePermissions {
+			permissions[permission] = true
+		}
+	}
+
+	return permissions, nil
+}
+
+func (s *AuthorizationService) rolePermissions(ctx context.Context, role string) ([]string, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[role]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.permissions, nil
+	}
+
+	permissions, err := s.roleRepo.GetRolePermissions(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[role] = cachedPermissions{
+		permissions: permissions,
+		expiresAt:   time.Now().Add(s.cacheTTL),
+	}
+	s.mu.Unlock()
+
+	return permissions, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // 註冊 GIF 解碼器
+	"image/jpeg"
+	_ "image/png" // 註冊 PNG 解碼器
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/pkg/storage"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 註冊 WebP 解碼器
+)
+
+// maxAvatarPixels 解碼前先檢查圖片尺寸，避免小檔案解壓縮成巨大的圖片
+const maxAvatarPixels = 40_000_000
+
+// avatarJPEGQuality 重新編碼的 JPEG 品質
+const avatarJPEGQuality = 85
+
+var (
+	ErrAvatarTooLarge    = errors.New("avatar too large")
+	ErrAvatarUnsupported = errors.New("unsupported avatar image")
+)
+
+// avatarMediaTypes 接受的圖片格式（以 http.DetectContentType 判斷）
+var avatarMediaTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// AvatarService 處理上傳的頭像：檢查格式、裁切成正方形並縮放成多種尺寸後存入 BlobStore
+// 圖片一律重新編碼為 JPEG，原始檔案（包含 EXIF 等中繼資料）不會保存
+type AvatarService struct {
+	store    storage.BlobStore
+	sizes    []int // 由大到小
+	maxBytes int64
+	urlTTL   time.Duration
+}
+
+func NewAvatarService(store storage.BlobStore, sizes []int, maxBytes int64, urlTTL time.Duration) *AvatarService {
+	sorted := append([]int(nil), sizes...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	return &AvatarService{
+		store:    store,
+		sizes:    sorted,
+		maxBytes: maxBytes,
+		urlTTL:   urlTTL,
+	}
+}
+
+// MaxBytes 上傳檔案的大小上限
+func (s *AvatarService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Upload 處理並存入頭像，回傳新的路徑前綴（每次上傳都不同，舊的網址不會顯示新圖片）
+// 檔案格式由內容判斷，不採用上傳時宣告的 Content-Type
+func (s *AvatarService) Upload(ctx context.Context, userID int32, data []byte) (string, error) {
+	if int64(len(data)) > s.maxBytes {
+		return "", ErrAvatarTooLarge
+	}
+	if !avatarMediaTypes[http.DetectContentType(data)] {
+		return "", ErrAvatarUnsupported
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return "", ErrAvatarUnsupported
+	}
+	if config.Width*config.Height > maxAvatarPixels {
+		return "", ErrAvatarTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrAvatarUnsupported
+	}
+
+	version := make([]byte, 8)
+	if _, err := rand.Read(version); err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("avatars/%d/%s", userID, hex.EncodeToString(version))
+
+	square := cropSquare(src)
+	for _, size := range s.sizes {
+		encoded, err := encodeAvatar(square, size)
+		if err != nil {
+			return "", err
+		}
+		if err := s.store.Put(ctx, avatarSizeKey(key, size), bytes.NewReader(encoded), int64(len(encoded)), "image/jpeg"); err != nil {
+			_ = s.Remove(ctx, key)
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+// Remove 刪除 key 下所有尺寸的頭像
+func (s *AvatarService) Remove(ctx context.Context, key string) error {
+	var firstErr error
+	for _, size := range s.sizes {
+		if err := s.store.Delete(ctx, avatarSizeKey(key, size)); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// URLs 各尺寸頭像的簽章網址（key 為尺寸，例如 "128"），網址在設定的期限後失效
+func (s *AvatarService) URLs(ctx context.Context, key string) (map[string]string, error) {
+	urls := make(map[string]string, len(s.sizes))
+	for _, size := range s.sizes {
+		u, err := s.store.SignedURL(ctx, avatarSizeKey(key, size), s.urlTTL)
+		if err != nil {
+			return nil, err
+		}
+		urls[strconv.Itoa(size)] = u
+	}
+	return urls, nil
+}
+
+func avatarSizeKey(key string, size int) string {
+	return fmt.Sprintf("%s/%d.jpg", key, size)
+}
+
+// cropSquare 從圖片中央裁切出最大的正方形
+func cropSquare(src image.Image) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, image.Pt(x, y), draw.Src)
+	return dst
+}
+
+// encodeAvatar 縮放成 size x size 並以 JPEG 編碼，透明部分以白色填滿
+func encodeAvatar(square image.Image, size int) ([]byte, error) {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), square, square.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: avatarJPEGQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
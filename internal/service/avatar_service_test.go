@@ -0,0 +1,113 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/pkg/storage"
+)
+
+func newTestAvatarService(t *testing.T) (*AvatarService, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	store, err := storage.NewLocalStore(root, "http://localhost:8080/media", "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStore failed: %v", err)
+	}
+	return NewAvatarService(store, []int{64, 128}, 1<<20, time.Minute), root
+}
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAvatarService_Upload(t *testing.T) {
+	svc, root := newTestAvatarService(t)
+	ctx := context.Background()
+
+	key, err := svc.Upload(ctx, 1, encodePNG(t, 300, 200))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 每個尺寸都重新編碼為正方形 JPEG
+	for _, size := range []int{64, 128} {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(avatarSizeKey(key, size))))
+		if err != nil {
+			t.Fatalf("Expected %dpx avatar to be stored, got %v", size, err)
+		}
+		config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Expected JPEG, got %v", err)
+		}
+		if config.Width != size || config.Height != size {
+			t.Errorf("Expected %dx%d, got %dx%d", size, size, config.Width, config.Height)
+		}
+	}
+
+	urls, err := svc.URLs(ctx, key)
+	if err != nil || len(urls) != 2 || urls["64"] == "" || urls["128"] == "" {
+		t.Errorf("Expected signed URLs for each size, got %v, %v", urls, err)
+	}
+
+	if err := svc.Remove(ctx, key); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(avatarSizeKey(key, 64)))); !os.IsNotExist(err) {
+		t.Errorf("Expected avatar to be removed, got %v", err)
+	}
+}
+
+func TestAvatarService_Rejects(t *testing.T) {
+	// GIF 的畫面尺寸在檔頭，改成 10000x10000 模擬解壓縮炸彈
+	var bomb bytes.Buffer
+	if err := gif.Encode(&bomb, image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.White}), nil); err != nil {
+		t.Fatalf("gif.Encode failed: %v", err)
+	}
+	bombBytes := bomb.Bytes()
+	copy(bombBytes[6:10], []byte{0x10, 0x27, 0x10, 0x27})
+
+	testCases := []struct {
+		name        string
+		data        []byte
+		expectError error
+	}{
+		{name: "NotAnImage", data: []byte("<?php echo 'not an image'; ?>"), expectError: ErrAvatarUnsupported},
+		{name: "SVG", data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), expectError: ErrAvatarUnsupported},
+		{name: "TruncatedPNG", data: encodePNG(t, 10, 10)[:40], expectError: ErrAvatarUnsupported},
+		{name: "TooManyBytes", data: append(encodePNG(t, 10, 10), make([]byte, 1<<20)...), expectError: ErrAvatarTooLarge},
+		{name: "TooManyPixels", data: bombBytes, expectError: ErrAvatarTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, root := newTestAvatarService(t)
+			if _, err := svc.Upload(context.Background(), 1, tc.data); err != tc.expectError {
+				t.Errorf("Expected error %v, got %v", tc.expectError, err)
+			}
+			if entries, _ := os.ReadDir(root); len(entries) != 0 {
+				t.Error("Expected nothing to be stored")
+			}
+		})
+	}
+}
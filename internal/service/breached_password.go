@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+)
+
+// breachedPrefixLength k-anonymity 查詢使用的 SHA-1 前綴長度（與 Have I Been Pwned range API 相同）
+const breachedPrefixLength = 5
+
+// breachedReadChunk 每次從檔案讀取一行時的緩衝大小
+const breachedReadChunk = 64
+
+// BreachedPasswordFile 離線的外洩密碼清單
+//
+// 檔案為依雜湊排序的 Have I Been Pwned SHA-1 清單，每行為 "HASH" 或 "HASH:COUNT"。
+// 查詢時只以雜湊前 5 碼在檔案中二分搜尋出該範圍，再於記憶體比對後綴，
+// 因此不需將整個清單載入記憶體，之後也能換成線上的 range API 而不改變呼叫端
+type BreachedPasswordFile struct {
+	file *os.File
+	size int64
+}
+
+var _ BreachedPasswordChecker = (*BreachedPasswordFile)(nil)
+
+func OpenBreachedPasswordFile(path string) (*BreachedPasswordFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &BreachedPasswordFile{
+		file: file,
+		size: info.Size(),
+	}, nil
+}
+
+func (f *BreachedPasswordFile) Close() error {
+	return f.file.Close()
+}
+
+// IsBreached 密碼的 SHA-1 是否出現在清單中
+func (f *BreachedPasswordFile) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := f.Range(hash[:breachedPrefixLength])
+	if err != nil {
+		return false, err
+	}
+	for _, suffix := range suffixes {
+		if suffix == hash[breachedPrefixLength:] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Range 回傳以 prefix 開頭的所有雜湊後綴（大寫）
+func (f *BreachedPasswordFile) Range(prefix string) ([]string, error) {
+	prefix = strings.ToUpper(prefix)
+
+	// 二分搜尋第一個雜湊 >= prefix 的行
+	lo, hi := int64(0), f.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, err := f.lineStart(mid)
+		if err != nil {
+			return nil, err
+		}
+		if start < f.size {
+			line, err := f.readLine(start)
+			if err != nil {
+				return nil, err
+			}
+			if breachedHash(line) < prefix {
+				lo = mid + 1
+				continue
+			}
+		}
+		hi = mid
+	}
+
+	start, err := f.lineStart(lo)
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes := []string{}
+	scanner := bufio.NewScanner(io.NewSectionReader(f.file, start, f.size-start))
+	for scanner.Scan() {
+		hash := breachedHash(scanner.Text())
+		if !strings.HasPrefix(hash, prefix) {
+			break
+		}
+		suffixes = append(suffixes, hash[len(prefix):])
+	}
+	return suffixes, scanner.Err()
+}
+
+// lineStart 回傳 offset 之後（含）第一行的起始位置，沒有下一行時回傳檔案大小
+func (f *BreachedPasswordFile) lineStart(offset int64) (int64, error) {
+	if offset == 0 {
+		return 0, nil
+	}
+
+	buf := make([]byte, breachedReadChunk)
+	for pos := offset - 1; pos < f.size; pos += breachedReadChunk {
+		n, err := f.file.ReadAt(buf, pos)
+		if i := strings.IndexByte(string(buf[:n]), '\n'); i >= 0 {
+			return pos + int64(i) + 1, nil
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return f.size, nil
+}
+
+// readLine 讀取 start 開始的一行（不含換行）
+func (f *BreachedPasswordFile) readLine(start int64) (string, error) {
+	var line strings.Builder
+	buf := make([]byte, breachedReadChunk)
+	for pos := start; pos < f.size; pos += breachedReadChunk {
+		n, err := f.file.ReadAt(buf, pos)
+		chunk := string(buf[:n])
+		if i := strings.IndexByte(chunk, '\n'); i >= 0 {
+			line.WriteString(chunk[:i])
+			break
+		}
+		line.WriteString(chunk)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return line.String(), nil
+}
+
+// breachedHash 取出一行中的雜湊部分（去除出現次數與換行）
+func breachedHash(line string) string {
+	hash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+	return strings.ToUpper(hash)
+}
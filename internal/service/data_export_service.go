@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+)
+
+// DataExportService 個人資料匯出的註冊表
+// 每個保存用戶資料的模組在啟動時以名稱註冊自己的 exporter，匯出時依註冊順序收集
+// 新增資料表時只需註冊新的 exporter，匯出 API 不需修改
+//
+// 密碼歷史只有雜湊，不匯出
+type DataExportService struct {
+	names     []string
+	exporters map[string]contract.UserDataExporter
+}
+
+func NewDataExportService() *DataExportService {
+	return &DataExportService{
+		exporters: make(map[string]contract.UserDataExporter),
+	}
+}
+
+// Register 以 name 作為匯出結果的欄位名稱註冊 exporter
+// 名稱重複表示啟動設定錯誤，直接 panic
+func (s *DataExportService) Register(name string, exporter contract.UserDataExporter) {
+	if _, ok := s.exporters[name]; ok {
+		panic(fmt.Sprintf("data exporter %q already registered", name))
+	}
+	s.names = append(s.names, name)
+	s.exporters[name] = exporter
+}
+
+// Names 已註冊的 exporter 名稱（依註冊順序）
+func (s *DataExportService) Names() []string {
+	return append([]string(nil), s.names...)
+}
+
+// Export 收集所有模組為該用戶保存的資料，任一 exporter 失敗即回傳錯誤（不回傳不完整的匯出）
+func (s *DataExportService) Export(ctx context.Context, userID int32) (map[string]interface{}, error) {
+	data := make(map[string]interface{}, len(s.names))
+	for _, name := range s.names {
+		value, err := s.exporters[name].ExportUserData(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", name, err)
+		}
+		data[name] = value
+	}
+	return data, nil
+}
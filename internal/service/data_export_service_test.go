@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+)
+
+func TestDataExportService_Export(t *testing.T) {
+	roleRepo := mock.NewMockRoleRepository()
+	roleRepo.UserRoles[1] = []string{"user"}
+	identityRepo := mock.NewMockUserIdentityRepository()
+
+	svc := NewDataExportService()
+	svc.Register("roles", roleRepo)
+	svc.Register("identities", identityRepo)
+	svc.Register("mfa", mock.NewMockMFARepository())
+
+	if names := svc.Names(); !reflect.DeepEqual(names, []string{"roles", "identities", "mfa"}) {
+		t.Errorf("Expected exporters in registration order, got %v", names)
+	}
+
+	data, err := svc.Export(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if roles, ok := data["roles"].([]string); !ok || len(roles) != 1 || roles[0] != "user" {
+		t.Errorf("Unexpected roles export: %#v", data["roles"])
+	}
+	if _, ok := data["identities"]; !ok {
+		t.Error("Expected identities key even when empty")
+	}
+	if data["mfa"] != nil {
+		t.Errorf("Expected nil mfa export when not configured, got %#v", data["mfa"])
+	}
+
+	// 任一 exporter 失敗即不回傳不完整的匯出
+	identityRepo.Error = errors.New("db down")
+	if _, err := svc.Export(context.Background(), 1); !errors.Is(err, identityRepo.Error) {
+		t.Errorf("Expected exporter error, got %v", err)
+	}
+}
+
+func TestDataExportService_DuplicateName(t *testing.T) {
+	svc := NewDataExportService()
+	svc.Register("roles", mock.NewMockRoleRepository())
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on duplicate exporter name")
+		}
+	}()
+	svc.Register("roles", mock.NewMockRoleRepository())
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey 非對稱簽章金鑰
+// PrivateKey 為 nil 時只能用來驗證（例如輪替後仍在效期內的舊金鑰）
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// NewSigningKey 以私鑰建立可簽章的金鑰，演算法由金鑰類型決定
+func NewSigningKey(id string, privateKey crypto.Signer) (*SigningKey, error) {
+	key, err := NewVerificationKey(id, privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.PrivateKey = privateKey
+	return key, nil
+}
+
+// NewVerificationKey 以公鑰建立只能驗證的金鑰
+func NewVerificationKey(id string, publicKey crypto.PublicKey) (*SigningKey, error) {
+	if id == "" {
+		return nil, errors.New("key id is required")
+	}
+
+	var method jwt.SigningMethod
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", publicKey)
+	}
+
+	return &SigningKey{
+		ID:        id,
+		Method:    method,
+		PublicKey: publicKey,
+	}, nil
+}
+
+// LoadPEMKey 從 PEM 檔案載入金鑰
+// 有私鑰檔時公鑰由私鑰推導；只有公鑰檔時為驗證專用金鑰
+func LoadPEMKey(id, privateKeyFile, publicKeyFile string) (*SigningKey, error) {
+	if privateKeyFile != "" {
+		block, err := readPEM(privateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		return NewSigningKey(id, privateKey)
+	}
+
+	if publicKeyFile != "" {
+		block, err := readPEM(publicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		return NewVerificationKey(id, publicKey)
+	}
+
+	return nil, fmt.Errorf("key %s: private_key_file or public_key_file is required", id)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+	return block, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	// PKCS#1（BEGIN RSA PRIVATE KEY）
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	// PKCS#8（BEGIN PRIVATE KEY），RSA 與 Ed25519 皆適用
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
@@ -2,55 +2,129 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// JWTService 簽發與驗證 access token
+// 支援兩種模式：
+//   - HS256：共用密鑰（NewJWTService）
+//   - RS256 / EdDSA：非對稱金鑰，token 帶有 kid header，可同時接受多把金鑰以便輪替（NewJWTServiceWithKeys）
 type JWTService struct {
-	secretKey   string
-	expireHours int
+	secretKey string
+	accessTTL time.Duration
+
+	signingKey       *SigningKey            // 非對稱模式下目前用來簽章的金鑰
+	verificationKeys map[string]*SigningKey // 非對稱模式下可接受的金鑰（含 signingKey），key: kid
 }
 
 type Claims struct {
+	UserID       int32    `json:"user_id"`
+	Username     string   `json:"username"`
+	Email        string   `json:"email"`
+	TokenVersion int32    `json:"token_version"`
+	Roles        []string `json:"roles"`
+	SessionID    string   `json:"sid,omitempty"` // 所屬的登入 session，撤銷 session 時 token 一併失效
+	// Actor 管理員代登入（impersonation）時為實際操作的管理員，對應 RFC 8693 的 act claim
+	Actor *ActorClaim `json:"act,omitempty"`
+	// RegisteredClaims.ID 即為 jti，用於撤銷單一 token
+	jwt.RegisteredClaims
+}
+
+// ActorClaim 代為操作的管理員
+type ActorClaim struct {
 	UserID   int32  `json:"user_id"`
 	Username string `json:"username"`
-	Email    string `json:"email"`
-	jwt.RegisteredClaims
 }
 
-func NewJWTService(secretKey string, expireHours int) *JWTService {
+// IsImpersonated 是否為管理員代登入的 token
+func (c *Claims) IsImpersonated() bool {
+	return c.Actor != nil
+}
+
+func NewJWTService(secretKey string, accessTTL time.Duration) *JWTService {
 	return &JWTService{
-		secretKey:   secretKey,
-		expireHours: expireHours,
+		secretKey: secretKey,
+		accessTTL: accessTTL,
+	}
+}
+
+// NewJWTServiceWithKeys 建立非對稱簽章的 JWTService
+// activeKeyID 指定用來簽章的金鑰（必須有私鑰），其餘金鑰只用於驗證
+func NewJWTServiceWithKeys(keys []*SigningKey, activeKeyID string, accessTTL time.Duration) (*JWTService, error) {
+	verificationKeys := make(map[string]*SigningKey, len(keys))
+	for _, key := range keys {
+		if _, exists := verificationKeys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		verificationKeys[key.ID] = key
+	}
+
+	signingKey, ok := verificationKeys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found", activeKeyID)
+	}
+	if signingKey.PrivateKey == nil {
+		return nil, fmt.Errorf("active key %q has no private key", activeKeyID)
 	}
+
+	return &JWTService{
+		accessTTL:        accessTTL,
+		signingKey:       signingKey,
+		verificationKeys: verificationKeys,
+	}, nil
+}
+
+// AccessTTL 回傳 access token 的有效時間
+func (s *JWTService) AccessTTL() time.Duration {
+	return s.accessTTL
 }
 
 // GenerateToken 生成 JWT Token
-func (s *JWTService) GenerateToken(userID int32, username, email string) (string, error) {
-	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
+func (s *JWTService) GenerateToken(user *entity.User, roles []string, sessionID string) (string, error) {
+	claims := newClaims(user, roles, s.accessTTL)
+	claims.SessionID = sessionID
+
+	return s.sign(claims)
+}
+
+// GenerateImpersonationToken 生成管理員以 user 身分操作的 token
+// 帶有 act claim 記錄實際操作的 actor，不屬於任何 session，也沒有 refresh token，到期後需重新申請
+func (s *JWTService) GenerateImpersonationToken(user *entity.User, roles []string, actor *entity.User, ttl time.Duration) (string, error) {
+	claims := newClaims(user, roles, ttl)
+	claims.Actor = &ActorClaim{
+		UserID:   actor.ID,
+		Username: actor.Username,
+	}
+
+	return s.sign(claims)
+}
+
+// newClaims 建立 user 的基本 claims
+func newClaims(user *entity.User, roles []string, ttl time.Duration) Claims {
+	now := time.Now()
+	return Claims{
+		UserID:       user.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+		TokenVersion: user.TokenVersion,
+		Roles:        roles,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(s.expireHours))),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.secretKey))
 }
 
 // ValidateToken 驗證 Token
 func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(s.secretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.keyFunc)
 
 	if err != nil {
 		return nil, err
@@ -62,3 +136,46 @@ func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+// JWKS 回傳可公開的驗證公鑰（HS256 模式下為空集合）
+func (s *JWTService) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range s.verificationKeys {
+		if jwk, ok := toJWK(key); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks
+}
+
+// sign 以目前的簽章金鑰簽署 claims
+func (s *JWTService) sign(claims jwt.Claims) (string, error) {
+	if s.signingKey == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(s.secretKey))
+	}
+
+	token := jwt.NewWithClaims(s.signingKey.Method, claims)
+	token.Header["kid"] = s.signingKey.ID
+	return token.SignedString(s.signingKey.PrivateKey)
+}
+
+// keyFunc 依模式與 kid 選擇驗證金鑰，並確認演算法與金鑰相符（防止演算法混淆攻擊）
+func (s *JWTService) keyFunc(token *jwt.Token) (interface{}, error) {
+	if s.signingKey == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(s.secretKey), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.verificationKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.PublicKey, nil
+}
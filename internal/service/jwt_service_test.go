@@ -0,0 +1,191 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testUser = &entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}
+
+func newRSAKey(t *testing.T, id string) *SigningKey {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	key, err := NewSigningKey(id, privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create signing key: %v", err)
+	}
+	return key
+}
+
+func newEd25519Key(t *testing.T, id string) *SigningKey {
+	t.Helper()
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	key, err := NewSigningKey(id, privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create signing key: %v", err)
+	}
+	return key
+}
+
+// =============================================================================
+// Signing Tests
+// =============================================================================
+
+func TestJWTService_SignAndValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		newKey func(t *testing.T, id string) *SigningKey
+		alg    string
+	}{
+		{name: "RS256", newKey: newRSAKey, alg: "RS256"},
+		{name: "EdDSA", newKey: newEd25519Key, alg: "EdDSA"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := tc.newKey(t, "key-1")
+			svc, err := NewJWTServiceWithKeys([]*SigningKey{key}, "key-1", time.Minute)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			tokenString, err := svc.GenerateToken(testUser, []string{entity.RoleUser}, "session-1")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+			if err != nil {
+				t.Fatalf("Failed to parse token: %v", err)
+			}
+			if parsed.Header["kid"] != "key-1" || parsed.Method.Alg() != tc.alg {
+				t.Errorf("Expected kid key-1 and alg %s, got %v %s", tc.alg, parsed.Header["kid"], parsed.Method.Alg())
+			}
+
+			claims, err := svc.ValidateToken(tokenString)
+			if err != nil {
+				t.Fatalf("Expected valid token, got %v", err)
+			}
+			if claims.UserID != testUser.ID {
+				t.Errorf("Expected user ID %d, got %d", testUser.ID, claims.UserID)
+			}
+			if claims.SessionID != "session-1" {
+				t.Errorf("Expected session ID session-1, got %s", claims.SessionID)
+			}
+		})
+	}
+}
+
+func TestJWTService_KeyRotation(t *testing.T) {
+	oldKey := newRSAKey(t, "key-1")
+	newKey := newRSAKey(t, "key-2")
+
+	// 輪替前：以 key-1 簽發
+	before, _ := NewJWTServiceWithKeys([]*SigningKey{oldKey}, "key-1", time.Minute)
+	oldToken, err := before.GenerateToken(testUser, nil, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 輪替後：以 key-2 簽發，key-1 只保留公鑰
+	oldPublic, _ := NewVerificationKey("key-1", oldKey.PublicKey)
+	after, err := NewJWTServiceWithKeys([]*SigningKey{newKey, oldPublic}, "key-2", time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := after.ValidateToken(oldToken); err != nil {
+		t.Errorf("Expected token signed with previous key to remain valid, got %v", err)
+	}
+	if len(after.JWKS().Keys) != 2 {
+		t.Errorf("Expected 2 keys in JWKS, got %d", len(after.JWKS().Keys))
+	}
+
+	// 完全移除舊金鑰後，舊 token 失效
+	removed, _ := NewJWTServiceWithKeys([]*SigningKey{newKey}, "key-2", time.Minute)
+	if _, err := removed.ValidateToken(oldToken); err == nil {
+		t.Error("Expected token with unknown kid to be rejected")
+	}
+}
+
+func TestJWTService_RejectsAlgorithmConfusion(t *testing.T) {
+	key := newRSAKey(t, "key-1")
+	svc, _ := NewJWTServiceWithKeys([]*SigningKey{key}, "key-1", time.Minute)
+
+	// 以公鑰內容當作 HMAC 密鑰偽造 token
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 1})
+	forged.Header["kid"] = "key-1"
+	tokenString, err := forged.SignedString([]byte("any-secret"))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	if _, err := svc.ValidateToken(tokenString); err == nil {
+		t.Error("Expected HS256 token to be rejected in asymmetric mode")
+	}
+}
+
+func TestJWTService_ActiveKeyMustHavePrivateKey(t *testing.T) {
+	key := newEd25519Key(t, "key-1")
+	public, _ := NewVerificationKey("key-1", key.PublicKey)
+
+	if _, err := NewJWTServiceWithKeys([]*SigningKey{public}, "key-1", time.Minute); err == nil {
+		t.Error("Expected error when active key has no private key")
+	}
+	if _, err := NewJWTServiceWithKeys([]*SigningKey{key}, "missing", time.Minute); err == nil {
+		t.Error("Expected error when active key is missing")
+	}
+}
+
+func TestJWTService_HS256JWKSIsEmpty(t *testing.T) {
+	svc := NewJWTService("secret", time.Minute)
+
+	if keys := svc.JWKS().Keys; len(keys) != 0 {
+		t.Errorf("Expected no public keys in HS256 mode, got %d", len(keys))
+	}
+}
+
+func TestJWTService_ImpersonationToken(t *testing.T) {
+	svc := NewJWTService("test-secret", time.Minute)
+	admin := &entity.User{ID: 9, Username: "admin", Email: "admin@example.com"}
+
+	tokenString, err := svc.GenerateImpersonationToken(testUser, []string{entity.RoleUser}, admin, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	claims, err := svc.ValidateToken(tokenString)
+	if err != nil {
+		t.Fatalf("Expected valid token, got %v", err)
+	}
+	if claims.UserID != testUser.ID || !claims.IsImpersonated() {
+		t.Fatalf("Expected impersonated token for user %d, got %+v", testUser.ID, claims)
+	}
+	if claims.Actor.UserID != admin.ID || claims.Actor.Username != admin.Username {
+		t.Errorf("Unexpected actor claim %+v", claims.Actor)
+	}
+	// 代登入不開啟 session
+	if claims.SessionID != "" {
+		t.Errorf("Expected no session ID, got %s", claims.SessionID)
+	}
+	if ttl := time.Until(claims.ExpiresAt.Time); ttl > 5*time.Minute || ttl < 4*time.Minute {
+		t.Errorf("Expected expiry in about 5 minutes, got %v", ttl)
+	}
+
+	normal, _ := svc.GenerateToken(testUser, []string{entity.RoleUser}, "session-1")
+	if claims, _ := svc.ValidateToken(normal); claims.IsImpersonated() {
+		t.Error("Expected normal token not to be impersonated")
+	}
+}
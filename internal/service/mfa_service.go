@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	totpPeriod = 30 // 秒
+	totpSkew   = 1  // 允許前後各一個時間步的時鐘誤差
+	totpDigits = otp.DigitsSix
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAService 處理 TOTP（RFC 6238）驗證碼與備用碼
+type MFAService struct {
+	repo   contract.MFARepository
+	issuer string // 顯示在驗證器 App 中的服務名稱
+}
+
+func NewMFAService(repo contract.MFARepository, issuer string) *MFAService {
+	return &MFAService{
+		repo:   repo,
+		issuer: issuer,
+	}
+}
+
+// GenerateKey 產生新的 TOTP secret 與 otpauth URI（供驗證器 App 掃描）
+func (s *MFAService) GenerateKey(accountName string) (secret, uri string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.issuer,
+		AccountName: accountName,
+		Period:      totpPeriod,
+		Digits:      totpDigits,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return key.Secret(), key.URL(), nil
+}
+
+// IsEnabled 用戶是否已啟用兩步驟驗證
+func (s *MFAService) IsEnabled(ctx context.Context, userID int32) (bool, error) {
+	mfa, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return mfa.IsEnabled(), nil
+}
+
+// VerifyTOTP 驗證 TOTP 驗證碼，同一時間步的驗證碼只能成功使用一次
+func (s *MFAService) VerifyTOTP(ctx context.Context, mfa *entity.UserMFA, code string) (bool, error) {
+	step, ok := matchTOTPStep(mfa.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return false, nil
+	}
+	return s.repo.MarkStepUsed(ctx, mfa.UserID, step)
+}
+
+// VerifyCode 驗證已啟用用戶的 TOTP 驗證碼或備用碼（備用碼使用後即失效）
+func (s *MFAService) VerifyCode(ctx context.Context, userID int32, code string) (bool, error) {
+	mfa, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	if !mfa.IsEnabled() {
+		return false, nil
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totpDigits.Length() {
+		return s.VerifyTOTP(ctx, mfa, code)
+	}
+	return s.repo.UseRecoveryCode(ctx, userID, utils.HashToken(normalizeRecoveryCode(code)))
+}
+
+// GenerateRecoveryCodes 產生新的一組備用碼並取代既有的
+// 資料庫只保存雜湊值，回傳的明文只會顯示給用戶這一次
+func (s *MFAService) GenerateRecoveryCodes(ctx context.Context, userID int32) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))
+		codes[i] = formatRecoveryCode(raw)
+		hashes[i] = utils.HashToken(raw)
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// matchTOTPStep 在允許的時鐘誤差內尋找符合的時間步
+func matchTOTPStep(secret, code string, now time.Time) (int64, bool) {
+	if len(code) != totpDigits.Length() {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for i := -totpSkew; i <= totpSkew; i++ {
+		step := current + int64(i)
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    totpDigits,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// formatRecoveryCode 每 4 個字元加上分隔符號方便閱讀
+func formatRecoveryCode(raw string) string {
+	var b strings.Builder
+	for i, r := range raw {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// normalizeRecoveryCode 移除分隔符號與空白並轉小寫
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 附錄 B 的測試向量（SHA1，取後 6 位數）
+func TestMatchTOTPStep_RFC6238Vectors(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890" 的 base32
+
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+
+	for _, tc := range testCases {
+		step, ok := matchTOTPStep(secret, tc.code, time.Unix(tc.unix, 0))
+		if !ok {
+			t.Errorf("Expected code %s to match at %d", tc.code, tc.unix)
+			continue
+		}
+		if step != tc.unix/totpPeriod {
+			t.Errorf("Expected step %d, got %d", tc.unix/totpPeriod, step)
+		}
+	}
+}
+
+func TestMatchTOTPStep_Skew(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	// 287082 屬於時間步 1（30-59 秒）
+	testCases := []struct {
+		name  string
+		unix  int64
+		match bool
+	}{
+		{name: "PreviousStep", unix: 89, match: true},
+		{name: "NextStep", unix: 0, match: true},
+		{name: "TwoStepsLater", unix: 90, match: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok := matchTOTPStep(secret, "287082", time.Unix(tc.unix, 0)); ok != tc.match {
+				t.Errorf("Expected match=%v, got %v", tc.match, ok)
+			}
+		})
+	}
+
+	if _, ok := matchTOTPStep(secret, "28708", time.Unix(59, 0)); ok {
+		t.Error("Expected malformed code not to match")
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	raw := "abcdefghijklmnop"
+	formatted := formatRecoveryCode(raw)
+	if formatted != "abcd-efgh-ijkl-mnop" {
+		t.Errorf("Unexpected formatted code: %s", formatted)
+	}
+	if got := normalizeRecoveryCode(" ABCD-EFGH ijkl-MNOP "); got != raw {
+		t.Errorf("Expected %s, got %s", raw, got)
+	}
+}
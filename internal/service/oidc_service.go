@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+// oidcStateAudience OIDC 登入狀態 token 的 audience（與其他 action token 區隔）
+const oidcStateAudience = "oidc_state"
+
+var (
+	ErrOIDCProviderNotFound = errors.New("oidc provider not found")
+	ErrInvalidOIDCState     = errors.New("invalid oidc state")
+	ErrOIDCExchangeFailed   = errors.New("oidc code exchange failed")
+)
+
+// OIDCProviderConfig 外部 OpenID Connect 提供者設定
+type OIDCProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string // 未設定時使用 openid、email、profile
+}
+
+// OIDCIdentity 從 ID token 取得的外部身分
+type OIDCIdentity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// OIDCService 處理 OIDC authorization code flow（PKCE + state + nonce）
+// state、nonce 與 PKCE verifier 簽署後存放在瀏覽器 cookie，callback 時取回比對，伺服器不需保存狀態
+type OIDCService struct {
+	providers  map[string]*oidcProvider
+	stateKey   []byte
+	stateTTL   time.Duration
+	httpClient *http.Client
+}
+
+type oidcProvider struct {
+	cfg OIDCProviderConfig
+
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// oidcStateClaims 登入狀態 token 內容
+type oidcStateClaims struct {
+	Provider     string `json:"provider"`
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	jwt.RegisteredClaims
+}
+
+func NewOIDCService(providers []OIDCProviderConfig, stateKey string, stateTTL time.Duration) *OIDCService {
+	s := &OIDCService{
+		providers:  make(map[string]*oidcProvider, len(providers)),
+		stateKey:   []byte(stateKey),
+		stateTTL:   stateTTL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, cfg := range providers {
+		s.providers[cfg.Name] = &oidcProvider{cfg: cfg}
+	}
+	return s
+}
+
+// StateTTL 登入狀態 token 的有效時間
+func (s *OIDCService) StateTTL() time.Duration {
+	return s.stateTTL
+}
+
+// AuthCodeURL 產生導向提供者的授權網址，以及需存放在 cookie 的登入狀態 token
+func (s *OIDCService) AuthCodeURL(ctx context.Context, providerName string) (authURL, stateToken string, err error) {
+	provider, _, err := s.provider(ctx, providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	claims := oidcStateClaims{
+		Provider:     providerName,
+		State:        uuid.New().String(),
+		Nonce:        uuid.New().String(),
+		CodeVerifier: oauth2.GenerateVerifier(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oidcStateAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.stateTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	stateToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.stateKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL = provider.AuthCodeURL(claims.State,
+		oidc.Nonce(claims.Nonce),
+		oauth2.S256ChallengeOption(claims.CodeVerifier),
+	)
+	return authURL, stateToken, nil
+}
+
+// Exchange 驗證 callback 的 state，以授權碼換取 ID token 並驗證簽章、audience 與 nonce
+func (s *OIDCService) Exchange(ctx context.Context, providerName, stateToken, state, code string) (*OIDCIdentity, error) {
+	claims, err := s.parseState(stateToken)
+	if err != nil {
+		return nil, ErrInvalidOIDCState
+	}
+	if claims.Provider != providerName || subtle.ConstantTimeCompare([]byte(claims.State), []byte(state)) != 1 {
+		return nil, ErrInvalidOIDCState
+	}
+
+	provider, verifier, err := s.provider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = oidc.ClientContext(ctx, s.httpClient)
+	token, err := provider.Exchange(ctx, code, oauth2.VerifierOption(claims.CodeVerifier))
+	if err != nil {
+		return nil, ErrOIDCExchangeFailed
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, ErrOIDCExchangeFailed
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, ErrOIDCExchangeFailed
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(claims.Nonce)) != 1 {
+		return nil, ErrOIDCExchangeFailed
+	}
+
+	var idClaims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := idToken.Claims(&idClaims); err != nil {
+		return nil, ErrOIDCExchangeFailed
+	}
+
+	return &OIDCIdentity{
+		Provider:          providerName,
+		Subject:           idToken.Subject,
+		Email:             idClaims.Email,
+		EmailVerified:     idClaims.EmailVerified,
+		PreferredUsername: idClaims.PreferredUsername,
+	}, nil
+}
+
+func (s *OIDCService) parseState(stateToken string) (*oidcStateClaims, error) {
+	claims := &oidcStateClaims{}
+	_, err := jwt.ParseWithClaims(stateToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.stateKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(oidcStateAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// provider 取得提供者的 OAuth2 設定，第一次使用時才進行 discovery，失敗時下次會重試
+func (s *OIDCService) provider(ctx context.Context, name string) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, nil, ErrOIDCProviderNotFound
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth2 != nil {
+		return p.oauth2, p.verifier, nil
+	}
+
+	discovered, err := oidc.NewProvider(oidc.ClientContext(ctx, s.httpClient), p.cfg.IssuerURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scopes := p.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	}
+
+	p.verifier = discovered.Verifier(&oidc.Config{ClientID: p.cfg.ClientID})
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     discovered.Endpoint(),
+		Scopes:       scopes,
+	}
+	return p.oauth2, p.verifier, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/service/oidctest"
+)
+
+func newTestOIDCService(t *testing.T) (*OIDCService, *oidctest.Provider) {
+	t.Helper()
+
+	provider := oidctest.NewProvider(t)
+	svc := NewOIDCService([]OIDCProviderConfig{{
+		Name:         "test",
+		IssuerURL:    provider.Issuer(),
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/test/callback",
+	}}, "state-secret", 10*time.Minute)
+	return svc, provider
+}
+
+func TestOIDCExchange_Success(t *testing.T) {
+	svc, provider := newTestOIDCService(t)
+	provider.PreferredUsername = "oidcuser"
+	ctx := context.Background()
+
+	authURL, stateToken, err := svc.AuthCodeURL(ctx, "test")
+	if err != nil {
+		t.Fatalf("AuthCodeURL failed: %v", err)
+	}
+	code, state := provider.Authorize(t, authURL)
+
+	identity, err := svc.Exchange(ctx, "test", stateToken, state, code)
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+
+	if identity.Provider != "test" || identity.Subject != provider.Subject {
+		t.Errorf("Unexpected identity %s/%s", identity.Provider, identity.Subject)
+	}
+	if identity.Email != provider.Email || !identity.EmailVerified {
+		t.Errorf("Expected verified email %s, got %s (verified=%v)", provider.Email, identity.Email, identity.EmailVerified)
+	}
+	if identity.PreferredUsername != "oidcuser" {
+		t.Errorf("Expected preferred username oidcuser, got %s", identity.PreferredUsername)
+	}
+}
+
+func TestOIDCExchange_InvalidState(t *testing.T) {
+	svc, provider := newTestOIDCService(t)
+	ctx := context.Background()
+
+	authURL, stateToken, err := svc.AuthCodeURL(ctx, "test")
+	if err != nil {
+		t.Fatalf("AuthCodeURL failed: %v", err)
+	}
+	code, state := provider.Authorize(t, authURL)
+
+	testCases := []struct {
+		name       string
+		provider   string
+		stateToken string
+		state      string
+	}{
+		{name: "StateMismatch", provider: "test", stateToken: stateToken, state: "other-state"},
+		{name: "TamperedStateToken", provider: "test", stateToken: stateToken + "x", state: state},
+		{name: "OtherProvider", provider: "other", stateToken: stateToken, state: state},
+		{name: "MissingStateToken", provider: "test", stateToken: "", state: state},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Exchange(ctx, tc.provider, tc.stateToken, tc.state, code)
+			if err != ErrInvalidOIDCState {
+				t.Errorf("Expected ErrInvalidOIDCState, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOIDCExchange_CodeCannotBeReused(t *testing.T) {
+	svc, provider := newTestOIDCService(t)
+	ctx := context.Background()
+
+	authURL, stateToken, err := svc.AuthCodeURL(ctx, "test")
+	if err != nil {
+		t.Fatalf("AuthCodeURL failed: %v", err)
+	}
+	code, state := provider.Authorize(t, authURL)
+
+	if _, err := svc.Exchange(ctx, "test", stateToken, state, code); err != nil {
+		t.Fatalf("First exchange failed: %v", err)
+	}
+	if _, err := svc.Exchange(ctx, "test", stateToken, state, code); err != ErrOIDCExchangeFailed {
+		t.Errorf("Expected ErrOIDCExchangeFailed on reuse, got %v", err)
+	}
+}
+
+func TestOIDCExchange_RejectsCodeFromOtherLogin(t *testing.T) {
+	svc, provider := newTestOIDCService(t)
+	ctx := context.Background()
+
+	// 授權碼屬於另一次登入，PKCE verifier 不符
+	authURL, _, err := svc.AuthCodeURL(ctx, "test")
+	if err != nil {
+		t.Fatalf("AuthCodeURL failed: %v", err)
+	}
+	code, _ := provider.Authorize(t, authURL)
+
+	otherURL, otherStateToken, err := svc.AuthCodeURL(ctx, "test")
+	if err != nil {
+		t.Fatalf("AuthCodeURL failed: %v", err)
+	}
+	_, otherState := provider.Authorize(t, otherURL)
+
+	if _, err := svc.Exchange(ctx, "test", otherStateToken, otherState, code); err != ErrOIDCExchangeFailed {
+		t.Errorf("Expected ErrOIDCExchangeFailed, got %v", err)
+	}
+}
+
+func TestOIDCAuthCodeURL_UnknownProvider(t *testing.T) {
+	svc, _ := newTestOIDCService(t)
+
+	if _, _, err := svc.AuthCodeURL(context.Background(), "unknown"); err != ErrOIDCProviderNotFound {
+		t.Errorf("Expected ErrOIDCProviderNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher 密碼雜湊演算法
+// 雜湊值以各演算法的標準格式編碼（bcrypt 為 $2a$…，argon2id 為 $argon2id$…），可由前綴辨識演算法
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify 比對密碼，hash 格式不正確或不是此演算法產生時回傳 false
+	Verify(password, hash string) bool
+	// NeedsRehash hash 是否以其他演算法或過時的參數產生
+	NeedsRehash(hash string) bool
+	// MaxPasswordLength 可雜湊的密碼最大長度（位元組）
+	MaxPasswordLength() int
+}
+
+// =============================================================================
+// bcrypt
+// =============================================================================
+
+// BcryptMaxPasswordLength bcrypt 只使用密碼的前 72 個位元組，超過時 GenerateFromPassword 回傳 ErrPasswordTooLong
+const BcryptMaxPasswordLength = 72
+
+// BcryptHasher 以 bcrypt 雜湊密碼
+type BcryptHasher struct {
+	Cost int
+}
+
+var _ PasswordHasher = (*BcryptHasher)(nil)
+
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{Cost: cost}
+}
+
+// IsBcryptHash hash 是否為 bcrypt 格式
+func IsBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (h *BcryptHasher) Verify(password, hash string) bool {
+	if !IsBcryptHash(hash) {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	if !IsBcryptHash(hash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+func (h *BcryptHasher) MaxPasswordLength() int {
+	return BcryptMaxPasswordLength
+}
+
+// =============================================================================
+// argon2id
+// =============================================================================
+
+const argon2idPrefix = "$argon2id$"
+
+// Argon2idMaxPasswordLength argon2id 本身沒有長度限制，設定上限避免以超長密碼消耗運算資源
+const Argon2idMaxPasswordLength = 256
+
+// Argon2idParams argon2id 的參數
+type Argon2idParams struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams OWASP 建議的最低參數（19 MiB、2 次迭代）
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Argon2idHasher 以 argon2id 雜湊密碼
+// 格式與參考實作相同：$argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>（base64 不補 =）
+type Argon2idHasher struct {
+	Params Argon2idParams
+}
+
+var _ PasswordHasher = (*Argon2idHasher)(nil)
+
+// NewArgon2idHasher 未設定的參數使用 DefaultArgon2idParams
+func NewArgon2idHasher(params Argon2idParams) *Argon2idHasher {
+	if params.Memory == 0 {
+		params.Memory = DefaultArgon2idParams.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = DefaultArgon2idParams.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = DefaultArgon2idParams.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = DefaultArgon2idParams.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = DefaultArgon2idParams.KeyLength
+	}
+	return &Argon2idHasher{Params: params}
+}
+
+// IsArgon2idHash hash 是否為 argon2id 格式
+func IsArgon2idHash(hash string) bool {
+	return strings.HasPrefix(hash, argon2idPrefix)
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.Params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Params.Iterations, h.Params.Memory, h.Params.Parallelism, h.Params.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version,
+		h.Params.Memory, h.Params.Iterations, h.Params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Verify(password, hash string) bool {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return false
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return subtle.ConstantTimeCompare(computed, key) == 1
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, _, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.Params.Memory ||
+		params.Iterations != h.Params.Iterations ||
+		params.Parallelism != h.Params.Parallelism ||
+		params.SaltLength != h.Params.SaltLength ||
+		params.KeyLength != h.Params.KeyLength
+}
+
+func (h *Argon2idHasher) MaxPasswordLength() int {
+	return Argon2idMaxPasswordLength
+}
+
+// decodeArgon2id 解析 argon2id 雜湊的參數、salt 與 key
+func decodeArgon2id(hash string) (Argon2idParams, []byte, []byte, error) {
+	var params Argon2idParams
+
+	// "", "argon2id", "v=19", "m=…,t=…,p=…", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	if len(salt) == 0 || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
+
+// =============================================================================
+// 多演算法
+// =============================================================================
+
+// MultiHasher 以 primary 產生新雜湊，並依前綴辨識的演算法驗證既有雜湊
+// 由其他演算法或舊參數產生的雜湊在 NeedsRehash 回傳 true，登入成功時即可換成 primary
+type MultiHasher struct {
+	primary PasswordHasher
+	bcrypt  PasswordHasher
+	argon2  PasswordHasher
+}
+
+var _ PasswordHasher = (*MultiHasher)(nil)
+
+// NewMultiHasher primary 必須是 *BcryptHasher 或 *Argon2idHasher
+// 另一種演算法的既有雜湊以預設參數驗證（驗證時參數取自雜湊本身）
+func NewMultiHasher(primary PasswordHasher) *MultiHasher {
+	m := &MultiHasher{
+		primary: primary,
+		bcrypt:  NewBcryptHasher(0),
+		argon2:  NewArgon2idHasher(Argon2idParams{}),
+	}
+	switch h := primary.(type) {
+	case *BcryptHasher:
+		m.bcrypt = h
+	case *Argon2idHasher:
+		m.argon2 = h
+	}
+	return m
+}
+
+func (m *MultiHasher) Hash(password string) (string, error) {
+	return m.primary.Hash(password)
+}
+
+func (m *MultiHasher) Verify(password, hash string) bool {
+	switch {
+	case IsBcryptHash(hash):
+		return m.bcrypt.Verify(password, hash)
+	case IsArgon2idHash(hash):
+		return m.argon2.Verify(password, hash)
+	default:
+		return false
+	}
+}
+
+func (m *MultiHasher) NeedsRehash(hash string) bool {
+	return m.primary.NeedsRehash(hash)
+}
+
+// MaxPasswordLength 新密碼以 primary 雜湊，因此以 primary 的限制為準
+func (m *MultiHasher) MaxPasswordLength() int {
+	return m.primary.MaxPasswordLength()
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2idParams 測試用的低成本參數
+var testArgon2idParams = Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher := NewBcryptHasher(bcrypt.MinCost)
+
+	hash, err := hasher.Hash("password123")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if !IsBcryptHash(hash) {
+		t.Errorf("Expected bcrypt prefix, got %s", hash)
+	}
+	if !hasher.Verify("password123", hash) {
+		t.Error("Expected password to verify")
+	}
+	if hasher.Verify("wrong", hash) {
+		t.Error("Expected wrong password to fail")
+	}
+	if hasher.NeedsRehash(hash) {
+		t.Error("Expected hash with current cost not to need rehash")
+	}
+	if !NewBcryptHasher(bcrypt.MinCost + 1).NeedsRehash(hash) {
+		t.Error("Expected hash with lower cost to need rehash")
+	}
+}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := NewArgon2idHasher(testArgon2idParams)
+
+	hash, err := hasher.Hash("password123")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("Unexpected encoding: %s", hash)
+	}
+	if !hasher.Verify("password123", hash) {
+		t.Error("Expected password to verify")
+	}
+	if hasher.Verify("wrong", hash) {
+		t.Error("Expected wrong password to fail")
+	}
+	if hasher.NeedsRehash(hash) {
+		t.Error("Expected hash with current parameters not to need rehash")
+	}
+
+	stronger := NewArgon2idHasher(Argon2idParams{Memory: 128, Iterations: 1, Parallelism: 1})
+	if !stronger.NeedsRehash(hash) {
+		t.Error("Expected hash with outdated parameters to need rehash")
+	}
+	// 驗證時參數取自雜湊本身
+	if !stronger.Verify("password123", hash) {
+		t.Error("Expected hash with other parameters to still verify")
+	}
+}
+
+func TestArgon2idHasher_Malformed(t *testing.T) {
+	hasher := NewArgon2idHasher(testArgon2idParams)
+
+	for _, hash := range []string{
+		"",
+		"$argon2id$",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+	} {
+		if hasher.Verify("password123", hash) {
+			t.Errorf("Expected malformed hash %q to fail", hash)
+		}
+		if !hasher.NeedsRehash(hash) {
+			t.Errorf("Expected malformed hash %q to need rehash", hash)
+		}
+	}
+}
+
+func TestMultiHasher(t *testing.T) {
+	bcryptHash, _ := NewBcryptHasher(bcrypt.MinCost).Hash("password123")
+	argonHash, _ := NewArgon2idHasher(testArgon2idParams).Hash("password123")
+
+	testCases := []struct {
+		name        string
+		primary     PasswordHasher
+		hash        string
+		needsRehash bool
+	}{
+		{name: "BcryptPrimaryBcryptHash", primary: NewBcryptHasher(bcrypt.MinCost), hash: bcryptHash, needsRehash: false},
+		{name: "BcryptPrimaryArgonHash", primary: NewBcryptHasher(bcrypt.MinCost), hash: argonHash, needsRehash: true},
+		{name: "ArgonPrimaryBcryptHash", primary: NewArgon2idHasher(testArgon2idParams), hash: bcryptHash, needsRehash: true},
+		{name: "ArgonPrimaryArgonHash", primary: NewArgon2idHasher(testArgon2idParams), hash: argonHash, needsRehash: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hasher := NewMultiHasher(tc.primary)
+
+			if !hasher.Verify("password123", tc.hash) {
+				t.Error("Expected password to verify")
+			}
+			if hasher.Verify("wrong", tc.hash) {
+				t.Error("Expected wrong password to fail")
+			}
+			if got := hasher.NeedsRehash(tc.hash); got != tc.needsRehash {
+				t.Errorf("Expected NeedsRehash %v, got %v", tc.needsRehash, got)
+			}
+		})
+	}
+
+	if NewMultiHasher(NewBcryptHasher(bcrypt.MinCost)).Verify("password123", "plaintext") {
+		t.Error("Expected unknown hash format to fail")
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// defaultPasswordMinLength 未設定最短長度時使用
+const defaultPasswordMinLength = 8
+
+// minUserInfoLength username / Email 帳號短於此長度時不檢查相似度，避免誤判
+const minUserInfoLength = 3
+
+// 密碼政策違規代碼
+const (
+	PasswordTooShort         = "too_short"
+	PasswordTooLong          = "too_long"
+	PasswordMissingUppercase = "missing_uppercase"
+	PasswordMissingLowercase = "missing_lowercase"
+	PasswordMissingDigit     = "missing_digit"
+	PasswordMissingSymbol    = "missing_symbol"
+	PasswordContainsUserInfo = "contains_user_info"
+	PasswordRecentlyUsed     = "recently_used"
+	PasswordBreached         = "breached"
+)
+
+// PasswordPolicy 密碼規則
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int // 位元組，0 或超過雜湊演算法的限制時使用演算法的限制
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	DisallowUserInfo bool // 不可包含 username 或 Email 帳號
+	HistorySize      int  // 不可重複使用最近 N 個密碼（0 表示停用）
+}
+
+// PasswordViolation 一項未符合的密碼規則
+type PasswordViolation struct {
+	Code    string
+	Message string
+}
+
+// BreachedPasswordChecker 檢查密碼是否出現在已外洩的密碼清單中
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// PasswordService 依密碼政策檢查密碼，並透過 PasswordHasher 雜湊與驗證
+type PasswordService struct {
+	policy   PasswordPolicy
+	hasher   PasswordHasher
+	history  contract.PasswordHistoryRepository
+	breached BreachedPasswordChecker // nil 表示不檢查
+}
+
+var _ PasswordHasher = (*PasswordService)(nil)
+
+func NewPasswordService(policy PasswordPolicy, hasher PasswordHasher, history contract.PasswordHistoryRepository, breached BreachedPasswordChecker) *PasswordService {
+	if policy.MinLength <= 0 {
+		policy.MinLength = defaultPasswordMinLength
+	}
+	if limit := hasher.MaxPasswordLength(); policy.MaxLength <= 0 || policy.MaxLength > limit {
+		policy.MaxLength = limit
+	}
+	return &PasswordService{
+		policy:   policy,
+		hasher:   hasher,
+		history:  history,
+		breached: breached,
+	}
+}
+
+// Hash 以目前設定的演算法雜湊密碼
+func (s *PasswordService) Hash(password string) (string, error) {
+	return s.hasher.Hash(password)
+}
+
+// Verify 比對密碼與既有的雜湊（演算法由雜湊前綴判斷）
+func (s *PasswordService) Verify(password, hash string) bool {
+	return s.hasher.Verify(password, hash)
+}
+
+// NeedsRehash hash 是否應以目前的演算法與參數重新雜湊
+func (s *PasswordService) NeedsRehash(hash string) bool {
+	return s.hasher.NeedsRehash(hash)
+}
+
+// MaxPasswordLength 密碼政策允許的最大長度（位元組）
+func (s *PasswordService) MaxPasswordLength() int {
+	return s.policy.MaxLength
+}
+
+// Validate 列出密碼未符合的所有規則，全部符合時回傳 nil
+// user 為設定密碼的用戶，註冊時尚未建立（ID 為 0），不檢查密碼歷史
+func (s *PasswordService) Validate(ctx context.Context, password string, user *entity.User) ([]PasswordViolation, error) {
+	var violations []PasswordViolation
+	add := func(code, message string) {
+		violations = append(violations, PasswordViolation{Code: code, Message: message})
+	}
+
+	if utf8.RuneCountInString(password) < s.policy.MinLength {
+		add(PasswordTooShort, fmt.Sprintf("Password must be at least %d characters long", s.policy.MinLength))
+	}
+	// 上限以位元組計算，與雜湊演算法的限制一致
+	tooLong := len(password) > s.policy.MaxLength
+	if tooLong {
+		add(PasswordTooLong, fmt.Sprintf("Password must be at most %d bytes long", s.policy.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if s.policy.RequireUppercase && !hasUpper {
+		add(PasswordMissingUppercase, "Password must contain an uppercase letter")
+	}
+	if s.policy.RequireLowercase && !hasLower {
+		add(PasswordMissingLowercase, "Password must contain a lowercase letter")
+	}
+	if s.policy.RequireDigit && !hasDigit {
+		add(PasswordMissingDigit, "Password must contain a digit")
+	}
+	if s.policy.RequireSymbol && !hasSymbol {
+		add(PasswordMissingSymbol, "Password must contain a symbol")
+	}
+
+	if s.policy.DisallowUserInfo && containsUserInfo(password, user) {
+		add(PasswordContainsUserInfo, "Password must not contain your username or email")
+	}
+
+	// 過長的密碼不再比對密碼歷史與外洩清單，避免消耗雜湊運算
+	if tooLong {
+		return violations, nil
+	}
+
+	reused, err := s.recentlyUsed(ctx, password, user)
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		add(PasswordRecentlyUsed, fmt.Sprintf("Password must not match any of your last %d passwords", s.policy.HistorySize))
+	}
+
+	if s.breached != nil {
+		breached, err := s.breached.IsBreached(ctx, password)
+		if err != nil {
+			return nil, err
+		}
+		if breached {
+			add(PasswordBreached, "Password has appeared in a data breach, please choose another one")
+		}
+	}
+
+	return violations, nil
+}
+
+// Record 記錄新設定的密碼雜湊，只保留政策要求的筆數
+func (s *PasswordService) Record(ctx context.Context, userID int32, passwordHash string) error {
+	if s.policy.HistorySize <= 0 {
+		return nil
+	}
+	if err := s.history.Add(ctx, userID, passwordHash); err != nil {
+		return err
+	}
+	return s.history.Prune(ctx, userID, int32(s.policy.HistorySize))
+}
+
+// recentlyUsed 比對目前的密碼與最近的密碼歷史
+// 此功能上線前設定的密碼不在歷史中，因此另外比對目前的密碼雜湊
+func (s *PasswordService) recentlyUsed(ctx context.Context, password string, user *entity.User) (bool, error) {
+	if s.policy.HistorySize <= 0 || user == nil || user.ID == 0 {
+		return false, nil
+	}
+
+	hashes, err := s.history.ListRecent(ctx, user.ID, int32(s.policy.HistorySize))
+	if err != nil {
+		return false, err
+	}
+	if user.PasswordHash != "" {
+		hashes = append(hashes, user.PasswordHash)
+	}
+
+	seen := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		if s.hasher.Verify(password, hash) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// containsUserInfo 密碼（不分大小寫）是否包含 username 或 Email 的帳號部分
+func containsUserInfo(password string, user *entity.User) bool {
+	if user == nil {
+		return false
+	}
+
+	lowered := strings.ToLower(password)
+	localPart, _, _ := strings.Cut(user.Email, "@")
+	for _, info := range []string{user.Username, localPart} {
+		info = strings.ToLower(info)
+		if utf8.RuneCountInString(info) >= minUserInfoLength && strings.Contains(lowered, info) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func violationCodes(violations []PasswordViolation) []string {
+	codes := make([]string, len(violations))
+	for i, violation := range violations {
+		codes[i] = violation.Code
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func TestPasswordService_Validate(t *testing.T) {
+	strict := PasswordPolicy{
+		MinLength:        10,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		DisallowUserInfo: true,
+	}
+	user := &entity.User{Username: "alice", Email: "wonderland@example.com"}
+
+	testCases := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		expected []string
+	}{
+		{name: "DefaultMinLength", policy: PasswordPolicy{}, password: "short", expected: []string{PasswordTooShort}},
+		{name: "DefaultAccepts", policy: PasswordPolicy{}, password: "longenough", expected: []string{}},
+		{name: "StrictAccepts", policy: strict, password: "Tr0ub4dor&3x", expected: []string{}},
+		{name: "MissingClasses", policy: strict, password: "abcdefghijk", expected: []string{PasswordMissingDigit, PasswordMissingSymbol, PasswordMissingUppercase}},
+		{name: "UnicodeLength", policy: PasswordPolicy{MinLength: 4}, password: "密碼長度", expected: []string{}},
+		{name: "MaxLength", policy: PasswordPolicy{MaxLength: 12}, password: "longer-than-12", expected: []string{PasswordTooLong}},
+		{name: "BcryptLimit", policy: PasswordPolicy{MaxLength: 100}, password: strings.Repeat("a", 73), expected: []string{PasswordTooLong}},
+		{name: "BcryptLimitAccepts", policy: PasswordPolicy{}, password: strings.Repeat("a", 72), expected: []string{}},
+		{name: "ContainsUsername", policy: strict, password: "My-ALICE-pw-1", expected: []string{PasswordContainsUserInfo}},
+		{name: "ContainsEmailLocalPart", policy: strict, password: "Wonderland!23", expected: []string{PasswordContainsUserInfo}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewPasswordService(tc.policy, NewBcryptHasher(bcrypt.MinCost), mock.NewMockPasswordHistoryRepository(), nil)
+
+			violations, err := svc.Validate(context.Background(), tc.password, user)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			sort.Strings(tc.expected)
+			if got := violationCodes(violations); strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("Expected violations %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// writeBreachedFile 以排序後的 HASH:COUNT 格式寫入測試用外洩清單
+func writeBreachedFile(t *testing.T, passwords ...string) string {
+	t.Helper()
+
+	lines := []string{}
+	for _, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":42")
+	}
+	// 加入大量雜訊讓二分搜尋跨越多個區段
+	for i := 0; i < 500; i++ {
+		sum := sha1.Sum([]byte{byte(i), byte(i >> 8), 'x'})
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":1")
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return path
+}
+
+func TestBreachedPasswordFile(t *testing.T) {
+	path := writeBreachedFile(t, "password123", "letmein", "hunter2")
+	file, err := OpenBreachedPasswordFile(path)
+	if err != nil {
+		t.Fatalf("OpenBreachedPasswordFile failed: %v", err)
+	}
+	defer file.Close()
+
+	for _, password := range []string{"password123", "letmein", "hunter2"} {
+		if breached, err := file.IsBreached(context.Background(), password); err != nil || !breached {
+			t.Errorf("Expected %q to be breached, got %v, %v", password, breached, err)
+		}
+	}
+	for _, password := range []string{"correct horse battery staple", ""} {
+		if breached, err := file.IsBreached(context.Background(), password); err != nil || breached {
+			t.Errorf("Expected %q not to be breached, got %v, %v", password, breached, err)
+		}
+	}
+}
+
+func TestBreachedPasswordFile_Range(t *testing.T) {
+	path := writeBreachedFile(t, "password123")
+	file, err := OpenBreachedPasswordFile(path)
+	if err != nil {
+		t.Fatalf("OpenBreachedPasswordFile failed: %v", err)
+	}
+	defer file.Close()
+
+	content, _ := os.ReadFile(path)
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\r\n") {
+		hash := breachedHash(line)
+		suffixes, err := file.Range(strings.ToLower(hash[:breachedPrefixLength]))
+		if err != nil {
+			t.Fatalf("Range failed: %v", err)
+		}
+		found := false
+		for _, suffix := range suffixes {
+			found = found || suffix == hash[breachedPrefixLength:]
+		}
+		if !found {
+			t.Fatalf("Expected range %s to contain %s", hash[:breachedPrefixLength], hash)
+		}
+	}
+}
+
+func TestPasswordService_Breached(t *testing.T) {
+	file, err := OpenBreachedPasswordFile(writeBreachedFile(t, "password123"))
+	if err != nil {
+		t.Fatalf("OpenBreachedPasswordFile failed: %v", err)
+	}
+	defer file.Close()
+	svc := NewPasswordService(PasswordPolicy{}, NewBcryptHasher(bcrypt.MinCost), mock.NewMockPasswordHistoryRepository(), file)
+
+	violations, err := svc.Validate(context.Background(), "password123", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if codes := violationCodes(violations); len(codes) != 1 || codes[0] != PasswordBreached {
+		t.Errorf("Expected breached violation, got %v", codes)
+	}
+}
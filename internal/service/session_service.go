@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+)
+
+// sessionTouchInterval 最後活動時間的更新間隔，避免每個請求都寫入資料庫
+const sessionTouchInterval = time.Minute
+
+var ErrSessionRevoked = errors.New("session revoked")
+
+// SessionService 檢查 access token 所屬的登入 session 並記錄最後活動時間
+type SessionService struct {
+	repo contract.SessionRepository
+}
+
+func NewSessionService(repo contract.SessionRepository) *SessionService {
+	return &SessionService{
+		repo: repo,
+	}
+}
+
+// Active 取得仍有效的 session
+// session 不存在（已被清除）、已撤銷或已過期時回傳 ErrSessionRevoked
+func (s *SessionService) Active(ctx context.Context, sessionID string) (*entity.UserSession, error) {
+	session, err := s.repo.GetByID(ctx, sessionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSessionRevoked
+		}
+		return nil, err
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, ErrSessionRevoked
+	}
+	return session, nil
+}
+
+// Touch 記錄 session 的最後活動時間與 IP
+// 同一個 IP 在間隔內重複使用時不更新
+func (s *SessionService) Touch(ctx context.Context, session *entity.UserSession, ip string) error {
+	if session.IP == ip && time.Since(session.LastSeenAt) < sessionTouchInterval {
+		return nil
+	}
+	return s.repo.Touch(ctx, session.ID, ip)
+}
+
+// DeleteExpired 清除已過期或已撤銷的 session，回傳刪除筆數
+func (s *SessionService) DeleteExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+)
+
+func TestSessionService_Active(t *testing.T) {
+	repo := mock.NewMockSessionRepository()
+	svc := NewSessionService(repo)
+	now := time.Now()
+
+	_ = repo.Create(context.Background(), &entity.UserSession{ID: "active", UserID: 1, ExpiresAt: now.Add(time.Hour)})
+	_ = repo.Create(context.Background(), &entity.UserSession{ID: "expired", UserID: 1, ExpiresAt: now.Add(-time.Minute)})
+	_ = repo.Create(context.Background(), &entity.UserSession{ID: "revoked", UserID: 1, ExpiresAt: now.Add(time.Hour), RevokedAt: &now})
+
+	if session, err := svc.Active(context.Background(), "active"); err != nil || session.ID != "active" {
+		t.Errorf("Expected active session, got %v, %v", session, err)
+	}
+
+	for _, id := range []string{"expired", "revoked", "missing"} {
+		t.Run(id, func(t *testing.T) {
+			if _, err := svc.Active(context.Background(), id); err != ErrSessionRevoked {
+				t.Errorf("Expected error %v, got %v", ErrSessionRevoked, err)
+			}
+		})
+	}
+}
+
+func TestSessionService_TouchThrottled(t *testing.T) {
+	repo := mock.NewMockSessionRepository()
+	svc := NewSessionService(repo)
+	_ = repo.Create(context.Background(), &entity.UserSession{ID: "s", UserID: 1, IP: "10.0.0.1", ExpiresAt: time.Now().Add(time.Hour)})
+	session, _ := repo.GetByID(context.Background(), "s")
+
+	// 同一個 IP 在間隔內不更新
+	repo.Sessions["s"].LastSeenAt = session.LastSeenAt.Add(-time.Second)
+	if err := svc.Touch(context.Background(), session, "10.0.0.1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !repo.Sessions["s"].LastSeenAt.Before(session.LastSeenAt) {
+		t.Error("Expected touch to be skipped within the interval")
+	}
+
+	// IP 改變時立即更新
+	if err := svc.Touch(context.Background(), session, "10.0.0.2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.Sessions["s"].IP != "10.0.0.2" {
+		t.Error("Expected IP to be updated")
+	}
+}
+
+func TestSessionService_DeleteExpired(t *testing.T) {
+	repo := mock.NewMockSessionRepository()
+	svc := NewSessionService(repo)
+	now := time.Now()
+	_ = repo.Create(context.Background(), &entity.UserSession{ID: "active", ExpiresAt: now.Add(time.Hour)})
+	_ = repo.Create(context.Background(), &entity.UserSession{ID: "expired", ExpiresAt: now.Add(-time.Minute)})
+	_ = repo.Create(context.Background(), &entity.UserSession{ID: "revoked", ExpiresAt: now.Add(time.Hour), RevokedAt: &now})
+
+	deleted, err := svc.DeleteExpired(context.Background())
+	if err != nil || deleted != 2 {
+		t.Errorf("Expected 2 deleted, got %d, %v", deleted, err)
+	}
+	if _, ok := repo.Sessions["active"]; !ok {
+		t.Error("Expected active session to remain")
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+)
+
+// TokenRevocationService 負責 access token 的撤銷與檢查
+type TokenRevocationService struct {
+	store    contract.RevokedTokenStore
+	userRepo contract.UserRepository
+}
+
+func NewTokenRevocationService(store contract.RevokedTokenStore, userRepo contract.UserRepository) *TokenRevocationService {
+	return &TokenRevocationService{
+		store:    store,
+		userRepo: userRepo,
+	}
+}
+
+// RevokeToken 將 token 的 jti 加入撤銷清單，保留到 token 原本的過期時間
+func (s *TokenRevocationService) RevokeToken(ctx context.Context, claims *Claims) error {
+	return s.store.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+// ConsumeOnce 將一次性 token 的 jti 標記為已使用，已使用過時回傳 false
+func (s *TokenRevocationService) ConsumeOnce(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	return s.store.Consume(ctx, jti, expiresAt)
+}
+
+// IsRevoked 檢查 token 是否已失效：
+// 1. jti 在撤銷清單中（登出）
+// 2. token 版本落後於用戶目前的版本（修改密碼、登出所有裝置）
+// 3. 用戶已不存在（帳號已刪除）
+func (s *TokenRevocationService) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	revoked, err := s.store.IsRevoked(ctx, claims.ID)
+	if err != nil || revoked {
+		return revoked, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return claims.TokenVersion != user.TokenVersion, nil
+}
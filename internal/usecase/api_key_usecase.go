@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/response"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+)
+
+// APIKeyUseCase 管理用戶的個人 API 金鑰
+type APIKeyUseCase struct {
+	apiKeyRepo    contract.APIKeyRepository
+	roleRepo      contract.RoleRepository
+	apiKeys       *service.APIKeyService
+	authorization *service.AuthorizationService
+}
+
+func NewAPIKeyUseCase(
+	apiKeyRepo contract.APIKeyRepository,
+	roleRepo contract.RoleRepository,
+	apiKeys *service.APIKeyService,
+	authorization *service.AuthorizationService,
+) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepo:    apiKeyRepo,
+		roleRepo:      roleRepo,
+		apiKeys:       apiKeys,
+		authorization: authorization,
+	}
+}
+
+// Create 建立新金鑰，scopes 必須是用戶目前擁有的權限
+func (u *APIKeyUseCase) Create(ctx context.Context, userID int32, req request.CreateAPIKeyRequest) (*response.CreateAPIKeyResponse, error) {
+	scopes, err := u.validateScopes(ctx, userID, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, prefix, keyHash, err := u.apiKeys.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &entity.APIKey{
+		UserID:  userID,
+		Name:    req.Name,
+		Prefix:  prefix,
+		KeyHash: keyHash,
+		Scopes:  scopes,
+	}
+	if req.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := u.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &response.CreateAPIKeyResponse{
+		APIKeyResponse: response.NewAPIKeyResponse(key),
+		Key:            rawKey,
+	}, nil
+}
+
+// List 列出用戶未撤銷的金鑰
+func (u *APIKeyUseCase) List(ctx context.Context, userID int32) ([]*response.APIKeyResponse, error) {
+	keys, err := u.apiKeyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyResponses := make([]*response.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		keyResponses[i] = response.NewAPIKeyResponse(key)
+	}
+	return keyResponses, nil
+}
+
+// Revoke 撤銷金鑰，只能撤銷自己的金鑰
+func (u *APIKeyUseCase) Revoke(ctx context.Context, userID, keyID int32) error {
+	revoked, err := u.apiKeyRepo.Revoke(ctx, keyID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return customerrors.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// validateScopes 去除重複並確認每個 scope 都是用戶目前擁有的權限
+func (u *APIKeyUseCase) validateScopes(ctx context.Context, userID int32, scopes []string) ([]string, error) {
+	roles, err := u.roleRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := u.authorization.Permissions(ctx, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	validated := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !permissions[scope] {
+			return nil, customerrors.ErrInvalidAPIKeyScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			validated = append(validated, scope)
+		}
+	}
+	sort.Strings(validated)
+	return validated, nil
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+)
+
+func newTestAPIKeyUseCase(roles ...string) (*APIKeyUseCase, *mock.MockAPIKeyRepository) {
+	apiKeyRepo := mock.NewMockAPIKeyRepository()
+	roleRepo := mock.NewMockRoleRepository()
+	roleRepo.UserRoles[1] = roles
+	userRepo := &mock.SimpleMockUserRepository{User: &entity.User{ID: 1}}
+
+	return NewAPIKeyUseCase(
+		apiKeyRepo,
+		roleRepo,
+		service.NewAPIKeyService(apiKeyRepo, userRepo, roleRepo),
+		service.NewAuthorizationService(roleRepo, time.Minute),
+	), apiKeyRepo
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	uc, repo := newTestAPIKeyUseCase(entity.RoleAdmin)
+	days := 30
+
+	resp, err := uc.Create(context.Background(), 1, request.CreateAPIKeyRequest{
+		Name:          "ci",
+		Scopes:        []string{entity.PermissionUsersList, entity.PermissionUsersDelete, entity.PermissionUsersList},
+		ExpiresInDays: &days,
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if resp.Key == "" || resp.Prefix == "" || resp.Key[:len(resp.Prefix)] != resp.Prefix {
+		t.Fatalf("Expected key with prefix %q, got %q", resp.Prefix, resp.Key)
+	}
+	if len(resp.Scopes) != 2 || resp.Scopes[0] != entity.PermissionUsersDelete || resp.Scopes[1] != entity.PermissionUsersList {
+		t.Errorf("Expected sorted, de-duplicated scopes, got %v", resp.Scopes)
+	}
+	if resp.ExpiresAt == nil || time.Until(*resp.ExpiresAt) < 29*24*time.Hour {
+		t.Errorf("Expected expiry in 30 days, got %v", resp.ExpiresAt)
+	}
+
+	stored := repo.Keys[0]
+	if stored.KeyHash != utils.HashToken(resp.Key) {
+		t.Error("Expected only the key hash to be stored")
+	}
+}
+
+func TestCreateAPIKey_ScopeNotGranted(t *testing.T) {
+	uc, repo := newTestAPIKeyUseCase(entity.RoleUser)
+
+	_, err := uc.Create(context.Background(), 1, request.CreateAPIKeyRequest{
+		Name:   "ci",
+		Scopes: []string{entity.PermissionUsersDelete},
+	})
+	if err != customerrors.ErrInvalidAPIKeyScope {
+		t.Fatalf("Expected ErrInvalidAPIKeyScope, got %v", err)
+	}
+	if len(repo.Keys) != 0 {
+		t.Error("Expected no key to be created")
+	}
+}
+
+func TestListAndRevokeAPIKeys(t *testing.T) {
+	uc, _ := newTestAPIKeyUseCase(entity.RoleUser)
+	ctx := context.Background()
+
+	first, err := uc.Create(ctx, 1, request.CreateAPIKeyRequest{Name: "first"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := uc.Create(ctx, 1, request.CreateAPIKeyRequest{Name: "second"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if err := uc.Revoke(ctx, 1, first.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+
+	keys, err := uc.List(ctx, 1)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(keys) != 1 || keys[0].Name != "second" {
+		t.Errorf("Expected only the second key to be listed, got %+v", keys)
+	}
+
+	// 已撤銷或不屬於自己的金鑰
+	if err := uc.Revoke(ctx, 1, first.ID); err != customerrors.ErrAPIKeyNotFound {
+		t.Errorf("Expected ErrAPIKeyNotFound for revoked key, got %v", err)
+	}
+	if err := uc.Revoke(ctx, 2, keys[0].ID); err != customerrors.ErrAPIKeyNotFound {
+		t.Errorf("Expected ErrAPIKeyNotFound for another user's key, got %v", err)
+	}
+}
@@ -3,26 +3,78 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
 	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
 	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/response"
 	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
 	"github.com/dinosaur1258/GolangFramework/pkg/database"
 	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"github.com/google/uuid"
 )
 
+// refreshTokenBytes refresh token 的隨機位元組數
+const refreshTokenBytes = 32
+
+// AuthConfig AuthUseCase 的行為設定
+type AuthConfig struct {
+	RefreshTTL               time.Duration
+	RequireEmailVerification bool // 未驗證 Email 的帳號無法登入
+
+	// 連續登入失敗達到 LockoutThreshold 次後鎖定帳號（0 表示停用）
+	// 鎖定時間從 LockoutBaseDuration 開始，每多失敗一次加倍，最長 LockoutMaxDuration
+	LockoutThreshold    int32
+	LockoutBaseDuration time.Duration
+	LockoutMaxDuration  time.Duration
+
+	MFAPendingTTL time.Duration // 登入第一步取得的 MFA token 效期
+
+	ImpersonationTTL time.Duration // 管理員代登入 token 的效期
+}
+
 type AuthUseCase struct {
-	userRepo contract.UserRepository
-	db       *sql.DB // ⭐ 新增:需要 DB 來執行事務
+	userRepo         contract.UserRepository
+	refreshTokenRepo contract.RefreshTokenRepository
+	roleRepo         contract.RoleRepository
+	sessionRepo      contract.SessionRepository
+	jwtService       *service.JWTService
+	tokenRevocation  *service.TokenRevocationService
+	passwords        *service.PasswordService
+	mfa              *service.MFAService
+	actionTokens     *service.ActionTokenService
+	db               *sql.DB // ⭐ 新增:需要 DB 來執行事務
+	cfg              AuthConfig
 }
 
 // ⭐ 修改:建構子需要傳入 db
-func NewAuthUseCase(userRepo contract.UserRepository, db *sql.DB) *AuthUseCase {
+func NewAuthUseCase(
+	userRepo contract.UserRepository,
+	refreshTokenRepo contract.RefreshTokenRepository,
+	roleRepo contract.RoleRepository,
+	sessionRepo contract.SessionRepository,
+	jwtService *service.JWTService,
+	tokenRevocation *service.TokenRevocationService,
+	passwords *service.PasswordService,
+	mfa *service.MFAService,
+	actionTokens *service.ActionTokenService,
+	db *sql.DB,
+	cfg AuthConfig,
+) *AuthUseCase {
 	return &AuthUseCase{
-		userRepo: userRepo,
-		db:       db,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		roleRepo:         roleRepo,
+		sessionRepo:      sessionRepo,
+		jwtService:       jwtService,
+		tokenRevocation:  tokenRevocation,
+		passwords:        passwords,
+		mfa:              mfa,
+		actionTokens:     actionTokens,
+		db:               db,
+		cfg:              cfg,
 	}
 }
 
@@ -46,29 +98,42 @@ func (a *AuthUseCase) Register(ctx context.Context, req request.RegisterRequest)
 		return nil, customerrors.ErrUserAlreadyExists
 	}
 
-	// 密碼加密
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	// 其他用戶變更前的 username 在保留期間內無法註冊
+	reserved, err := a.userRepo.UsernameReserved(ctx, req.Username, 0)
 	if err != nil {
 		return nil, err
 	}
+	if reserved {
+		return nil, customerrors.ErrUserAlreadyExists
+	}
 
-	// 建立用戶
+	// 檢查密碼政策並加密
 	user := &entity.User{
-		Username:     req.Username,
-		Email:        req.Email,
-		PasswordHash: string(hashedPassword),
+		Username: req.Username,
+		Email:    req.Email,
+	}
+	if err := checkPassword(ctx, a.passwords, "password", req.Password, user); err != nil {
+		return nil, err
+	}
+	user.PasswordHash, err = a.passwords.Hash(req.Password)
+	if err != nil {
+		return nil, err
 	}
 
+	// 建立用戶
 	if err := a.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
+	if err := a.passwords.Record(ctx, user.ID, user.PasswordHash); err != nil {
+		return nil, err
+	}
 
-	return &response.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	// 指派預設角色
+	if err := a.roleRepo.AssignRole(ctx, user.ID, entity.RoleUser); err != nil {
+		return nil, err
+	}
+
+	return response.NewUserResponse(user), nil
 }
 
 // ⭐ 新增:使用事務的註冊方法
@@ -78,7 +143,7 @@ func (a *AuthUseCase) RegisterWithTransaction(ctx context.Context, req request.R
 	var result *response.UserResponse
 
 	// 使用事務執行
-	err := database.WithTransaction(a.db, func(txCtx context.Context) error {
+	err := database.WithTransaction(ctx, a.db, func(txCtx context.Context) error {
 		// 1. 檢查 email 是否已存在(在事務中)
 		existingUser, err := a.userRepo.GetByEmail(txCtx, req.Email)
 		if err != nil && err != sql.ErrNoRows {
@@ -96,34 +161,45 @@ func (a *AuthUseCase) RegisterWithTransaction(ctx context.Context, req request.R
 		if existingUser != nil {
 			return customerrors.ErrUserAlreadyExists
 		}
-
-		// 3. 密碼加密
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		reserved, err := a.userRepo.UsernameReserved(txCtx, req.Username, 0)
 		if err != nil {
 			return err
 		}
+		if reserved {
+			return customerrors.ErrUserAlreadyExists
+		}
 
-		// 4. 建立用戶(在事務中)
+		// 3. 檢查密碼政策並加密
 		user := &entity.User{
-			Username:     req.Username,
-			Email:        req.Email,
-			PasswordHash: string(hashedPassword),
+			Username: req.Username,
+			Email:    req.Email,
+		}
+		if err := checkPassword(txCtx, a.passwords, "password", req.Password, user); err != nil {
+			return err
+		}
+		user.PasswordHash, err = a.passwords.Hash(req.Password)
+		if err != nil {
+			return err
 		}
 
+		// 4. 建立用戶與密碼歷史(在事務中)
 		if err := a.userRepo.Create(txCtx, user); err != nil {
 			return err // 失敗會自動 rollback
 		}
+		if err := a.passwords.Record(txCtx, user.ID, user.PasswordHash); err != nil {
+			return err
+		}
 
-		// 5. 如果將來需要做其他操作(例如:寫入 audit log)
+		// 5. 指派預設角色(在事務中)
+		if err := a.roleRepo.AssignRole(txCtx, user.ID, entity.RoleUser); err != nil {
+			return err
+		}
+
+		// 如果將來需要做其他操作(例如:寫入 audit log)
 		// 都會在同一個事務中,要麼全成功,要麼全失敗
 
 		// 6. 準備返回結果
-		result = &response.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt,
-		}
+		result = response.NewUserResponse(user)
 
 		return nil // 成功,會自動 commit
 	})
@@ -135,7 +211,7 @@ func (a *AuthUseCase) RegisterWithTransaction(ctx context.Context, req request.R
 	return result, nil
 }
 
-// Login 用戶登入(保持不變)
+// Login 用戶登入，並開啟一個新的 refresh token family
 func (a *AuthUseCase) Login(ctx context.Context, req request.LoginRequest) (*response.LoginResponse, error) {
 	// 根據 email 取得用戶
 	user, err := a.userRepo.GetByEmail(ctx, req.Email)
@@ -146,19 +222,431 @@ func (a *AuthUseCase) Login(ctx context.Context, req request.LoginRequest) (*res
 		return nil, err
 	}
 
+	// 鎖定期間不驗證密碼，避免繼續被猜測
+	if user.IsLocked(time.Now()) {
+		return nil, customerrors.ErrAccountLocked
+	}
+
 	// 驗證密碼
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+	if !a.passwords.Verify(req.Password, user.PasswordHash) {
+		locked, err := a.recordLoginFailure(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			return nil, customerrors.ErrAccountLocked
+		}
 		return nil, customerrors.ErrInvalidCredentials
 	}
 
-	// 生成 Token (這裡先返回空字串,等等會在 handler 生成)
+	// 密碼正確後才檢查 Email 驗證狀態，避免洩漏帳號是否存在
+	if a.cfg.RequireEmailVerification && !user.IsEmailVerified() {
+		return nil, customerrors.ErrEmailNotVerified
+	}
+
+	// 雜湊以舊演算法或舊參數產生時，趁有明文密碼時換成目前的設定
+	if err := a.rehashPassword(ctx, user, req.Password); err != nil {
+		return nil, err
+	}
+
+	return a.startSession(ctx, user)
+}
+
+// rehashPassword 依目前的雜湊設定更新用戶的密碼雜湊（密碼本身不變）
+// 只寫入密碼雜湊且以舊雜湊比對，同時間密碼已被變更時放棄升級
+func (a *AuthUseCase) rehashPassword(ctx context.Context, user *entity.User, password string) error {
+	if !a.passwords.NeedsRehash(user.PasswordHash) {
+		return nil
+	}
+
+	hashedPassword, err := a.passwords.Hash(password)
+	if err != nil {
+		return err
+	}
+	updated, err := a.userRepo.UpdatePasswordHash(ctx, user.ID, user.PasswordHash, hashedPassword)
+	if err != nil {
+		return err
+	}
+	if updated {
+		user.PasswordHash = hashedPassword
+	}
+	return nil
+}
+
+// startSession 第一步驗證（密碼或外部身分）通過後建立登入 session
+// 已啟用兩步驟驗證時先回傳短效的 MFA token，驗證碼通過後才簽發正式 token
+// 失敗次數要到兩步驟驗證通過後才清除，否則知道密碼的人可以無限次猜測驗證碼
+func (a *AuthUseCase) startSession(ctx context.Context, user *entity.User) (*response.LoginResponse, error) {
+	mfaEnabled, err := a.mfa.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled {
+		mfaToken, err := a.actionTokens.GenerateToken(service.PurposeMFAPending, user.ID, user.Email, a.cfg.MFAPendingTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &response.LoginResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}, nil
+	}
+
+	return a.completeLogin(ctx, user)
+}
+
+// VerifyMFA 以登入第一步取得的 MFA token 與驗證碼（TOTP 或備用碼）換發正式 token
+// 驗證碼錯誤與密碼錯誤一樣計入連續失敗次數
+func (a *AuthUseCase) VerifyMFA(ctx context.Context, req request.VerifyMFARequest) (*response.LoginResponse, error) {
+	userID, claims, err := a.actionTokens.ValidateToken(req.MFAToken, service.PurposeMFAPending)
+	if err != nil {
+		return nil, customerrors.ErrInvalidMFAToken
+	}
+
+	user, err := a.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	if user.Email != claims.Email {
+		return nil, customerrors.ErrInvalidMFAToken
+	}
+
+	if user.IsLocked(time.Now()) {
+		return nil, customerrors.ErrAccountLocked
+	}
+
+	valid, err := a.mfa.VerifyCode(ctx, user.ID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		locked, err := a.recordLoginFailure(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			return nil, customerrors.ErrAccountLocked
+		}
+		return nil, customerrors.ErrInvalidMFACode
+	}
+
+	return a.completeLogin(ctx, user)
+}
+
+// completeLogin 清除失敗次數並簽發 token，開啟新的 refresh token family
+func (a *AuthUseCase) completeLogin(ctx context.Context, user *entity.User) (*response.LoginResponse, error) {
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := a.userRepo.ResetLoginFailures(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// session 與 refresh token family 使用相同的 ID
+	session := &entity.UserSession{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(a.cfg.RefreshTTL),
+	}
+	client := clientInfoFromContext(ctx)
+	session.UserAgent, session.IP = client.UserAgent, client.IP
+	if err := a.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := a.issueRefreshToken(ctx, user.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.buildLoginResponse(ctx, user, session.ID, refreshToken)
+}
+
+// recordLoginFailure 記錄一次登入失敗，達到門檻時鎖定帳號並回傳 true
+func (a *AuthUseCase) recordLoginFailure(ctx context.Context, userID int32) (bool, error) {
+	attempts, err := a.userRepo.RecordLoginFailure(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if a.cfg.LockoutThreshold <= 0 || attempts < a.cfg.LockoutThreshold {
+		return false, nil
+	}
+
+	until := time.Now().Add(a.lockoutDuration(attempts))
+	if err := a.userRepo.Lock(ctx, userID, until); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// lockoutDuration 依失敗次數計算鎖定時間（指數退避）
+func (a *AuthUseCase) lockoutDuration(attempts int32) time.Duration {
+	duration := a.cfg.LockoutBaseDuration
+	for i := a.cfg.LockoutThreshold; i < attempts; i++ {
+		duration *= 2
+		if duration >= a.cfg.LockoutMaxDuration {
+			return a.cfg.LockoutMaxDuration
+		}
+	}
+	return min(duration, a.cfg.LockoutMaxDuration)
+}
+
+// RefreshToken 使用 refresh token 換發新的 token（每次使用都會輪替）
+// 若已輪替過的 token 再次出現，視為遭竊並撤銷整個 token family
+func (a *AuthUseCase) RefreshToken(ctx context.Context, rawToken string) (*response.LoginResponse, error) {
+	stored, err := a.refreshTokenRepo.GetByHash(ctx, utils.HashToken(rawToken))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	// 已撤銷的 token 再次被使用：撤銷整個 family 與其 session
+	if stored.RevokedAt != nil {
+		if err := a.revokeSession(ctx, stored.UserID, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, customerrors.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, customerrors.ErrInvalidRefreshToken
+	}
+
+	// session 已被撤銷（例如從其他裝置移除）時不再換發
+	session, err := a.sessionRepo.GetByID(ctx, stored.FamilyID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if session != nil && session.RevokedAt != nil {
+		return nil, customerrors.ErrInvalidRefreshToken
+	}
+
+	// 撤銷舊 token、延長 session 與簽發新 token 必須一起成功，避免舊 token 已失效卻沒有拿到新 token 而被迫重新登入
+	var user *entity.User
+	var refreshToken string
+	err = database.WithTransaction(ctx, a.db, func(txCtx context.Context) error {
+		// 以條件式更新撤銷舊 token，避免同一個 token 被併發使用兩次
+		revoked, err := a.refreshTokenRepo.Revoke(txCtx, stored.ID)
+		if err != nil {
+			return err
+		}
+		if !revoked {
+			return customerrors.ErrRefreshTokenReused
+		}
+
+		user, err = a.userRepo.GetByID(txCtx, stored.UserID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return customerrors.ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		if err := a.extendSession(txCtx, user.ID, stored.FamilyID); err != nil {
+			return err
+		}
+
+		refreshToken, err = a.issueRefreshToken(txCtx, user.ID, stored.FamilyID)
+		return err
+	})
+	if err == customerrors.ErrRefreshTokenReused {
+		// 撤銷 session 必須在 rollback 之後執行，否則會一併被還原
+		if err := a.revokeSession(ctx, stored.UserID, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, customerrors.ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return a.buildLoginResponse(ctx, user, stored.FamilyID, refreshToken)
+}
+
+// extendSession 換發 token 時更新 session 的最後活動時間並延長效期
+// 此功能上線前開啟的 token family 沒有 session，第一次換發時補建
+func (a *AuthUseCase) extendSession(ctx context.Context, userID int32, sessionID string) error {
+	client := clientInfoFromContext(ctx)
+	expiresAt := time.Now().Add(a.cfg.RefreshTTL)
+
+	found, err := a.sessionRepo.Extend(ctx, sessionID, client.IP, expiresAt)
+	if err != nil || found {
+		return err
+	}
+
+	return a.sessionRepo.Create(ctx, &entity.UserSession{
+		ID:        sessionID,
+		UserID:    userID,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// revokeSession 撤銷 session 與對應的 refresh token family
+func (a *AuthUseCase) revokeSession(ctx context.Context, userID int32, sessionID string) error {
+	if _, err := a.sessionRepo.Revoke(ctx, sessionID, userID); err != nil {
+		return err
+	}
+	return a.refreshTokenRepo.RevokeFamily(ctx, sessionID)
+}
+
+// Logout 登出目前的裝置：撤銷目前的 access token 與所屬的 session，
+// 若有提供 refresh token 則一併撤銷其 token family
+func (a *AuthUseCase) Logout(ctx context.Context, claims *service.Claims, rawRefreshToken string) error {
+	if err := a.tokenRevocation.RevokeToken(ctx, claims); err != nil {
+		return err
+	}
+
+	if claims.SessionID != "" {
+		if err := a.revokeSession(ctx, claims.UserID, claims.SessionID); err != nil {
+			return err
+		}
+	}
+
+	// 代登入只結束代登入本身，不可撤銷被代登入用戶的 session
+	if rawRefreshToken == "" || claims.IsImpersonated() {
+		return nil
+	}
+
+	stored, err := a.refreshTokenRepo.GetByHash(ctx, utils.HashToken(rawRefreshToken))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	// 只允許撤銷屬於自己的 refresh token
+	if stored.UserID != claims.UserID {
+		return nil
+	}
+
+	return a.revokeSession(ctx, stored.UserID, stored.FamilyID)
+}
+
+// LogoutAll 登出所有裝置：遞增 token 版本並撤銷所有 session 與 refresh token
+func (a *AuthUseCase) LogoutAll(ctx context.Context, userID int32) error {
+	if err := revokeAllLogins(ctx, a.userRepo, a.sessionRepo, a.refreshTokenRepo, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// revokeAllLogins 遞增 token 版本並撤銷所有 session 與 refresh token，用戶不存在時回傳 sql.ErrNoRows
+// 登出所有裝置、修改與重設密碼等操作共用，避免已失效的登入仍留在裝置清單中
+func revokeAllLogins(
+	ctx context.Context,
+	userRepo contract.UserRepository,
+	sessionRepo contract.SessionRepository,
+	refreshTokenRepo contract.RefreshTokenRepository,
+	userID int32,
+) error {
+	if _, err := userRepo.IncrementTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+	if err := sessionRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	return refreshTokenRepo.RevokeAllForUser(ctx, userID)
+}
+
+// Impersonate 讓管理員以指定用戶的身分操作（客服重現問題用）
+// 簽發的 token 帶有 act claim 記錄實際操作的管理員，不開啟 session 也沒有 refresh token
+// 本身也能代登入他人的用戶不可被代登入，避免藉此取得其他管理員的權限
+func (a *AuthUseCase) Impersonate(ctx context.Context, actorID, userID int32) (*response.ImpersonationResponse, error) {
+	if actorID == userID {
+		return nil, customerrors.ErrCannotImpersonate
+	}
+
+	actor, err := a.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+	user, err := a.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	roles, err := a.roleRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		permissions, err := a.roleRepo.GetRolePermissions(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		for _, permission := range permissions {
+			if permission == entity.PermissionUsersImpersonate {
+				return nil, customerrors.ErrCannotImpersonate
+			}
+		}
+	}
+
+	token, err := a.jwtService.GenerateImpersonationToken(user, roles, actor, a.cfg.ImpersonationTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.ImpersonationResponse{
+		Token:          token,
+		ExpiresIn:      int64(a.cfg.ImpersonationTTL.Seconds()),
+		User:           response.NewUserResponse(user),
+		ImpersonatorID: actor.ID,
+	}, nil
+}
+
+// buildLoginResponse 產生 access token 並組成登入回應
+func (a *AuthUseCase) buildLoginResponse(ctx context.Context, user *entity.User, sessionID, refreshToken string) (*response.LoginResponse, error) {
+	roles, err := a.roleRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := a.jwtService.GenerateToken(user, roles, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &response.LoginResponse{
-		Token: "", // 將在 handler 層生成
-		User: &response.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt,
-		},
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(a.jwtService.AccessTTL().Seconds()),
+		User:         response.NewUserResponse(user),
 	}, nil
 }
+
+// issueRefreshToken 產生新的 refresh token 並儲存其雜湊值
+func (a *AuthUseCase) issueRefreshToken(ctx context.Context, userID int32, familyID string) (string, error) {
+	rawToken, err := utils.GenerateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return "", err
+	}
+
+	token := &entity.RefreshToken{
+		UserID:    userID,
+		TokenHash: utils.HashToken(rawToken),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(a.cfg.RefreshTTL),
+	}
+	if err := a.refreshTokenRepo.Create(ctx, token); err != nil {
+		return "", err
+	}
+
+	return rawToken, nil
+}
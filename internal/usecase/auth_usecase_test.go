@@ -0,0 +1,425 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/memory"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type authTestEnv struct {
+	useCase         *AuthUseCase
+	userRepo        *mock.SimpleMockUserRepository
+	refreshRepo     *mock.MockRefreshTokenRepository
+	sessionRepo     *mock.MockSessionRepository
+	passwordHistory *mock.MockPasswordHistoryRepository
+	passwords       *service.PasswordService
+	mfaRepo         *mock.MockMFARepository
+	jwtService      *service.JWTService
+	tokenRevocation *service.TokenRevocationService
+	tx              *testTxLog
+}
+
+func newTestAuthUseCase(t *testing.T) (*AuthUseCase, *mock.MockRefreshTokenRepository) {
+	env := newAuthTestEnv(t)
+	return env.useCase, env.refreshRepo
+}
+
+func newAuthTestEnv(t *testing.T) *authTestEnv {
+	t.Helper()
+
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	userRepo := &mock.SimpleMockUserRepository{
+		User: &entity.User{
+			ID:           1,
+			Username:     "testuser",
+			Email:        "test@example.com",
+			PasswordHash: string(passwordHash),
+		},
+	}
+	refreshRepo := mock.NewMockRefreshTokenRepository()
+	sessionRepo := mock.NewMockSessionRepository()
+	passwordHistory := mock.NewMockPasswordHistoryRepository()
+	passwords := service.NewPasswordService(service.PasswordPolicy{}, newTestHasher(), passwordHistory, nil)
+	jwtService := service.NewJWTService("test-secret", 15*time.Minute)
+	tokenRevocation := service.NewTokenRevocationService(memory.NewRevokedTokenStore(), userRepo)
+	mfaRepo := mock.NewMockMFARepository()
+	mfaService := service.NewMFAService(mfaRepo, "Test")
+	actionTokens := service.NewActionTokenService("action-secret")
+	db, tx := newTestDB(t)
+
+	return &authTestEnv{
+		useCase: NewAuthUseCase(userRepo, refreshRepo, mock.NewMockRoleRepository(), sessionRepo, jwtService, tokenRevocation, passwords, mfaService, actionTokens, db, AuthConfig{
+			RefreshTTL:    time.Hour,
+			MFAPendingTTL: 5 * time.Minute,
+		}),
+		userRepo:        userRepo,
+		refreshRepo:     refreshRepo,
+		sessionRepo:     sessionRepo,
+		passwordHistory: passwordHistory,
+		passwords:       passwords,
+		mfaRepo:         mfaRepo,
+		jwtService:      jwtService,
+		tokenRevocation: tokenRevocation,
+		tx:              tx,
+	}
+}
+
+func login(t *testing.T, uc *AuthUseCase) string {
+	t.Helper()
+
+	resp, err := uc.Login(context.Background(), request.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if resp.RefreshToken == "" {
+		t.Fatal("Expected refresh token to be issued on login")
+	}
+	return resp.RefreshToken
+}
+
+// =============================================================================
+// Register Tests
+// =============================================================================
+
+func TestRegister_ReservedUsername(t *testing.T) {
+	env := newAuthTestEnv(t)
+	env.userRepo.GetByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+		return nil, sql.ErrNoRows
+	}
+	env.userRepo.GetByUsernameFunc = func(ctx context.Context, username string) (*entity.User, error) {
+		return nil, sql.ErrNoRows
+	}
+	env.userRepo.CreateFunc = func(ctx context.Context, user *entity.User) error {
+		t.Error("Expected no user to be created")
+		return nil
+	}
+	env.userRepo.ReservedUsernames = map[string]int32{"alice": 2}
+
+	_, err := env.useCase.Register(context.Background(), request.RegisterRequest{
+		Username: "alice",
+		Email:    "new@example.com",
+		Password: "password123",
+	})
+	if err != customerrors.ErrUserAlreadyExists {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrUserAlreadyExists, err)
+	}
+}
+
+// =============================================================================
+// RefreshToken Tests
+// =============================================================================
+
+func TestRefreshToken_Rotates(t *testing.T) {
+	uc, _ := newTestAuthUseCase(t)
+	first := login(t, uc)
+
+	resp, err := uc.RefreshToken(context.Background(), first)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.RefreshToken == "" || resp.RefreshToken == first {
+		t.Error("Expected a new refresh token")
+	}
+	if resp.User == nil || resp.User.ID != 1 {
+		t.Error("Expected user in response")
+	}
+
+	// 新的 token 應可繼續使用
+	if _, err := uc.RefreshToken(context.Background(), resp.RefreshToken); err != nil {
+		t.Errorf("Expected rotated token to be valid, got %v", err)
+	}
+}
+
+func TestRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	uc, repo := newTestAuthUseCase(t)
+	first := login(t, uc)
+
+	resp, err := uc.RefreshToken(context.Background(), first)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 再次使用已輪替的 token
+	if _, err := uc.RefreshToken(context.Background(), first); err != customerrors.ErrRefreshTokenReused {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrRefreshTokenReused, err)
+	}
+
+	// 同一 family 的最新 token 也應被撤銷
+	if token := repo.Tokens[utils.HashToken(resp.RefreshToken)]; token.RevokedAt == nil {
+		t.Error("Expected the whole token family to be revoked")
+	}
+	if _, err := uc.RefreshToken(context.Background(), resp.RefreshToken); err != customerrors.ErrRefreshTokenReused {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrRefreshTokenReused, err)
+	}
+}
+
+func TestRefreshToken_Transaction(t *testing.T) {
+	t.Run("Commit", func(t *testing.T) {
+		env := newAuthTestEnv(t)
+		first := login(t, env.useCase)
+		before := len(env.tx.Ops())
+
+		if _, err := env.useCase.RefreshToken(context.Background(), first); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ops := env.tx.Ops()[before:]; !slices.Equal(ops, []string{"begin", "commit"}) {
+			t.Errorf("Expected one committed transaction, got %v", ops)
+		}
+	})
+
+	t.Run("RollbackOnFailure", func(t *testing.T) {
+		env := newAuthTestEnv(t)
+		first := login(t, env.useCase)
+		before := len(env.tx.Ops())
+
+		errLookup := errors.New("lookup failed")
+		env.userRepo.GetByIDFunc = func(ctx context.Context, id int32) (*entity.User, error) {
+			return nil, errLookup
+		}
+		if _, err := env.useCase.RefreshToken(context.Background(), first); !errors.Is(err, errLookup) {
+			t.Fatalf("Expected lookup error, got %v", err)
+		}
+		// 簽發新 token 前失敗時撤銷舊 token 一併 rollback
+		if ops := env.tx.Ops()[before:]; !slices.Equal(ops, []string{"begin", "rollback"}) {
+			t.Errorf("Expected transaction to be rolled back, got %v", ops)
+		}
+	})
+}
+
+func TestRefreshToken_Invalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		setup func(repo *mock.MockRefreshTokenRepository) string
+	}{
+		{
+			name: "UnknownToken",
+			setup: func(repo *mock.MockRefreshTokenRepository) string {
+				return "does-not-exist"
+			},
+		},
+		{
+			name: "Expired",
+			setup: func(repo *mock.MockRefreshTokenRepository) string {
+				_ = repo.Create(context.Background(), &entity.RefreshToken{
+					UserID:    1,
+					TokenHash: utils.HashToken("expired-token"),
+					FamilyID:  "family",
+					ExpiresAt: time.Now().Add(-time.Minute),
+				})
+				return "expired-token"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc, repo := newTestAuthUseCase(t)
+			raw := tc.setup(repo)
+
+			if _, err := uc.RefreshToken(context.Background(), raw); err != customerrors.ErrInvalidRefreshToken {
+				t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidRefreshToken, err)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Logout Tests
+// =============================================================================
+
+func loginClaims(t *testing.T, env *authTestEnv) (*service.Claims, string) {
+	t.Helper()
+
+	resp, err := env.useCase.Login(context.Background(), request.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	claims, err := env.jwtService.ValidateToken(resp.Token)
+	if err != nil {
+		t.Fatalf("Expected valid access token, got %v", err)
+	}
+	if claims.ID == "" {
+		t.Fatal("Expected access token to carry a jti")
+	}
+	return claims, resp.RefreshToken
+}
+
+func TestLogout(t *testing.T) {
+	env := newAuthTestEnv(t)
+	claims, refreshToken := loginClaims(t, env)
+
+	if err := env.useCase.Logout(context.Background(), claims, refreshToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	revoked, err := env.tokenRevocation.IsRevoked(context.Background(), claims)
+	if err != nil || !revoked {
+		t.Errorf("Expected access token to be revoked, got revoked=%v err=%v", revoked, err)
+	}
+	if _, err := env.useCase.RefreshToken(context.Background(), refreshToken); err == nil {
+		t.Error("Expected refresh token to be revoked")
+	}
+
+	// 其他裝置的 token 不受影響
+	other, _ := loginClaims(t, env)
+	if revoked, _ := env.tokenRevocation.IsRevoked(context.Background(), other); revoked {
+		t.Error("Expected other tokens to remain valid")
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	env := newAuthTestEnv(t)
+	first, firstRefresh := loginClaims(t, env)
+	second, _ := loginClaims(t, env)
+
+	if err := env.useCase.LogoutAll(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, claims := range []*service.Claims{first, second} {
+		if revoked, _ := env.tokenRevocation.IsRevoked(context.Background(), claims); !revoked {
+			t.Error("Expected every existing access token to be revoked")
+		}
+	}
+	if _, err := env.useCase.RefreshToken(context.Background(), firstRefresh); err == nil {
+		t.Error("Expected refresh tokens to be revoked")
+	}
+
+	// 之後重新登入的 token 應可使用
+	fresh, _ := loginClaims(t, env)
+	if revoked, _ := env.tokenRevocation.IsRevoked(context.Background(), fresh); revoked {
+		t.Error("Expected new token to be valid")
+	}
+}
+
+// =============================================================================
+// Account Lockout Tests
+// =============================================================================
+
+func newLockoutTestEnv(t *testing.T) *authTestEnv {
+	env := newAuthTestEnv(t)
+	env.useCase.cfg.LockoutThreshold = 3
+	env.useCase.cfg.LockoutBaseDuration = time.Minute
+	env.useCase.cfg.LockoutMaxDuration = 4 * time.Minute
+	return env
+}
+
+func TestLogin_LocksAccountAfterThreshold(t *testing.T) {
+	env := newLockoutTestEnv(t)
+	ctx := context.Background()
+	wrong := request.LoginRequest{Email: "test@example.com", Password: "wrong"}
+	correct := request.LoginRequest{Email: "test@example.com", Password: "password123"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := env.useCase.Login(ctx, wrong); err != customerrors.ErrInvalidCredentials {
+			t.Fatalf("Attempt %d: expected error %v, got %v", i+1, customerrors.ErrInvalidCredentials, err)
+		}
+	}
+
+	// 第三次失敗達到門檻
+	if _, err := env.useCase.Login(ctx, wrong); err != customerrors.ErrAccountLocked {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrAccountLocked, err)
+	}
+
+	lockedUntil := env.userRepo.User.LockedUntil
+	if lockedUntil == nil {
+		t.Fatal("Expected account to be locked")
+	}
+	if remaining := time.Until(*lockedUntil); remaining <= 0 || remaining > time.Minute {
+		t.Errorf("Expected lock of about 1 minute, got %v", remaining)
+	}
+
+	// 鎖定期間即使密碼正確也無法登入
+	if _, err := env.useCase.Login(ctx, correct); err != customerrors.ErrAccountLocked {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrAccountLocked, err)
+	}
+}
+
+func TestLogin_LockoutBacksOffExponentially(t *testing.T) {
+	env := newLockoutTestEnv(t)
+	ctx := context.Background()
+	wrong := request.LoginRequest{Email: "test@example.com", Password: "wrong"}
+
+	// 已達門檻且鎖定已過期，再失敗一次時鎖定時間加倍
+	expired := time.Now().Add(-time.Second)
+	env.userRepo.User.FailedLoginAttempts = 3
+	env.userRepo.User.LockedUntil = &expired
+
+	if _, err := env.useCase.Login(ctx, wrong); err != customerrors.ErrAccountLocked {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrAccountLocked, err)
+	}
+	if remaining := time.Until(*env.userRepo.User.LockedUntil); remaining <= time.Minute || remaining > 2*time.Minute {
+		t.Errorf("Expected lock of about 2 minutes, got %v", remaining)
+	}
+}
+
+func TestLockoutDuration(t *testing.T) {
+	env := newLockoutTestEnv(t)
+
+	testCases := []struct {
+		attempts int32
+		expected time.Duration
+	}{
+		{attempts: 3, expected: time.Minute},
+		{attempts: 4, expected: 2 * time.Minute},
+		{attempts: 5, expected: 4 * time.Minute},
+		{attempts: 6, expected: 4 * time.Minute},
+		{attempts: 100, expected: 4 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		if got := env.useCase.lockoutDuration(tc.attempts); got != tc.expected {
+			t.Errorf("lockoutDuration(%d) = %v, expected %v", tc.attempts, got, tc.expected)
+		}
+	}
+}
+
+func TestLogin_SuccessResetsFailures(t *testing.T) {
+	env := newLockoutTestEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.useCase.Login(ctx, request.LoginRequest{Email: "test@example.com", Password: "wrong"}); err != customerrors.ErrInvalidCredentials {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrInvalidCredentials, err)
+	}
+	if env.userRepo.User.FailedLoginAttempts != 1 {
+		t.Fatalf("Expected 1 failed attempt, got %d", env.userRepo.User.FailedLoginAttempts)
+	}
+
+	login(t, env.useCase)
+
+	if env.userRepo.User.FailedLoginAttempts != 0 {
+		t.Errorf("Expected failed attempts to be reset, got %d", env.userRepo.User.FailedLoginAttempts)
+	}
+}
+
+func TestLogin_LockoutDisabled(t *testing.T) {
+	env := newAuthTestEnv(t)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		if _, err := env.useCase.Login(ctx, request.LoginRequest{Email: "test@example.com", Password: "wrong"}); err != customerrors.ErrInvalidCredentials {
+			t.Fatalf("Attempt %d: expected error %v, got %v", i+1, customerrors.ErrInvalidCredentials, err)
+		}
+	}
+	if env.userRepo.User.LockedUntil != nil {
+		t.Error("Expected account not to be locked when lockout is disabled")
+	}
+}
@@ -0,0 +1,28 @@
+package usecase
+
+import "context"
+
+// maxUserAgentLength 與 user_sessions.user_agent 欄位長度一致
+const maxUserAgentLength = 512
+
+// ClientInfo 發出請求的裝置資訊，登入時記錄在 session 中
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo 將裝置資訊存入 context（由 handler 呼叫）
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	if len(info.UserAgent) > maxUserAgentLength {
+		info.UserAgent = info.UserAgent[:maxUserAgentLength]
+	}
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// clientInfoFromContext 取得裝置資訊，未設定時回傳空值
+func clientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
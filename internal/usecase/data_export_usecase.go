@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/response"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+)
+
+// DataExportUseCase 讓用戶匯出我們為其保存的所有資料
+type DataExportUseCase struct {
+	userRepo contract.UserRepository
+	exports  *service.DataExportService
+}
+
+func NewDataExportUseCase(userRepo contract.UserRepository, exports *service.DataExportService) *DataExportUseCase {
+	return &DataExportUseCase{
+		userRepo: userRepo,
+		exports:  exports,
+	}
+}
+
+// Export 收集所有已註冊 exporter 的資料
+func (u *DataExportUseCase) Export(ctx context.Context, userID int32) (*response.DataExportResponse, error) {
+	if _, err := u.userRepo.GetByID(ctx, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	data, err := u.exports.Export(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.DataExportResponse{
+		UserID:     userID,
+		ExportedAt: time.Now().UTC(),
+		Data:       data,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+)
+
+func newTestDataExportUseCase(env *authTestEnv, apiKeyRepo *mock.MockAPIKeyRepository, auditRepo *mock.MockAuditEventRepository) *DataExportUseCase {
+	exports := service.NewDataExportService()
+	exports.Register("profile", env.userRepo)
+	exports.Register("sessions", env.sessionRepo)
+	exports.Register("api_keys", apiKeyRepo)
+	exports.Register("mfa", env.mfaRepo)
+	exports.Register("audit_events", auditRepo)
+	return NewDataExportUseCase(env.userRepo, exports)
+}
+
+func TestExportData_ExcludesSecrets(t *testing.T) {
+	env := newAuthTestEnv(t)
+	login(t, env.useCase)
+
+	apiKeyRepo := mock.NewMockAPIKeyRepository()
+	_ = apiKeyRepo.Create(context.Background(), &entity.APIKey{UserID: 1, Name: "ci", Prefix: "gf_abcd", KeyHash: "key-hash-secret"})
+	_ = env.mfaRepo.Save(context.Background(), &entity.UserMFA{UserID: 1, Secret: "TOTPSECRETVALUE"})
+	actorID := int32(2)
+	auditRepo := mock.NewMockAuditEventRepository()
+	_ = auditRepo.Create(context.Background(), &entity.AuditEvent{UserID: 1, ActorID: &actorID, Action: entity.AuditActionImpersonatedRequest, Path: "/api/v1/users/profile"})
+
+	export, err := newTestDataExportUseCase(env, apiKeyRepo, auditRepo).Export(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if export.UserID != 1 {
+		t.Errorf("Expected user id 1, got %d", export.UserID)
+	}
+
+	body, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("Expected export to be JSON serializable, got %v", err)
+	}
+	for _, secret := range []string{env.userRepo.User.PasswordHash, "password_hash", "key-hash-secret", "TOTPSECRETVALUE"} {
+		if strings.Contains(string(body), secret) {
+			t.Errorf("Expected export not to contain %q", secret)
+		}
+	}
+	for _, expected := range []string{`"username":"testuser"`, `"prefix":"gf_abcd"`, `"sessions":[{`, `"action":"impersonated_request"`} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Expected export to contain %s, got %s", expected, body)
+		}
+	}
+}
+
+func TestExportData_DeletedUser(t *testing.T) {
+	env := newAuthTestEnv(t)
+	_ = env.userRepo.Delete(context.Background(), 1)
+
+	if _, err := newTestDataExportUseCase(env, mock.NewMockAPIKeyRepository(), mock.NewMockAuditEventRepository()).Export(context.Background(), 1); err != customerrors.ErrUserNotFound {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrUserNotFound, err)
+	}
+}
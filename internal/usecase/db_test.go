@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// testTxLog 記錄事務的 begin / commit / rollback
+// mock repository 不會使用事務，這裡只用來確認 usecase 在事務中執行以及失敗時 rollback
+type testTxLog struct {
+	mu  sync.Mutex
+	ops []string
+}
+
+func (l *testTxLog) record(op string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ops = append(l.ops, op)
+}
+
+// Ops 回傳目前的紀錄
+func (l *testTxLog) Ops() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.ops)
+}
+
+// newTestDB 建立只支援事務的 *sql.DB，供 database.WithTransaction 使用
+func newTestDB(t *testing.T) (*sql.DB, *testTxLog) {
+	t.Helper()
+
+	log := &testTxLog{}
+	db := sql.OpenDB(&testConnector{log: log})
+	t.Cleanup(func() { db.Close() })
+	return db, log
+}
+
+// testDB 建立測試用的 *sql.DB，不需檢查事務紀錄時使用
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, _ := newTestDB(t)
+	return db
+}
+
+type testConnector struct{ log *testTxLog }
+
+func (c *testConnector) Connect(context.Context) (driver.Conn, error) { return &testConn{c.log}, nil }
+func (c *testConnector) Driver() driver.Driver                        { return nil }
+
+type testConn struct{ log *testTxLog }
+
+func (c *testConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *testConn) Close() error                        { return nil }
+func (c *testConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *testConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.log.record("begin")
+	return &testTx{c.log}, nil
+}
+
+type testTx struct{ log *testTxLog }
+
+func (t *testTx) Commit() error   { t.log.record("commit"); return nil }
+func (t *testTx) Rollback() error { t.log.record("rollback"); return nil }
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/mailer"
+)
+
+// EmailChangeUseCase 變更 Email
+// 新 Email 先存為待確認，由寄到新 Email 的連結確認後才取代原 Email；
+// 原 Email 同時收到通知與取消連結，避免 session 被盜用時帳號遭到接管
+type EmailChangeUseCase struct {
+	auth            *AuthUseCase
+	userRepo        contract.UserRepository
+	actionTokens    *service.ActionTokenService
+	tokenRevocation *service.TokenRevocationService
+	mailer          mailer.Mailer
+	tokenTTL        time.Duration
+	frontendURL     string
+}
+
+func NewEmailChangeUseCase(
+	auth *AuthUseCase,
+	userRepo contract.UserRepository,
+	actionTokens *service.ActionTokenService,
+	tokenRevocation *service.TokenRevocationService,
+	mailer mailer.Mailer,
+	tokenTTL time.Duration,
+	frontendURL string,
+) *EmailChangeUseCase {
+	return &EmailChangeUseCase{
+		auth:            auth,
+		userRepo:        userRepo,
+		actionTokens:    actionTokens,
+		tokenRevocation: tokenRevocation,
+		mailer:          mailer,
+		tokenTTL:        tokenTTL,
+		frontendURL:     frontendURL,
+	}
+}
+
+// RequestChange 將 newEmail 設為待確認，寄送確認連結到新 Email 並通知原 Email
+// 再次申請會取代先前待確認的 Email，先前的確認連結隨之失效
+func (e *EmailChangeUseCase) RequestChange(ctx context.Context, userID int32, newEmail string) error {
+	user, err := e.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrUserNotFound
+		}
+		return err
+	}
+
+	if newEmail == user.Email {
+		return customerrors.ErrInvalidInput
+	}
+
+	existingUser, err := e.userRepo.GetByEmail(ctx, newEmail)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if existingUser != nil {
+		return customerrors.ErrUserAlreadyExists
+	}
+
+	if err := e.userRepo.SetPendingEmail(ctx, userID, newEmail); err != nil {
+		return err
+	}
+
+	// 確認 token 綁定新 Email，取消 token 綁定原 Email
+	confirmToken, err := e.actionTokens.GenerateToken(service.PurposeEmailChange, user.ID, newEmail, e.tokenTTL)
+	if err != nil {
+		return err
+	}
+	cancelToken, err := e.actionTokens.GenerateToken(service.PurposeEmailChangeCancel, user.ID, user.Email, e.tokenTTL)
+	if err != nil {
+		return err
+	}
+
+	confirmLink := fmt.Sprintf("%s/confirm-email-change?token=%s", e.frontendURL, url.QueryEscape(confirmToken))
+	if err := e.mailer.Send(ctx, mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm that you want to use this address for your account by opening the link below:\n\n%s\n\nThis link expires in %s. Your email address will not change until you confirm.\n",
+			user.Username, confirmLink, e.tokenTTL),
+	}); err != nil {
+		return err
+	}
+
+	cancelLink := fmt.Sprintf("%s/cancel-email-change?token=%s", e.frontendURL, url.QueryEscape(cancelToken))
+	return e.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf("Hi %s,\n\nA request was made to change the email address of your account to %s.\n\nIf you did not request this, open the link below to cancel the change (or revert it if it has already been confirmed) and sign out all devices:\n\n%s\n\nThis link expires in %s. We also recommend changing your password.\n",
+			user.Username, newEmail, cancelLink, e.tokenTTL),
+	})
+}
+
+// Confirm 使用寄到新 Email 的 token 完成變更，新 Email 同時視為已驗證
+// token 只在待確認的 Email 仍是 token 中的 Email 時有效，完成後即無法再次使用
+func (e *EmailChangeUseCase) Confirm(ctx context.Context, token string) error {
+	userID, claims, err := e.actionTokens.ValidateToken(token, service.PurposeEmailChange)
+	if err != nil {
+		return customerrors.ErrInvalidEmailChangeToken
+	}
+
+	// 申請後新 Email 可能已被其他帳號註冊
+	existingUser, err := e.userRepo.GetByEmail(ctx, claims.Email)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if existingUser != nil && existingUser.ID != userID {
+		return customerrors.ErrUserAlreadyExists
+	}
+
+	confirmed, err := e.userRepo.ConfirmEmailChange(ctx, userID, claims.Email)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return customerrors.ErrInvalidEmailChangeToken
+	}
+	return nil
+}
+
+// Cancel 使用寄到原 Email 的 token 取消變更；已確認的變更會改回原 Email
+// 取消代表變更可能不是用戶本人申請的，因此同時登出所有裝置。token 只能使用一次
+func (e *EmailChangeUseCase) Cancel(ctx context.Context, token string) error {
+	userID, claims, err := e.actionTokens.ValidateToken(token, service.PurposeEmailChangeCancel)
+	if err != nil {
+		return customerrors.ErrInvalidEmailChangeToken
+	}
+
+	user, err := e.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrInvalidEmailChangeToken
+		}
+		return err
+	}
+
+	// 變更完成後原 Email 可能已被其他帳號註冊
+	if user.Email != claims.Email {
+		existingUser, err := e.userRepo.GetByEmail(ctx, claims.Email)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if existingUser != nil {
+			return customerrors.ErrUserAlreadyExists
+		}
+	}
+
+	consumed, err := e.tokenRevocation.ConsumeOnce(ctx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return customerrors.ErrInvalidEmailChangeToken
+	}
+
+	if err := e.userRepo.CancelEmailChange(ctx, userID, claims.Email); err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrInvalidEmailChangeToken
+		}
+		return err
+	}
+
+	return e.auth.LogoutAll(ctx, userID)
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/mailer"
+)
+
+type emailChangeTestEnv struct {
+	auth    *authTestEnv
+	useCase *EmailChangeUseCase
+	mailer  *fakeMailer
+}
+
+func newEmailChangeTestEnv(t *testing.T) *emailChangeTestEnv {
+	t.Helper()
+
+	auth := newAuthTestEnv(t)
+	// 只有目前的 Email 查得到帳號
+	auth.userRepo.GetByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+		if user := auth.userRepo.User; user != nil && user.Email == email {
+			return user, nil
+		}
+		return nil, sql.ErrNoRows
+	}
+	mail := &fakeMailer{}
+
+	return &emailChangeTestEnv{
+		auth:    auth,
+		useCase: NewEmailChangeUseCase(auth.useCase, auth.userRepo, auth.useCase.actionTokens, auth.tokenRevocation, mail, time.Hour, "http://localhost:3000"),
+		mailer:  mail,
+	}
+}
+
+// requestChange 申請變更 Email，回傳寄到新 Email 的確認 token 與寄到原 Email 的取消 token
+func (env *emailChangeTestEnv) requestChange(t *testing.T, newEmail string) (string, string) {
+	t.Helper()
+
+	oldEmail := env.auth.userRepo.User.Email
+	sent := len(env.mailer.sent)
+	if err := env.useCase.RequestChange(context.Background(), 1, newEmail); err != nil {
+		t.Fatalf("RequestChange failed: %v", err)
+	}
+	if len(env.mailer.sent) != sent+2 {
+		t.Fatalf("Expected 2 emails to be sent, got %d", len(env.mailer.sent)-sent)
+	}
+
+	confirm, cancel := env.mailer.sent[sent], env.mailer.sent[sent+1]
+	if confirm.To != newEmail || cancel.To != oldEmail {
+		t.Fatalf("Expected confirmation to %s and notice to %s, got %s and %s", newEmail, oldEmail, confirm.To, cancel.To)
+	}
+	return mailToken(t, confirm), mailToken(t, cancel)
+}
+
+// mailToken 從郵件的連結中取出 token
+func mailToken(t *testing.T, msg mailer.Message) string {
+	t.Helper()
+
+	match := tokenPattern.FindStringSubmatch(msg.Body)
+	if match == nil {
+		t.Fatal("Expected email to contain a token link")
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("Failed to unescape token: %v", err)
+	}
+	return token
+}
+
+func TestEmailChange_Confirm(t *testing.T) {
+	env := newEmailChangeTestEnv(t)
+	ctx := context.Background()
+	user := env.auth.userRepo.User
+
+	confirmToken, _ := env.requestChange(t, "new@example.com")
+
+	// 確認前 Email 不變
+	if user.Email != "test@example.com" || user.PendingEmail != "new@example.com" {
+		t.Fatalf("Expected email to stay pending, got email=%s pending=%s", user.Email, user.PendingEmail)
+	}
+
+	if err := env.useCase.Confirm(ctx, confirmToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Email != "new@example.com" || user.PendingEmail != "" {
+		t.Errorf("Expected email to be changed, got email=%s pending=%s", user.Email, user.PendingEmail)
+	}
+	if !user.IsEmailVerified() {
+		t.Error("Expected new email to be marked as verified")
+	}
+
+	// token 只能使用一次
+	if err := env.useCase.Confirm(ctx, confirmToken); err != customerrors.ErrInvalidEmailChangeToken {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidEmailChangeToken, err)
+	}
+}
+
+func TestEmailChange_Cancel(t *testing.T) {
+	env := newEmailChangeTestEnv(t)
+	ctx := context.Background()
+	user := env.auth.userRepo.User
+
+	refreshToken := login(t, env.auth.useCase)
+	confirmToken, cancelToken := env.requestChange(t, "attacker@example.com")
+
+	if err := env.useCase.Cancel(ctx, cancelToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Email != "test@example.com" || user.PendingEmail != "" {
+		t.Errorf("Expected change to be cancelled, got email=%s pending=%s", user.Email, user.PendingEmail)
+	}
+
+	// 所有裝置登出，確認連結失效，取消連結只能使用一次
+	if _, err := env.auth.useCase.RefreshToken(ctx, refreshToken); err == nil {
+		t.Error("Expected refresh token to be revoked after cancelling")
+	}
+	if err := env.useCase.Confirm(ctx, confirmToken); err != customerrors.ErrInvalidEmailChangeToken {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidEmailChangeToken, err)
+	}
+	if err := env.useCase.Cancel(ctx, cancelToken); err != customerrors.ErrInvalidEmailChangeToken {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidEmailChangeToken, err)
+	}
+}
+
+func TestEmailChange_CancelAfterConfirmReverts(t *testing.T) {
+	env := newEmailChangeTestEnv(t)
+	ctx := context.Background()
+	user := env.auth.userRepo.User
+
+	confirmToken, cancelToken := env.requestChange(t, "attacker@example.com")
+	if err := env.useCase.Confirm(ctx, confirmToken); err != nil {
+		t.Fatalf("Confirm failed: %v", err)
+	}
+
+	if err := env.useCase.Cancel(ctx, cancelToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Email != "test@example.com" {
+		t.Errorf("Expected email to be reverted, got %s", user.Email)
+	}
+}
+
+func TestEmailChange_SupersededRequest(t *testing.T) {
+	env := newEmailChangeTestEnv(t)
+	ctx := context.Background()
+
+	first, _ := env.requestChange(t, "first@example.com")
+	second, _ := env.requestChange(t, "second@example.com")
+
+	if err := env.useCase.Confirm(ctx, first); err != customerrors.ErrInvalidEmailChangeToken {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidEmailChangeToken, err)
+	}
+	if err := env.useCase.Confirm(ctx, second); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if env.auth.userRepo.User.Email != "second@example.com" {
+		t.Errorf("Expected email second@example.com, got %s", env.auth.userRepo.User.Email)
+	}
+}
+
+func TestEmailChange_RequestInvalid(t *testing.T) {
+	testCases := []struct {
+		name        string
+		email       string
+		expectError error
+	}{
+		{name: "SameEmail", email: "test@example.com", expectError: customerrors.ErrInvalidInput},
+		{name: "EmailInUse", email: "taken@example.com", expectError: customerrors.ErrUserAlreadyExists},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newEmailChangeTestEnv(t)
+			lookup := env.auth.userRepo.GetByEmailFunc
+			env.auth.userRepo.GetByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+				if email == "taken@example.com" {
+					return &entity.User{ID: 2, Email: email}, nil
+				}
+				return lookup(ctx, email)
+			}
+
+			if err := env.useCase.RequestChange(context.Background(), 1, tc.email); err != tc.expectError {
+				t.Errorf("Expected error %v, got %v", tc.expectError, err)
+			}
+			if len(env.mailer.sent) != 0 || env.auth.userRepo.User.PendingEmail != "" {
+				t.Error("Expected no pending change and no email")
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/mailer"
+)
+
+type EmailVerificationUseCase struct {
+	userRepo     contract.UserRepository
+	actionTokens *service.ActionTokenService
+	mailer       mailer.Mailer
+	tokenTTL     time.Duration
+	frontendURL  string
+}
+
+func NewEmailVerificationUseCase(
+	userRepo contract.UserRepository,
+	actionTokens *service.ActionTokenService,
+	mailer mailer.Mailer,
+	tokenTTL time.Duration,
+	frontendURL string,
+) *EmailVerificationUseCase {
+	return &EmailVerificationUseCase{
+		userRepo:     userRepo,
+		actionTokens: actionTokens,
+		mailer:       mailer,
+		tokenTTL:     tokenTTL,
+		frontendURL:  frontendURL,
+	}
+}
+
+// SendVerification 寄送 Email 驗證信給指定用戶
+func (e *EmailVerificationUseCase) SendVerification(ctx context.Context, userID int32) error {
+	user, err := e.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrUserNotFound
+		}
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return customerrors.ErrEmailAlreadyVerified
+	}
+
+	return e.send(ctx, user)
+}
+
+// ResendVerification 依 Email 重新寄送驗證信
+// Email 不存在或已驗證時不回傳錯誤，避免被用來探測帳號
+// 寄信失敗只會發生在未驗證的帳號上，呼叫端應記錄錯誤但回傳相同的結果
+func (e *EmailVerificationUseCase) ResendVerification(ctx context.Context, email string) error {
+	user, err := e.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	return e.send(ctx, user)
+}
+
+// VerifyEmail 使用驗證 token 完成 Email 驗證
+// token 綁定簽發當下的 Email，且驗證成功後即無法再次使用
+func (e *EmailVerificationUseCase) VerifyEmail(ctx context.Context, token string) error {
+	userID, claims, err := e.actionTokens.ValidateToken(token, service.PurposeEmailVerification)
+	if err != nil {
+		return customerrors.ErrInvalidVerificationToken
+	}
+
+	verified, err := e.userRepo.MarkEmailVerified(ctx, userID, claims.Email)
+	if err != nil {
+		return err
+	}
+	if verified {
+		return nil
+	}
+
+	// 未更新任何資料：用戶不存在、Email 已變更，或已驗證過
+	user, err := e.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrInvalidVerificationToken
+		}
+		return err
+	}
+	if user.Email == claims.Email && user.IsEmailVerified() {
+		return customerrors.ErrEmailAlreadyVerified
+	}
+	return customerrors.ErrInvalidVerificationToken
+}
+
+func (e *EmailVerificationUseCase) send(ctx context.Context, user *entity.User) error {
+	token, err := e.actionTokens.GenerateToken(service.PurposeEmailVerification, user.ID, user.Email, e.tokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", e.frontendURL, url.QueryEscape(token))
+
+	return e.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease verify your email address by opening the link below:\n\n%s\n\nThis link expires in %s.\n",
+			user.Username, link, e.tokenTTL),
+	})
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/mailer"
+)
+
+// fakeMailer 記錄寄出的郵件
+type fakeMailer struct {
+	sent []mailer.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var tokenPattern = regexp.MustCompile(`token=([^\s]+)`)
+
+// lastToken 從最後一封郵件的連結中取出 token
+func (m *fakeMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatal("Expected an email to be sent")
+	}
+	match := tokenPattern.FindStringSubmatch(m.sent[len(m.sent)-1].Body)
+	if match == nil {
+		t.Fatal("Expected email to contain a token link")
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("Failed to unescape token: %v", err)
+	}
+	return token
+}
+
+func newTestEmailVerificationUseCase(user *entity.User) (*EmailVerificationUseCase, *fakeMailer) {
+	mail := &fakeMailer{}
+	userRepo := &mock.SimpleMockUserRepository{User: user}
+	uc := NewEmailVerificationUseCase(userRepo, service.NewActionTokenService("action-secret"), mail, time.Hour, "http://localhost:3000")
+	return uc, mail
+}
+
+// =============================================================================
+// VerifyEmail Tests
+// =============================================================================
+
+func TestVerifyEmail_SingleUse(t *testing.T) {
+	user := &entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}
+	uc, mail := newTestEmailVerificationUseCase(user)
+
+	if err := uc.SendVerification(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mail.sent[0].To != "test@example.com" {
+		t.Errorf("Expected mail to test@example.com, got %s", mail.sent[0].To)
+	}
+	token := mail.lastToken(t)
+
+	if err := uc.VerifyEmail(context.Background(), token); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !user.IsEmailVerified() {
+		t.Error("Expected email to be verified")
+	}
+
+	// 第二次使用同一個 token
+	if err := uc.VerifyEmail(context.Background(), token); err != customerrors.ErrEmailAlreadyVerified {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrEmailAlreadyVerified, err)
+	}
+}
+
+func TestVerifyEmail_Invalid(t *testing.T) {
+	actionTokens := service.NewActionTokenService("action-secret")
+
+	testCases := []struct {
+		name  string
+		token func(t *testing.T) string
+	}{
+		{
+			name: "Malformed",
+			token: func(t *testing.T) string {
+				return "not-a-token"
+			},
+		},
+		{
+			name: "WrongSecret",
+			token: func(t *testing.T) string {
+				token, _ := service.NewActionTokenService("other-secret").GenerateToken(service.PurposeEmailVerification, 1, "test@example.com", time.Hour)
+				return token
+			},
+		},
+		{
+			name: "WrongPurpose",
+			token: func(t *testing.T) string {
+				token, _ := actionTokens.GenerateToken("other_purpose", 1, "test@example.com", time.Hour)
+				return token
+			},
+		},
+		{
+			name: "Expired",
+			token: func(t *testing.T) string {
+				token, _ := actionTokens.GenerateToken(service.PurposeEmailVerification, 1, "test@example.com", -time.Minute)
+				return token
+			},
+		},
+		{
+			name: "EmailChanged",
+			token: func(t *testing.T) string {
+				token, _ := actionTokens.GenerateToken(service.PurposeEmailVerification, 1, "old@example.com", time.Hour)
+				return token
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}
+			uc, _ := newTestEmailVerificationUseCase(user)
+
+			if err := uc.VerifyEmail(context.Background(), tc.token(t)); err != customerrors.ErrInvalidVerificationToken {
+				t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidVerificationToken, err)
+			}
+			if user.IsEmailVerified() {
+				t.Error("Expected email to remain unverified")
+			}
+		})
+	}
+}
+
+func TestResendVerification_DoesNotRevealAccounts(t *testing.T) {
+	verifiedAt := time.Now()
+	testCases := []struct {
+		name       string
+		user       *entity.User
+		expectSent int
+	}{
+		{
+			name:       "Unverified",
+			user:       &entity.User{ID: 1, Email: "test@example.com"},
+			expectSent: 1,
+		},
+		{
+			name:       "AlreadyVerified",
+			user:       &entity.User{ID: 1, Email: "test@example.com", EmailVerifiedAt: &verifiedAt},
+			expectSent: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc, mail := newTestEmailVerificationUseCase(tc.user)
+
+			if err := uc.ResendVerification(context.Background(), "test@example.com"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(mail.sent) != tc.expectSent {
+				t.Errorf("Expected %d emails, got %d", tc.expectSent, len(mail.sent))
+			}
+		})
+	}
+
+	t.Run("UnknownEmail", func(t *testing.T) {
+		uc, mail := newTestEmailVerificationUseCase(nil)
+		uc.userRepo = &mock.SimpleMockUserRepository{Error: nil}
+		uc.userRepo.(*mock.SimpleMockUserRepository).GetByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+			return nil, sql.ErrNoRows
+		}
+
+		if err := uc.ResendVerification(context.Background(), "nobody@example.com"); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if len(mail.sent) != 0 {
+			t.Error("Expected no email to be sent")
+		}
+	})
+}
+
+// =============================================================================
+// Login with RequireEmailVerification
+// =============================================================================
+
+func TestLogin_RequireEmailVerification(t *testing.T) {
+	env := newAuthTestEnv(t)
+	env.useCase.cfg.RequireEmailVerification = true
+
+	req := request.LoginRequest{Email: "test@example.com", Password: "password123"}
+
+	if _, err := env.useCase.Login(context.Background(), req); err != customerrors.ErrEmailNotVerified {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrEmailNotVerified, err)
+	}
+
+	// 密碼錯誤時仍回傳 ErrInvalidCredentials，不洩漏驗證狀態
+	if _, err := env.useCase.Login(context.Background(), request.LoginRequest{Email: "test@example.com", Password: "wrong"}); err != customerrors.ErrInvalidCredentials {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidCredentials, err)
+	}
+
+	now := time.Now()
+	env.userRepo.User.EmailVerifiedAt = &now
+	if _, err := env.useCase.Login(context.Background(), req); err != nil {
+		t.Errorf("Expected verified user to log in, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+)
+
+// newImpersonationTestEnv 用戶 1 為一般用戶，用戶 2 為管理員
+func newImpersonationTestEnv(t *testing.T) *authTestEnv {
+	t.Helper()
+
+	env := newAuthTestEnv(t)
+	env.useCase.cfg.ImpersonationTTL = 15 * time.Minute
+	users := map[int32]*entity.User{
+		1: env.userRepo.User,
+		2: {ID: 2, Username: "admin", Email: "admin@example.com"},
+	}
+	env.userRepo.GetByIDFunc = func(ctx context.Context, id int32) (*entity.User, error) {
+		if user, ok := users[id]; ok {
+			return user, nil
+		}
+		return nil, sql.ErrNoRows
+	}
+	roleRepo := env.useCase.roleRepo.(*mock.MockRoleRepository)
+	roleRepo.UserRoles[1] = []string{entity.RoleUser}
+	roleRepo.UserRoles[2] = []string{entity.RoleAdmin}
+	return env
+}
+
+func TestImpersonate_Success(t *testing.T) {
+	env := newImpersonationTestEnv(t)
+
+	resp, err := env.useCase.Impersonate(context.Background(), 2, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.ImpersonatorID != 2 || resp.User.ID != 1 || resp.ExpiresIn != int64((15*time.Minute).Seconds()) {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+
+	claims, err := env.jwtService.ValidateToken(resp.Token)
+	if err != nil {
+		t.Fatalf("Expected valid token, got %v", err)
+	}
+	if claims.UserID != 1 || claims.Actor == nil || claims.Actor.UserID != 2 {
+		t.Errorf("Expected token for user 1 acting as admin 2, got %+v", claims)
+	}
+
+	// 不建立 session 與 refresh token
+	if len(env.sessionRepo.Sessions) != 0 || len(env.refreshRepo.Tokens) != 0 {
+		t.Error("Expected no session or refresh token to be created")
+	}
+}
+
+func TestImpersonate_Rejected(t *testing.T) {
+	testCases := []struct {
+		name    string
+		actorID int32
+		userID  int32
+		want    error
+	}{
+		{name: "Self", actorID: 2, userID: 2, want: customerrors.ErrCannotImpersonate},
+		{name: "PrivilegedTarget", actorID: 1, userID: 2, want: customerrors.ErrCannotImpersonate},
+		{name: "UnknownUser", actorID: 2, userID: 99, want: customerrors.ErrUserNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newImpersonationTestEnv(t)
+
+			if _, err := env.useCase.Impersonate(context.Background(), tc.actorID, tc.userID); err != tc.want {
+				t.Errorf("Expected error %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
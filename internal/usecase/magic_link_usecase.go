@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/response"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/mailer"
+	"github.com/dinosaur1258/GolangFramework/pkg/ratelimit"
+)
+
+// MagicLinkUseCase 免密碼登入：寄送一次性的登入連結到用戶的 Email
+type MagicLinkUseCase struct {
+	auth            *AuthUseCase
+	userRepo        contract.UserRepository
+	actionTokens    *service.ActionTokenService
+	tokenRevocation *service.TokenRevocationService
+	mailer          mailer.Mailer
+	emailLimiter    ratelimit.Limiter // 以 Email 為 key 限制寄送次數
+	tokenTTL        time.Duration
+	frontendURL     string
+}
+
+func NewMagicLinkUseCase(
+	auth *AuthUseCase,
+	userRepo contract.UserRepository,
+	actionTokens *service.ActionTokenService,
+	tokenRevocation *service.TokenRevocationService,
+	mailer mailer.Mailer,
+	emailLimiter ratelimit.Limiter,
+	tokenTTL time.Duration,
+	frontendURL string,
+) *MagicLinkUseCase {
+	return &MagicLinkUseCase{
+		auth:            auth,
+		userRepo:        userRepo,
+		actionTokens:    actionTokens,
+		tokenRevocation: tokenRevocation,
+		mailer:          mailer,
+		emailLimiter:    emailLimiter,
+		tokenTTL:        tokenTTL,
+		frontendURL:     frontendURL,
+	}
+}
+
+// RequestLink 寄送登入連結
+// 無論 Email 是否存在都不回傳錯誤，避免被用來探測帳號；次數限制對不存在的 Email 同樣生效
+func (m *MagicLinkUseCase) RequestLink(ctx context.Context, email string) error {
+	limit, err := m.emailLimiter.Allow(ctx, "magic-link:"+strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return err
+	}
+	if !limit.Allowed {
+		return customerrors.ErrTooManyRequests
+	}
+
+	user, err := m.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	token, err := m.actionTokens.GenerateToken(service.PurposeMagicLink, user.ID, user.Email, m.tokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/magic-link?token=%s", m.frontendURL, url.QueryEscape(token))
+
+	return m.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen the link below to sign in:\n\n%s\n\nThis link expires in %s and can only be used once. If you did not request it, you can ignore this email.\n",
+			user.Username, link, m.tokenTTL),
+	})
+}
+
+// Consume 使用登入連結中的 token 登入，回傳與密碼登入相同的 LoginResponse
+// token 綁定簽發當下的 Email 且只能使用一次；已啟用兩步驟驗證時仍需輸入驗證碼
+func (m *MagicLinkUseCase) Consume(ctx context.Context, token string) (*response.LoginResponse, error) {
+	userID, claims, err := m.actionTokens.ValidateToken(token, service.PurposeMagicLink)
+	if err != nil {
+		return nil, customerrors.ErrInvalidMagicLink
+	}
+
+	user, err := m.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrInvalidMagicLink
+		}
+		return nil, err
+	}
+	if user.Email != claims.Email {
+		return nil, customerrors.ErrInvalidMagicLink
+	}
+
+	// 鎖定期間不使用 token，解除鎖定後連結在效期內仍可使用
+	if user.IsLocked(time.Now()) {
+		return nil, customerrors.ErrAccountLocked
+	}
+
+	consumed, err := m.tokenRevocation.ConsumeOnce(ctx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, customerrors.ErrInvalidMagicLink
+	}
+
+	// 能開啟寄到該 Email 的連結即證明擁有此 Email
+	if !user.IsEmailVerified() {
+		if _, err := m.userRepo.MarkEmailVerified(ctx, user.ID, user.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.auth.startSession(ctx, user)
+}
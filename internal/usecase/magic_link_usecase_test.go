@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/ratelimit"
+)
+
+type magicLinkTestEnv struct {
+	auth    *authTestEnv
+	useCase *MagicLinkUseCase
+	mailer  *fakeMailer
+}
+
+// newMagicLinkTestEnv 每個 Email 每分鐘最多寄送 perEmailLimit 次
+func newMagicLinkTestEnv(t *testing.T, perEmailLimit int64) *magicLinkTestEnv {
+	t.Helper()
+
+	auth := newAuthTestEnv(t)
+	mail := &fakeMailer{}
+	emailLimiter := ratelimit.NewSlidingLog(ratelimit.NewMemoryStore(), ratelimit.Rate{Limit: perEmailLimit, Period: time.Minute}, ratelimit.SystemClock{})
+
+	return &magicLinkTestEnv{
+		auth:    auth,
+		useCase: NewMagicLinkUseCase(auth.useCase, auth.userRepo, auth.useCase.actionTokens, auth.tokenRevocation, mail, emailLimiter, 15*time.Minute, "http://localhost:3000"),
+		mailer:  mail,
+	}
+}
+
+func TestMagicLink_Success(t *testing.T) {
+	env := newMagicLinkTestEnv(t, 5)
+	ctx := context.Background()
+
+	if err := env.useCase.RequestLink(ctx, "test@example.com"); err != nil {
+		t.Fatalf("RequestLink failed: %v", err)
+	}
+	token := env.mailer.lastToken(t)
+
+	resp, err := env.useCase.Consume(ctx, token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Token == "" || resp.RefreshToken == "" {
+		t.Fatalf("Expected access and refresh tokens, got %+v", resp)
+	}
+	claims, err := env.auth.jwtService.ValidateToken(resp.Token)
+	if err != nil || claims.UserID != 1 {
+		t.Errorf("Expected valid access token for user 1, got %+v (%v)", claims, err)
+	}
+	if !env.auth.userRepo.User.IsEmailVerified() {
+		t.Error("Expected email to be marked as verified")
+	}
+
+	// 連結只能使用一次
+	if _, err := env.useCase.Consume(ctx, token); err != customerrors.ErrInvalidMagicLink {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidMagicLink, err)
+	}
+}
+
+func TestMagicLink_InvalidToken(t *testing.T) {
+	testCases := []struct {
+		name  string
+		token func(t *testing.T, env *magicLinkTestEnv) string
+	}{
+		{
+			name: "Malformed",
+			token: func(t *testing.T, env *magicLinkTestEnv) string {
+				return "not-a-token"
+			},
+		},
+		{
+			name: "WrongPurpose",
+			token: func(t *testing.T, env *magicLinkTestEnv) string {
+				token, _ := env.useCase.actionTokens.GenerateToken(service.PurposeEmailVerification, 1, "test@example.com", time.Minute)
+				return token
+			},
+		},
+		{
+			name: "EmailChanged",
+			token: func(t *testing.T, env *magicLinkTestEnv) string {
+				_ = env.useCase.RequestLink(context.Background(), "test@example.com")
+				env.auth.userRepo.User.Email = "new@example.com"
+				return env.mailer.lastToken(t)
+			},
+		},
+		{
+			name: "Expired",
+			token: func(t *testing.T, env *magicLinkTestEnv) string {
+				token, _ := env.useCase.actionTokens.GenerateToken(service.PurposeMagicLink, 1, "test@example.com", -time.Minute)
+				return token
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newMagicLinkTestEnv(t, 5)
+
+			if _, err := env.useCase.Consume(context.Background(), tc.token(t, env)); err != customerrors.ErrInvalidMagicLink {
+				t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidMagicLink, err)
+			}
+			if len(env.auth.sessionRepo.Sessions) != 0 {
+				t.Error("Expected no session to be created")
+			}
+		})
+	}
+}
+
+func TestMagicLink_RateLimitedPerEmail(t *testing.T) {
+	env := newMagicLinkTestEnv(t, 2)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := env.useCase.RequestLink(ctx, "test@example.com"); err != nil {
+			t.Fatalf("Expected request %d to succeed, got %v", i+1, err)
+		}
+	}
+	// 不分大小寫視為同一個 Email
+	if err := env.useCase.RequestLink(ctx, "TEST@example.com"); err != customerrors.ErrTooManyRequests {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrTooManyRequests, err)
+	}
+	if len(env.mailer.sent) != 2 {
+		t.Errorf("Expected 2 emails, got %d", len(env.mailer.sent))
+	}
+
+	// 其他 Email 不受影響
+	if err := env.useCase.RequestLink(ctx, "other@example.com"); err != nil {
+		t.Errorf("Expected other email not to be limited, got %v", err)
+	}
+}
+
+func TestMagicLink_UnknownEmail(t *testing.T) {
+	env := newMagicLinkTestEnv(t, 1)
+	env.auth.userRepo.GetByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+		return nil, sql.ErrNoRows
+	}
+
+	if err := env.useCase.RequestLink(context.Background(), "nobody@example.com"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(env.mailer.sent) != 0 {
+		t.Error("Expected no email to be sent")
+	}
+	// 不存在的 Email 同樣計入次數，回應與存在時一致
+	if err := env.useCase.RequestLink(context.Background(), "nobody@example.com"); err != customerrors.ErrTooManyRequests {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrTooManyRequests, err)
+	}
+}
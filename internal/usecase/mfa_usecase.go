@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/response"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+)
+
+// MFAUseCase 管理 TOTP 兩步驟驗證的設定、啟用與停用
+type MFAUseCase struct {
+	userRepo  contract.UserRepository
+	mfaRepo   contract.MFARepository
+	mfa       *service.MFAService
+	passwords *service.PasswordService
+}
+
+func NewMFAUseCase(userRepo contract.UserRepository, mfaRepo contract.MFARepository, mfa *service.MFAService, passwords *service.PasswordService) *MFAUseCase {
+	return &MFAUseCase{
+		userRepo:  userRepo,
+		mfaRepo:   mfaRepo,
+		mfa:       mfa,
+		passwords: passwords,
+	}
+}
+
+// Setup 產生新的 TOTP secret，需再以第一組驗證碼呼叫 Enable 才會生效
+func (m *MFAUseCase) Setup(ctx context.Context, userID int32) (*response.MFASetupResponse, error) {
+	user, err := m.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	enabled, err := m.mfa.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, customerrors.ErrMFAAlreadyEnabled
+	}
+
+	secret, uri, err := m.mfa.GenerateKey(user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.mfaRepo.Save(ctx, &entity.UserMFA{UserID: userID, Secret: secret}); err != nil {
+		return nil, err
+	}
+
+	return &response.MFASetupResponse{
+		Secret:     secret,
+		OTPAuthURL: uri,
+	}, nil
+}
+
+// Enable 確認第一組驗證碼後啟用兩步驟驗證，並回傳一組備用碼
+func (m *MFAUseCase) Enable(ctx context.Context, userID int32, code string) (*response.MFARecoveryCodesResponse, error) {
+	setting, err := m.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrMFASetupRequired
+		}
+		return nil, err
+	}
+	if setting.IsEnabled() {
+		return nil, customerrors.ErrMFAAlreadyEnabled
+	}
+
+	valid, err := m.mfa.VerifyTOTP(ctx, setting, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, customerrors.ErrInvalidMFACode
+	}
+
+	enabled, err := m.mfaRepo.Enable(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, customerrors.ErrMFAAlreadyEnabled
+	}
+
+	codes, err := m.mfa.GenerateRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &response.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable 停用兩步驟驗證（需要密碼與驗證碼）
+func (m *MFAUseCase) Disable(ctx context.Context, userID int32, req request.DisableMFARequest) error {
+	user, err := m.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrUserNotFound
+		}
+		return err
+	}
+
+	if !m.passwords.Verify(req.Password, user.PasswordHash) {
+		return customerrors.ErrInvalidCredentials
+	}
+
+	if err := m.verifyCode(ctx, userID, req.Code); err != nil {
+		return err
+	}
+
+	return m.mfaRepo.Delete(ctx, userID)
+}
+
+// RegenerateRecoveryCodes 產生新的一組備用碼，舊的備用碼全部失效
+func (m *MFAUseCase) RegenerateRecoveryCodes(ctx context.Context, userID int32, code string) (*response.MFARecoveryCodesResponse, error) {
+	if err := m.verifyCode(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := m.mfa.GenerateRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &response.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// verifyCode 確認已啟用兩步驟驗證且驗證碼正確
+func (m *MFAUseCase) verifyCode(ctx context.Context, userID int32, code string) error {
+	enabled, err := m.mfa.IsEnabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return customerrors.ErrMFANotEnabled
+	}
+
+	valid, err := m.mfa.VerifyCode(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return customerrors.ErrInvalidMFACode
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/pquerna/otp/totp"
+)
+
+func newTestMFAUseCase(env *authTestEnv) *MFAUseCase {
+	return NewMFAUseCase(env.userRepo, env.mfaRepo, service.NewMFAService(env.mfaRepo, "Test"), env.passwords)
+}
+
+// totpCode 產生指定時間的 TOTP 驗證碼
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := totp.GenerateCode(secret, at)
+	if err != nil {
+		t.Fatalf("Failed to generate TOTP code: %v", err)
+	}
+	return code
+}
+
+// enableMFA 完成設定與啟用，回傳 secret 與備用碼
+func enableMFA(t *testing.T, env *authTestEnv) (string, []string) {
+	t.Helper()
+
+	uc := newTestMFAUseCase(env)
+	setupResp, err := uc.Setup(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	codesResp, err := uc.Enable(context.Background(), 1, totpCode(t, setupResp.Secret, time.Now()))
+	if err != nil {
+		t.Fatalf("Enable failed: %v", err)
+	}
+	return setupResp.Secret, codesResp.RecoveryCodes
+}
+
+// loginForMFAToken 以密碼登入並取得 MFA token
+func loginForMFAToken(t *testing.T, uc *AuthUseCase) string {
+	t.Helper()
+
+	resp, err := uc.Login(context.Background(), request.LoginRequest{Email: "test@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !resp.MFARequired || resp.MFAToken == "" {
+		t.Fatal("Expected login to require MFA")
+	}
+	return resp.MFAToken
+}
+
+// =============================================================================
+// Setup / Enable / Disable Tests
+// =============================================================================
+
+func TestMFA_SetupAndEnable(t *testing.T) {
+	env := newAuthTestEnv(t)
+	uc := newTestMFAUseCase(env)
+	ctx := context.Background()
+
+	if _, err := uc.Enable(ctx, 1, "123456"); err != customerrors.ErrMFASetupRequired {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrMFASetupRequired, err)
+	}
+
+	setupResp, err := uc.Setup(ctx, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(setupResp.OTPAuthURL, "otpauth://totp/") || !strings.Contains(setupResp.OTPAuthURL, "issuer=Test") {
+		t.Errorf("Unexpected otpauth URL: %s", setupResp.OTPAuthURL)
+	}
+
+	// 尚未確認驗證碼前，登入不需要兩步驟驗證
+	login(t, env.useCase)
+
+	if _, err := uc.Enable(ctx, 1, "000000"); err != customerrors.ErrInvalidMFACode {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrInvalidMFACode, err)
+	}
+
+	codesResp, err := uc.Enable(ctx, 1, totpCode(t, setupResp.Secret, time.Now()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(codesResp.RecoveryCodes) != 10 {
+		t.Errorf("Expected 10 recovery codes, got %d", len(codesResp.RecoveryCodes))
+	}
+	for hash := range env.mfaRepo.RecoveryCodes[1] {
+		for _, code := range codesResp.RecoveryCodes {
+			if strings.Contains(hash, code) {
+				t.Fatal("Expected recovery codes to be stored as hashes")
+			}
+		}
+	}
+
+	if _, err := uc.Setup(ctx, 1); err != customerrors.ErrMFAAlreadyEnabled {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrMFAAlreadyEnabled, err)
+	}
+}
+
+func TestMFA_Disable(t *testing.T) {
+	env := newAuthTestEnv(t)
+	uc := newTestMFAUseCase(env)
+	ctx := context.Background()
+	secret, _ := enableMFA(t, env)
+	code := totpCode(t, secret, time.Now().Add(30*time.Second))
+
+	if err := uc.Disable(ctx, 1, request.DisableMFARequest{Password: "wrong", Code: code}); err != customerrors.ErrInvalidCredentials {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrInvalidCredentials, err)
+	}
+	if err := uc.Disable(ctx, 1, request.DisableMFARequest{Password: "password123", Code: "000000"}); err != customerrors.ErrInvalidMFACode {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrInvalidMFACode, err)
+	}
+	if err := uc.Disable(ctx, 1, request.DisableMFARequest{Password: "password123", Code: code}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 停用後登入直接取得 token
+	login(t, env.useCase)
+
+	if err := uc.Disable(ctx, 1, request.DisableMFARequest{Password: "password123", Code: code}); err != customerrors.ErrMFANotEnabled {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrMFANotEnabled, err)
+	}
+}
+
+// =============================================================================
+// Login / VerifyMFA Tests
+// =============================================================================
+
+func TestLogin_WithMFA(t *testing.T) {
+	env := newAuthTestEnv(t)
+	ctx := context.Background()
+	secret, _ := enableMFA(t, env)
+
+	resp, err := env.useCase.Login(ctx, request.LoginRequest{Email: "test@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !resp.MFARequired || resp.Token != "" || resp.RefreshToken != "" {
+		t.Fatal("Expected only an MFA token before the second step")
+	}
+	if len(env.refreshRepo.Tokens) != 0 {
+		t.Error("Expected no refresh token to be issued before the second step")
+	}
+
+	// 啟用時已使用目前時間步的驗證碼，改用下一個時間步
+	code := totpCode(t, secret, time.Now().Add(30*time.Second))
+	verifyResp, err := env.useCase.VerifyMFA(ctx, request.VerifyMFARequest{MFAToken: resp.MFAToken, Code: code})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if verifyResp.Token == "" || verifyResp.RefreshToken == "" || verifyResp.MFARequired {
+		t.Error("Expected access and refresh tokens after MFA verification")
+	}
+	if _, err := env.jwtService.ValidateToken(verifyResp.Token); err != nil {
+		t.Errorf("Expected valid access token, got %v", err)
+	}
+
+	// 同一組驗證碼不能重複使用
+	mfaToken := loginForMFAToken(t, env.useCase)
+	if _, err := env.useCase.VerifyMFA(ctx, request.VerifyMFARequest{MFAToken: mfaToken, Code: code}); err != customerrors.ErrInvalidMFACode {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidMFACode, err)
+	}
+}
+
+func TestVerifyMFA_RecoveryCode(t *testing.T) {
+	env := newAuthTestEnv(t)
+	ctx := context.Background()
+	_, recoveryCodes := enableMFA(t, env)
+
+	// 備用碼不分大小寫、可省略分隔符號
+	code := strings.ToUpper(strings.ReplaceAll(recoveryCodes[0], "-", ""))
+
+	mfaToken := loginForMFAToken(t, env.useCase)
+	if _, err := env.useCase.VerifyMFA(ctx, request.VerifyMFARequest{MFAToken: mfaToken, Code: code}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 備用碼只能使用一次
+	mfaToken = loginForMFAToken(t, env.useCase)
+	if _, err := env.useCase.VerifyMFA(ctx, request.VerifyMFARequest{MFAToken: mfaToken, Code: recoveryCodes[0]}); err != customerrors.ErrInvalidMFACode {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidMFACode, err)
+	}
+}
+
+func TestVerifyMFA_InvalidToken(t *testing.T) {
+	env := newAuthTestEnv(t)
+	secret, _ := enableMFA(t, env)
+	code := totpCode(t, secret, time.Now().Add(30*time.Second))
+
+	otherPurpose, _ := service.NewActionTokenService("action-secret").GenerateToken(service.PurposeEmailVerification, 1, "test@example.com", time.Hour)
+
+	for _, token := range []string{"not-a-token", otherPurpose} {
+		if _, err := env.useCase.VerifyMFA(context.Background(), request.VerifyMFARequest{MFAToken: token, Code: code}); err != customerrors.ErrInvalidMFAToken {
+			t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidMFAToken, err)
+		}
+	}
+}
+
+func TestVerifyMFA_FailuresLockAccount(t *testing.T) {
+	env := newLockoutTestEnv(t)
+	ctx := context.Background()
+	enableMFA(t, env)
+
+	mfaToken := loginForMFAToken(t, env.useCase)
+	for i := 0; i < 2; i++ {
+		if _, err := env.useCase.VerifyMFA(ctx, request.VerifyMFARequest{MFAToken: mfaToken, Code: "000000"}); err != customerrors.ErrInvalidMFACode {
+			t.Fatalf("Attempt %d: expected error %v, got %v", i+1, customerrors.ErrInvalidMFACode, err)
+		}
+	}
+
+	// 密碼正確不會清除驗證碼的失敗次數
+	mfaToken = loginForMFAToken(t, env.useCase)
+	if _, err := env.useCase.VerifyMFA(ctx, request.VerifyMFARequest{MFAToken: mfaToken, Code: "000000"}); err != customerrors.ErrAccountLocked {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrAccountLocked, err)
+	}
+}
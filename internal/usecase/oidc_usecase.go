@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/response"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+)
+
+const (
+	oidcUsernameMaxLength = 40 // 保留空間給重複時加上的後綴
+	oidcUsernameAttempts  = 5
+)
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// OIDCUseCase 以外部 OpenID Connect 提供者登入
+// 依 provider + subject 找到已連結的用戶；首次登入時以已驗證的 Email 連結既有帳號或建立新帳號
+type OIDCUseCase struct {
+	auth         *AuthUseCase
+	userRepo     contract.UserRepository
+	identityRepo contract.UserIdentityRepository
+	roleRepo     contract.RoleRepository
+	oidc         *service.OIDCService
+}
+
+func NewOIDCUseCase(
+	auth *AuthUseCase,
+	userRepo contract.UserRepository,
+	identityRepo contract.UserIdentityRepository,
+	roleRepo contract.RoleRepository,
+	oidc *service.OIDCService,
+) *OIDCUseCase {
+	return &OIDCUseCase{
+		auth:         auth,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		roleRepo:     roleRepo,
+		oidc:         oidc,
+	}
+}
+
+// AuthorizationURL 產生導向提供者的授權網址與登入狀態 token（由 handler 存放在 cookie）
+func (o *OIDCUseCase) AuthorizationURL(ctx context.Context, provider string) (string, string, error) {
+	authURL, stateToken, err := o.oidc.AuthCodeURL(ctx, provider)
+	if err != nil {
+		if err == service.ErrOIDCProviderNotFound {
+			return "", "", customerrors.ErrOIDCProviderNotFound
+		}
+		return "", "", err
+	}
+	return authURL, stateToken, nil
+}
+
+// Callback 驗證提供者的回呼並登入，回傳與密碼登入相同的 LoginResponse
+func (o *OIDCUseCase) Callback(ctx context.Context, provider, stateToken string, req request.OIDCCallbackRequest) (*response.LoginResponse, error) {
+	identity, err := o.oidc.Exchange(ctx, provider, stateToken, req.State, req.Code)
+	if err != nil {
+		switch err {
+		case service.ErrOIDCProviderNotFound:
+			return nil, customerrors.ErrOIDCProviderNotFound
+		case service.ErrInvalidOIDCState:
+			return nil, customerrors.ErrInvalidOIDCState
+		case service.ErrOIDCExchangeFailed:
+			return nil, customerrors.ErrOIDCLoginFailed
+		default:
+			return nil, err
+		}
+	}
+
+	user, err := o.resolveUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// 鎖定期間同樣不允許登入，否則可透過外部登入繞過兩步驟驗證的失敗鎖定
+	if user.IsLocked(time.Now()) {
+		return nil, customerrors.ErrAccountLocked
+	}
+
+	return o.auth.startSession(ctx, user)
+}
+
+// resolveUser 找出外部身分對應的用戶，必要時連結或建立帳號
+func (o *OIDCUseCase) resolveUser(ctx context.Context, identity *service.OIDCIdentity) (*entity.User, error) {
+	linked, err := o.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if linked != nil {
+		if err := o.identityRepo.RecordLogin(ctx, linked.ID, identity.Email); err != nil {
+			return nil, err
+		}
+		user, err := o.userRepo.GetByID(ctx, linked.UserID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, customerrors.ErrOIDCLoginFailed
+			}
+			return nil, err
+		}
+		return user, nil
+	}
+
+	// 首次登入只信任提供者已驗證的 Email，避免以他人 Email 接管帳號
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, customerrors.ErrOIDCEmailNotVerified
+	}
+
+	user, err := o.userRepo.GetByEmail(ctx, identity.Email)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	var newUser *entity.User
+	if user != nil {
+		// 本地帳號的 Email 未驗證時不自動連結：該帳號可能是他人預先以這個 Email 註冊的
+		if !user.IsEmailVerified() {
+			return nil, customerrors.ErrOIDCAccountConflict
+		}
+	} else {
+		if newUser, err = o.newUser(ctx, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	// 建立帳號與連結外部身分必須一起成功，否則留下沒有角色或未連結的帳號，之後再登入會因 Email 已被使用而無法連結
+	err = database.WithTransaction(ctx, o.auth.db, func(txCtx context.Context) error {
+		if newUser != nil {
+			created, err := o.createUser(txCtx, newUser)
+			if err != nil {
+				return err
+			}
+			user = created
+		}
+
+		return o.identityRepo.Create(txCtx, &entity.UserIdentity{
+			UserID:   user.ID,
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// newUser 為外部身分準備新帳號
+// 密碼設為無法得知的隨機值，用戶之後可透過忘記密碼流程設定密碼
+func (o *OIDCUseCase) newUser(ctx context.Context, identity *service.OIDCIdentity) (*entity.User, error) {
+	username, err := o.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	randomPassword, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := o.auth.passwords.Hash(randomPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.User{
+		Username:     username,
+		Email:        identity.Email,
+		PasswordHash: hashedPassword,
+	}, nil
+}
+
+// createUser 建立帳號並指派預設角色，需在事務中呼叫
+func (o *OIDCUseCase) createUser(ctx context.Context, user *entity.User) (*entity.User, error) {
+	if err := o.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := o.roleRepo.AssignRole(ctx, user.ID, entity.RoleUser); err != nil {
+		return nil, err
+	}
+
+	// 提供者已驗證過 Email
+	if _, err := o.userRepo.MarkEmailVerified(ctx, user.ID, user.Email); err != nil {
+		return nil, err
+	}
+
+	return o.userRepo.GetByID(ctx, user.ID)
+}
+
+// availableUsername 以 preferred_username 或 Email 前綴產生尚未被使用的用戶名稱
+func (o *OIDCUseCase) availableUsername(ctx context.Context, identity *service.OIDCIdentity) (string, error) {
+	base := identity.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	base = usernameInvalidChars.ReplaceAllString(strings.ToLower(base), "")
+	if len(base) > oidcUsernameMaxLength {
+		base = base[:oidcUsernameMaxLength]
+	}
+	if len(base) < 3 {
+		base = "user"
+	}
+
+	candidate := base
+	for i := 0; i < oidcUsernameAttempts; i++ {
+		existing, err := o.userRepo.GetByUsername(ctx, candidate)
+		if err != nil && err != sql.ErrNoRows {
+			return "", err
+		}
+		if existing == nil {
+			reserved, err := o.userRepo.UsernameReserved(ctx, candidate, 0)
+			if err != nil {
+				return "", err
+			}
+			if !reserved {
+				return candidate, nil
+			}
+		}
+
+		suffix, err := utils.GenerateRandomToken(4)
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%s", base, strings.ToLower(usernameInvalidChars.ReplaceAllString(strings.ToLower(suffix), "")))
+	}
+	return "", customerrors.ErrUserAlreadyExists
+}
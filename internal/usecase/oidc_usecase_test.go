@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/response"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	"github.com/dinosaur1258/GolangFramework/internal/service/oidctest"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+)
+
+type oidcTestEnv struct {
+	*authTestEnv
+	useCase      *OIDCUseCase
+	provider     *oidctest.Provider
+	identityRepo *mock.MockUserIdentityRepository
+	roleRepo     *mock.MockRoleRepository
+}
+
+func newOIDCTestEnv(t *testing.T) *oidcTestEnv {
+	t.Helper()
+
+	env := newAuthTestEnv(t)
+	provider := oidctest.NewProvider(t)
+	provider.Email = env.userRepo.User.Email
+
+	oidcService := service.NewOIDCService([]service.OIDCProviderConfig{{
+		Name:         "test",
+		IssuerURL:    provider.Issuer(),
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/test/callback",
+	}}, "state-secret", 10*time.Minute)
+	identityRepo := mock.NewMockUserIdentityRepository()
+	roleRepo := mock.NewMockRoleRepository()
+
+	return &oidcTestEnv{
+		authTestEnv:  env,
+		useCase:      NewOIDCUseCase(env.useCase, env.userRepo, identityRepo, roleRepo, oidcService),
+		provider:     provider,
+		identityRepo: identityRepo,
+		roleRepo:     roleRepo,
+	}
+}
+
+// oidcLogin 走完導向提供者與回呼的完整流程
+func (env *oidcTestEnv) oidcLogin(t *testing.T) (*response.LoginResponse, error) {
+	t.Helper()
+
+	ctx := context.Background()
+	authURL, stateToken, err := env.useCase.AuthorizationURL(ctx, "test")
+	if err != nil {
+		t.Fatalf("AuthorizationURL failed: %v", err)
+	}
+	code, state := env.provider.Authorize(t, authURL)
+
+	return env.useCase.Callback(ctx, "test", stateToken, request.OIDCCallbackRequest{Code: code, State: state})
+}
+
+func markVerified(user *entity.User) {
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+}
+
+func TestOIDCCallback_LinksVerifiedAccount(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	markVerified(env.userRepo.User)
+
+	resp, err := env.oidcLogin(t)
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	if resp.Token == "" || resp.RefreshToken == "" || resp.User == nil || resp.User.ID != 1 {
+		t.Fatalf("Expected tokens for user 1, got %+v", resp)
+	}
+
+	if len(env.identityRepo.Identities) != 1 {
+		t.Fatalf("Expected 1 linked identity, got %d", len(env.identityRepo.Identities))
+	}
+	identity := env.identityRepo.Identities[0]
+	if identity.UserID != 1 || identity.Provider != "test" || identity.Subject != env.provider.Subject {
+		t.Errorf("Unexpected identity %+v", identity)
+	}
+}
+
+func TestOIDCCallback_ExistingIdentity(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	env.identityRepo.Identities = append(env.identityRepo.Identities, &entity.UserIdentity{
+		ID:       1,
+		UserID:   1,
+		Provider: "test",
+		Subject:  env.provider.Subject,
+	})
+	// 已連結的身分不需再檢查 Email
+	env.provider.Email = "changed@example.com"
+	env.provider.EmailVerified = false
+
+	resp, err := env.oidcLogin(t)
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	if resp.User == nil || resp.User.ID != 1 {
+		t.Fatalf("Expected login as user 1, got %+v", resp.User)
+	}
+
+	identity := env.identityRepo.Identities[0]
+	if identity.LastLoginAt == nil || identity.Email != "changed@example.com" {
+		t.Errorf("Expected last login to be recorded, got %+v", identity)
+	}
+}
+
+func TestOIDCCallback_DoesNotLinkUnverifiedAccount(t *testing.T) {
+	env := newOIDCTestEnv(t)
+
+	_, err := env.oidcLogin(t)
+	if err != customerrors.ErrOIDCAccountConflict {
+		t.Fatalf("Expected ErrOIDCAccountConflict, got %v", err)
+	}
+	if len(env.identityRepo.Identities) != 0 {
+		t.Error("Expected no identity to be linked")
+	}
+}
+
+func TestOIDCCallback_RequiresVerifiedProviderEmail(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	markVerified(env.userRepo.User)
+	env.provider.EmailVerified = false
+
+	_, err := env.oidcLogin(t)
+	if err != customerrors.ErrOIDCEmailNotVerified {
+		t.Fatalf("Expected ErrOIDCEmailNotVerified, got %v", err)
+	}
+}
+
+func TestOIDCCallback_CreatesNewUser(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	env.userRepo.User = nil
+	env.provider.Email = "new.user@example.com"
+	env.provider.PreferredUsername = "New User!"
+	env.userRepo.CreateFunc = func(ctx context.Context, user *entity.User) error {
+		user.ID = 2
+		created := *user
+		env.userRepo.User = &created
+		return nil
+	}
+
+	resp, err := env.oidcLogin(t)
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+
+	user := env.userRepo.User
+	if user == nil || user.Username != "newuser" || user.Email != "new.user@example.com" {
+		t.Fatalf("Unexpected created user %+v", user)
+	}
+	if !user.IsEmailVerified() {
+		t.Error("Expected created user email to be verified")
+	}
+	if roles := env.roleRepo.UserRoles[2]; len(roles) != 1 || roles[0] != entity.RoleUser {
+		t.Errorf("Expected user role, got %v", roles)
+	}
+	if resp.User == nil || resp.User.ID != 2 || resp.Token == "" {
+		t.Errorf("Expected tokens for user 2, got %+v", resp)
+	}
+	if len(env.identityRepo.Identities) != 1 || env.identityRepo.Identities[0].UserID != 2 {
+		t.Errorf("Expected identity linked to user 2, got %+v", env.identityRepo.Identities)
+	}
+}
+
+func TestOIDCCallback_NewUserRollsBack(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	env.userRepo.User = nil
+	env.provider.Email = "new.user@example.com"
+	env.userRepo.CreateFunc = func(ctx context.Context, user *entity.User) error {
+		user.ID = 2
+		return nil
+	}
+	env.roleRepo.Error = errors.New("assign failed")
+
+	if _, err := env.oidcLogin(t); err == nil {
+		t.Fatal("Expected callback to fail")
+	}
+	// 指派角色失敗時建立的帳號一併 rollback，也不連結外部身分
+	if ops := env.tx.Ops(); !slices.Equal(ops, []string{"begin", "rollback"}) {
+		t.Errorf("Expected transaction to be rolled back, got %v", ops)
+	}
+	if len(env.identityRepo.Identities) != 0 {
+		t.Errorf("Expected no identity, got %+v", env.identityRepo.Identities)
+	}
+}
+
+func TestOIDCCallback_RequiresMFA(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	markVerified(env.userRepo.User)
+	secret, _ := enableMFA(t, env.authTestEnv)
+
+	resp, err := env.oidcLogin(t)
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	if !resp.MFARequired || resp.MFAToken == "" || resp.Token != "" {
+		t.Fatalf("Expected MFA challenge, got %+v", resp)
+	}
+
+	verified, err := env.useCase.auth.VerifyMFA(context.Background(), request.VerifyMFARequest{
+		MFAToken: resp.MFAToken,
+		Code:     totpCode(t, secret, time.Now().Add(30*time.Second)), // 啟用時已使用目前時間窗
+	})
+	if err != nil {
+		t.Fatalf("VerifyMFA failed: %v", err)
+	}
+	if verified.Token == "" {
+		t.Error("Expected access token after MFA")
+	}
+}
+
+func TestOIDCCallback_LockedAccount(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	markVerified(env.userRepo.User)
+	until := time.Now().Add(time.Minute)
+	env.userRepo.User.LockedUntil = &until
+
+	if _, err := env.oidcLogin(t); err != customerrors.ErrAccountLocked {
+		t.Fatalf("Expected ErrAccountLocked, got %v", err)
+	}
+}
+
+func TestOIDCCallback_InvalidState(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	ctx := context.Background()
+
+	authURL, stateToken, err := env.useCase.AuthorizationURL(ctx, "test")
+	if err != nil {
+		t.Fatalf("AuthorizationURL failed: %v", err)
+	}
+	code, _ := env.provider.Authorize(t, authURL)
+
+	_, err = env.useCase.Callback(ctx, "test", stateToken, request.OIDCCallbackRequest{Code: code, State: "forged"})
+	if err != customerrors.ErrInvalidOIDCState {
+		t.Errorf("Expected ErrInvalidOIDCState, got %v", err)
+	}
+}
+
+func TestOIDCAuthorizationURL_UnknownProvider(t *testing.T) {
+	env := newOIDCTestEnv(t)
+
+	if _, _, err := env.useCase.AuthorizationURL(context.Background(), "unknown"); err != customerrors.ErrOIDCProviderNotFound {
+		t.Errorf("Expected ErrOIDCProviderNotFound, got %v", err)
+	}
+}
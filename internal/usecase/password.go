@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+)
+
+// checkPassword 依密碼政策檢查新密碼，field 為請求中的欄位名稱
+// 未符合時回傳帶有欄位明細的 ErrPasswordPolicy
+func checkPassword(ctx context.Context, passwords *service.PasswordService, field, password string, user *entity.User) error {
+	violations, err := passwords.Validate(ctx, password, user)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	fields := make([]customerrors.FieldError, len(violations))
+	for i, violation := range violations {
+		fields[i] = customerrors.FieldError{
+			Field:   field,
+			Code:    violation.Code,
+			Message: violation.Message,
+		}
+	}
+	return &customerrors.ValidationError{
+		Err:    customerrors.ErrPasswordPolicy,
+		Fields: fields,
+	}
+}
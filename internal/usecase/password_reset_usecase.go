@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/mailer"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+)
+
+const resetTokenBytes = 32
+
+type PasswordResetUseCase struct {
+	userRepo         contract.UserRepository
+	resetTokenRepo   contract.PasswordResetTokenRepository
+	refreshTokenRepo contract.RefreshTokenRepository
+	sessionRepo      contract.SessionRepository
+	passwords        *service.PasswordService
+	mailer           mailer.Mailer
+	db               *sql.DB // 重設密碼的所有寫入在同一個事務中執行
+	tokenTTL         time.Duration
+	frontendURL      string
+}
+
+func NewPasswordResetUseCase(
+	userRepo contract.UserRepository,
+	resetTokenRepo contract.PasswordResetTokenRepository,
+	refreshTokenRepo contract.RefreshTokenRepository,
+	sessionRepo contract.SessionRepository,
+	passwords *service.PasswordService,
+	mailer mailer.Mailer,
+	db *sql.DB,
+	tokenTTL time.Duration,
+	frontendURL string,
+) *PasswordResetUseCase {
+	return &PasswordResetUseCase{
+		userRepo:         userRepo,
+		resetTokenRepo:   resetTokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		passwords:        passwords,
+		mailer:           mailer,
+		db:               db,
+		tokenTTL:         tokenTTL,
+		frontendURL:      frontendURL,
+	}
+}
+
+// ForgotPassword 寄送密碼重設連結
+// Email 不存在時同樣回傳 nil，避免被用來探測帳號
+func (p *PasswordResetUseCase) ForgotPassword(ctx context.Context, email string) error {
+	user, err := p.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	// 資料庫只保存 token 的雜湊值，原始 token 只出現在郵件中
+	rawToken, err := utils.GenerateRandomToken(resetTokenBytes)
+	if err != nil {
+		return err
+	}
+
+	token := &entity.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(p.tokenTTL),
+	}
+	if err := p.resetTokenRepo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", p.frontendURL, url.QueryEscape(rawToken))
+
+	return p.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s\n\nThis link expires in %s and can only be used once. If you did not request a password reset, you can ignore this email.\n",
+			user.Username, link, p.tokenTTL),
+	})
+}
+
+// ResetPassword 使用重設 token 設定新密碼
+// 成功後讓該用戶既有的 token 全部失效、解除帳號鎖定，並作廢其他尚未使用的重設 token
+func (p *PasswordResetUseCase) ResetPassword(ctx context.Context, req request.ResetPasswordRequest) error {
+	tokenHash := utils.HashToken(req.Token)
+	token, err := p.resetTokenRepo.GetValid(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	user, err := p.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	// 先檢查密碼政策，未通過時 token 仍可再使用
+	if err := checkPassword(ctx, p.passwords, "new_password", req.NewPassword, user); err != nil {
+		return err
+	}
+
+	hashedPassword, err := p.passwords.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	// 使用 token、更新密碼與撤銷既有登入必須一起成功，避免 token 已用掉但密碼未變更，或密碼已變更但舊的登入仍有效
+	return database.WithTransaction(ctx, p.db, func(txCtx context.Context) error {
+		// 標記 token 為已使用，確保同一個 token 只能成功一次
+		if _, err := p.resetTokenRepo.Consume(txCtx, tokenHash); err != nil {
+			if err == sql.ErrNoRows {
+				return customerrors.ErrInvalidResetToken
+			}
+			return err
+		}
+
+		// 只寫入密碼雜湊，避免以事務前讀到的資料覆蓋其他欄位；密碼已在別處變更時 token 視為失效
+		updated, err := p.userRepo.UpdatePasswordHash(txCtx, user.ID, user.PasswordHash, hashedPassword)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return customerrors.ErrInvalidResetToken
+		}
+		if err := p.passwords.Record(txCtx, user.ID, hashedPassword); err != nil {
+			return err
+		}
+
+		if err := revokeAllLogins(txCtx, p.userRepo, p.sessionRepo, p.refreshTokenRepo, user.ID); err != nil {
+			return err
+		}
+		if err := p.userRepo.ResetLoginFailures(txCtx, user.ID); err != nil {
+			return err
+		}
+
+		return p.resetTokenRepo.DeleteAllForUser(txCtx, user.ID)
+	})
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+)
+
+type passwordResetTestEnv struct {
+	auth      *authTestEnv
+	useCase   *PasswordResetUseCase
+	resetRepo *mock.MockPasswordResetTokenRepository
+	mailer    *fakeMailer
+	tx        *testTxLog
+}
+
+func newPasswordResetTestEnv(t *testing.T) *passwordResetTestEnv {
+	t.Helper()
+
+	auth := newAuthTestEnv(t)
+	resetRepo := mock.NewMockPasswordResetTokenRepository()
+	mail := &fakeMailer{}
+	db, tx := newTestDB(t)
+
+	return &passwordResetTestEnv{
+		auth:      auth,
+		useCase:   NewPasswordResetUseCase(auth.userRepo, resetRepo, auth.refreshRepo, auth.sessionRepo, auth.passwords, mail, db, 30*time.Minute, "http://localhost:3000"),
+		resetRepo: resetRepo,
+		mailer:    mail,
+		tx:        tx,
+	}
+}
+
+// =============================================================================
+// ForgotPassword Tests
+// =============================================================================
+
+func TestForgotPassword_StoresHashedToken(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+
+	if err := env.useCase.ForgotPassword(context.Background(), "test@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rawToken := env.mailer.lastToken(t)
+	if _, ok := env.resetRepo.Tokens[rawToken]; ok {
+		t.Error("Expected raw token not to be stored")
+	}
+	if _, ok := env.resetRepo.Tokens[utils.HashToken(rawToken)]; !ok {
+		t.Error("Expected token hash to be stored")
+	}
+}
+
+func TestForgotPassword_UnknownEmail(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+	env.auth.userRepo.GetByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+		return nil, sql.ErrNoRows
+	}
+
+	if err := env.useCase.ForgotPassword(context.Background(), "nobody@example.com"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(env.mailer.sent) != 0 {
+		t.Error("Expected no email to be sent")
+	}
+	if len(env.resetRepo.Tokens) != 0 {
+		t.Error("Expected no token to be stored")
+	}
+}
+
+// =============================================================================
+// ResetPassword Tests
+// =============================================================================
+
+func TestResetPassword_Success(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+	ctx := context.Background()
+
+	refreshToken := login(t, env.auth.useCase)
+	oldVersion := env.auth.userRepo.User.TokenVersion
+
+	if err := env.useCase.ForgotPassword(ctx, "test@example.com"); err != nil {
+		t.Fatalf("ForgotPassword failed: %v", err)
+	}
+	rawToken := env.mailer.lastToken(t)
+
+	req := request.ResetPasswordRequest{Token: rawToken, NewPassword: "newpassword123"}
+	if err := env.useCase.ResetPassword(ctx, req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 既有 session 失效
+	if env.auth.userRepo.User.TokenVersion == oldVersion {
+		t.Error("Expected token version to be incremented")
+	}
+	if _, err := env.auth.useCase.RefreshToken(ctx, refreshToken); err == nil {
+		t.Error("Expected refresh token to be revoked after password reset")
+	}
+	for _, session := range env.auth.sessionRepo.Sessions {
+		if session.RevokedAt == nil {
+			t.Errorf("Expected session %s to be revoked after password reset", session.ID)
+		}
+	}
+
+	// 新密碼可登入，舊密碼不行
+	if _, err := env.auth.useCase.Login(ctx, request.LoginRequest{Email: "test@example.com", Password: "newpassword123"}); err != nil {
+		t.Errorf("Expected login with new password to succeed, got %v", err)
+	}
+	if _, err := env.auth.useCase.Login(ctx, request.LoginRequest{Email: "test@example.com", Password: "password123"}); err != customerrors.ErrInvalidCredentials {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidCredentials, err)
+	}
+
+	// token 只能使用一次
+	if err := env.useCase.ResetPassword(ctx, req); err != customerrors.ErrInvalidResetToken {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidResetToken, err)
+	}
+	if ops := env.tx.Ops(); !slices.Equal(ops, []string{"begin", "commit"}) {
+		t.Errorf("Unexpected transaction operations %v", ops)
+	}
+}
+
+func TestResetPassword_RollbackOnFailure(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+	ctx := context.Background()
+	env.auth.userRepo.UpdateFunc = func(ctx context.Context, user *entity.User) error {
+		t.Error("Expected reset not to update the full row")
+		return nil
+	}
+
+	if err := env.useCase.ForgotPassword(ctx, "test@example.com"); err != nil {
+		t.Fatalf("ForgotPassword failed: %v", err)
+	}
+
+	// 模擬讀取用戶後、事務寫入前，密碼已在別處被變更
+	stale := *env.auth.userRepo.User
+	env.auth.userRepo.GetByIDFunc = func(ctx context.Context, id int32) (*entity.User, error) {
+		return &stale, nil
+	}
+	env.auth.userRepo.User.PasswordHash = "changed-elsewhere"
+
+	err := env.useCase.ResetPassword(ctx, request.ResetPasswordRequest{Token: env.mailer.lastToken(t), NewPassword: "newpassword123"})
+	if err != customerrors.ErrInvalidResetToken {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrInvalidResetToken, err)
+	}
+	if env.auth.userRepo.User.PasswordHash != "changed-elsewhere" {
+		t.Errorf("Expected concurrent password change to be kept, got %s", env.auth.userRepo.User.PasswordHash)
+	}
+	// 更新密碼失敗時使用 token 一併 rollback
+	if ops := env.tx.Ops(); !slices.Equal(ops, []string{"begin", "rollback"}) {
+		t.Errorf("Expected transaction to be rolled back, got %v", ops)
+	}
+}
+
+func TestResetPassword_InvalidToken(t *testing.T) {
+	testCases := []struct {
+		name  string
+		setup func(t *testing.T, env *passwordResetTestEnv) string
+	}{
+		{
+			name: "Unknown",
+			setup: func(t *testing.T, env *passwordResetTestEnv) string {
+				return "unknown-token"
+			},
+		},
+		{
+			name: "Expired",
+			setup: func(t *testing.T, env *passwordResetTestEnv) string {
+				env.resetRepo.Tokens[utils.HashToken("expired-token")] = &entity.PasswordResetToken{
+					UserID:    1,
+					TokenHash: utils.HashToken("expired-token"),
+					ExpiresAt: time.Now().Add(-time.Minute),
+				}
+				return "expired-token"
+			},
+		},
+		{
+			name: "SupersededByReset",
+			setup: func(t *testing.T, env *passwordResetTestEnv) string {
+				ctx := context.Background()
+				_ = env.useCase.ForgotPassword(ctx, "test@example.com")
+				first := env.mailer.lastToken(t)
+				_ = env.useCase.ForgotPassword(ctx, "test@example.com")
+				second := env.mailer.lastToken(t)
+				_ = env.useCase.ResetPassword(ctx, request.ResetPasswordRequest{Token: second, NewPassword: "newpassword123"})
+				return first
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newPasswordResetTestEnv(t)
+			token := tc.setup(t, env)
+			oldHash := env.auth.userRepo.User.PasswordHash
+
+			err := env.useCase.ResetPassword(context.Background(), request.ResetPasswordRequest{Token: token, NewPassword: "anotherpassword"})
+			if err != customerrors.ErrInvalidResetToken {
+				t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidResetToken, err)
+			}
+			if env.auth.userRepo.User.PasswordHash != oldHash {
+				t.Error("Expected password to remain unchanged")
+			}
+		})
+	}
+}
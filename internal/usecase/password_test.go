@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestHasher 測試使用最低 cost，避免拖慢測試
+func newTestHasher() service.PasswordHasher {
+	return service.NewMultiHasher(service.NewBcryptHasher(bcrypt.MinCost))
+}
+
+func newTestPasswordService() *service.PasswordService {
+	return service.NewPasswordService(service.PasswordPolicy{}, newTestHasher(), mock.NewMockPasswordHistoryRepository(), nil)
+}
+
+// fieldErrors 取出 ErrPasswordPolicy 的欄位明細
+func fieldErrors(t *testing.T, err error) []customerrors.FieldError {
+	t.Helper()
+
+	if !errors.Is(err, customerrors.ErrPasswordPolicy) {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrPasswordPolicy, err)
+	}
+	var validationErr *customerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %T", err)
+	}
+	return validationErr.Fields
+}
+
+func TestRegister_PasswordPolicy(t *testing.T) {
+	env := newAuthTestEnv(t)
+	env.userRepo.GetByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+		return nil, sql.ErrNoRows
+	}
+	env.userRepo.GetByUsernameFunc = func(ctx context.Context, username string) (*entity.User, error) {
+		return nil, sql.ErrNoRows
+	}
+	env.useCase.passwords = service.NewPasswordService(service.PasswordPolicy{
+		MinLength:        10,
+		RequireDigit:     true,
+		DisallowUserInfo: true,
+	}, newTestHasher(), env.passwordHistory, nil)
+
+	_, err := env.useCase.Register(context.Background(), request.RegisterRequest{
+		Username: "alice",
+		Email:    "alice@example.com",
+		Password: "Alice",
+	})
+
+	fields := fieldErrors(t, err)
+	codes := map[string]bool{}
+	for _, field := range fields {
+		if field.Field != "password" {
+			t.Errorf("Expected field 'password', got %q", field.Field)
+		}
+		codes[field.Code] = true
+	}
+	for _, code := range []string{service.PasswordTooShort, service.PasswordMissingDigit, service.PasswordContainsUserInfo} {
+		if !codes[code] {
+			t.Errorf("Expected violation %q, got %+v", code, fields)
+		}
+	}
+}
+
+func TestChangePassword_History(t *testing.T) {
+	oldHash, _ := bcrypt.GenerateFromPassword([]byte("first-pass-1"), bcrypt.MinCost)
+	userRepo := &mock.SimpleMockUserRepository{User: &entity.User{
+		ID:           1,
+		Username:     "testuser",
+		Email:        "test@example.com",
+		PasswordHash: string(oldHash),
+	}}
+	history := mock.NewMockPasswordHistoryRepository()
+	passwords := service.NewPasswordService(service.PasswordPolicy{HistorySize: 2}, newTestHasher(), history, nil)
+	uc := NewUserUseCase(userRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), passwords, testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
+
+	change := func(oldPassword, newPassword string) error {
+		return uc.ChangePassword(context.Background(), 1, request.ChangePasswordRequest{
+			OldPassword: oldPassword,
+			NewPassword: newPassword,
+		})
+	}
+
+	// 目前的密碼（上線前設定，不在歷史中）也不能重複使用
+	fields := fieldErrors(t, change("first-pass-1", "first-pass-1"))
+	if len(fields) != 1 || fields[0].Field != "new_password" || fields[0].Code != service.PasswordRecentlyUsed {
+		t.Errorf("Unexpected field errors: %+v", fields)
+	}
+
+	for _, step := range [][2]string{
+		{"first-pass-1", "second-pass-2"},
+		{"second-pass-2", "third-pass-3"},
+		{"third-pass-3", "fourth-pass-4"},
+	} {
+		if err := change(step[0], step[1]); err != nil {
+			t.Fatalf("Expected no error changing to %s, got %v", step[1], err)
+		}
+	}
+	if len(history.Hashes[1]) != 2 {
+		t.Errorf("Expected history to keep 2 entries, got %d", len(history.Hashes[1]))
+	}
+
+	// 最近 2 個密碼不可使用，更早的可以
+	fieldErrors(t, change("fourth-pass-4", "third-pass-3"))
+	if err := change("fourth-pass-4", "second-pass-2"); err != nil {
+		t.Errorf("Expected password outside history to be accepted, got %v", err)
+	}
+}
+
+func TestResetPassword_PolicyViolationKeepsToken(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+	ctx := context.Background()
+
+	if err := env.useCase.ForgotPassword(ctx, "test@example.com"); err != nil {
+		t.Fatalf("ForgotPassword failed: %v", err)
+	}
+	rawToken := env.mailer.lastToken(t)
+
+	err := env.useCase.ResetPassword(ctx, request.ResetPasswordRequest{Token: rawToken, NewPassword: "short"})
+	if fields := fieldErrors(t, err); fields[0].Field != "new_password" {
+		t.Errorf("Expected field 'new_password', got %q", fields[0].Field)
+	}
+
+	// 同一個 token 仍可使用
+	if err := env.useCase.ResetPassword(ctx, request.ResetPasswordRequest{Token: rawToken, NewPassword: "newpassword123"}); err != nil {
+		t.Errorf("Expected token to remain valid, got %v", err)
+	}
+}
+
+func TestLogin_RehashesOutdatedHash(t *testing.T) {
+	testCases := []struct {
+		name   string
+		hasher service.PasswordHasher
+		prefix string
+	}{
+		{name: "HigherBcryptCost", hasher: service.NewBcryptHasher(bcrypt.MinCost + 1), prefix: "$2a$05$"},
+		{name: "Argon2id", hasher: service.NewArgon2idHasher(service.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1}), prefix: "$argon2id$"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newAuthTestEnv(t)
+			env.useCase.passwords = service.NewPasswordService(service.PasswordPolicy{}, service.NewMultiHasher(tc.hasher), env.passwordHistory, nil)
+			// 升級雜湊不得整列覆寫用戶資料
+			env.userRepo.UpdateFunc = func(ctx context.Context, user *entity.User) error {
+				t.Error("Expected rehash not to update the full row")
+				return nil
+			}
+
+			login(t, env.useCase)
+			upgraded := env.userRepo.User.PasswordHash
+			if !strings.HasPrefix(upgraded, tc.prefix) {
+				t.Fatalf("Expected hash to be upgraded to %s, got %s", tc.prefix, upgraded)
+			}
+
+			// 升級後的雜湊可繼續登入，且不再重新雜湊
+			login(t, env.useCase)
+			if env.userRepo.User.PasswordHash != upgraded {
+				t.Errorf("Expected no further rehash, got %s", env.userRepo.User.PasswordHash)
+			}
+		})
+	}
+}
+
+func TestLogin_RehashSkippedWhenPasswordChanged(t *testing.T) {
+	env := newAuthTestEnv(t)
+	env.useCase.passwords = service.NewPasswordService(service.PasswordPolicy{}, service.NewMultiHasher(service.NewBcryptHasher(bcrypt.MinCost+1)), env.passwordHistory, nil)
+
+	// 模擬登入驗證後、寫入升級雜湊前，密碼已在別處被變更
+	stale := *env.userRepo.User
+	env.userRepo.GetByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+		return &stale, nil
+	}
+	env.userRepo.User.PasswordHash = "changed-elsewhere"
+
+	login(t, env.useCase)
+	if env.userRepo.User.PasswordHash != "changed-elsewhere" {
+		t.Errorf("Expected concurrent password change to be kept, got %s", env.userRepo.User.PasswordHash)
+	}
+}
+
+func TestLogin_WrongPasswordDoesNotRehash(t *testing.T) {
+	env := newAuthTestEnv(t)
+	env.useCase.passwords = service.NewPasswordService(service.PasswordPolicy{}, service.NewMultiHasher(service.NewBcryptHasher(bcrypt.MinCost+1)), env.passwordHistory, nil)
+	env.userRepo.UpdateFunc = func(ctx context.Context, user *entity.User) error {
+		t.Error("Expected no update on failed login")
+		return nil
+	}
+
+	if _, err := env.useCase.Login(context.Background(), request.LoginRequest{Email: "test@example.com", Password: "wrong"}); err != customerrors.ErrInvalidCredentials {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidCredentials, err)
+	}
+}
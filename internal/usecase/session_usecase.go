@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/response"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+)
+
+// SessionUseCase 讓用戶查看並移除自己已登入的裝置
+type SessionUseCase struct {
+	sessionRepo      contract.SessionRepository
+	refreshTokenRepo contract.RefreshTokenRepository
+}
+
+func NewSessionUseCase(sessionRepo contract.SessionRepository, refreshTokenRepo contract.RefreshTokenRepository) *SessionUseCase {
+	return &SessionUseCase{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}
+}
+
+// List 列出用戶目前有效的 session，currentSessionID 為發出請求的 session
+func (u *SessionUseCase) List(ctx context.Context, userID int32, currentSessionID string) ([]*response.SessionResponse, error) {
+	sessions, err := u.sessionRepo.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionResponses := make([]*response.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		sessionResponses[i] = response.NewSessionResponse(session, currentSessionID)
+	}
+	return sessionResponses, nil
+}
+
+// Revoke 移除一個裝置：撤銷 session 與其 refresh token family
+// 該 session 的 access token 會在下一個請求被 AuthMiddleware 拒絕
+func (u *SessionUseCase) Revoke(ctx context.Context, userID int32, sessionID string) error {
+	revoked, err := u.sessionRepo.Revoke(ctx, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return customerrors.ErrSessionNotFound
+	}
+
+	return u.refreshTokenRepo.RevokeFamily(ctx, sessionID)
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/utils"
+)
+
+func loginFrom(t *testing.T, env *authTestEnv, info ClientInfo) string {
+	t.Helper()
+
+	resp, err := env.useCase.Login(WithClientInfo(context.Background(), info), request.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	claims, err := env.jwtService.ValidateToken(resp.Token)
+	if err != nil {
+		t.Fatalf("Expected valid access token, got %v", err)
+	}
+	if claims.SessionID == "" {
+		t.Fatal("Expected access token to carry a session id")
+	}
+	return claims.SessionID
+}
+
+func TestLogin_CreatesSession(t *testing.T) {
+	env := newAuthTestEnv(t)
+	sessionID := loginFrom(t, env, ClientInfo{UserAgent: "Mozilla/5.0", IP: "203.0.113.7"})
+
+	session, ok := env.sessionRepo.Sessions[sessionID]
+	if !ok {
+		t.Fatal("Expected session to be stored")
+	}
+	if session.UserID != 1 || session.UserAgent != "Mozilla/5.0" || session.IP != "203.0.113.7" {
+		t.Errorf("Unexpected session: %+v", session)
+	}
+
+	// session 與 refresh token family 使用相同的 ID
+	for _, token := range env.refreshRepo.Tokens {
+		if token.FamilyID != sessionID {
+			t.Errorf("Expected refresh token family %s, got %s", sessionID, token.FamilyID)
+		}
+	}
+}
+
+func TestRefreshToken_ExtendsSession(t *testing.T) {
+	env := newAuthTestEnv(t)
+	refreshToken := login(t, env.useCase)
+	family := env.refreshRepo.Tokens[utils.HashToken(refreshToken)].FamilyID
+	before := *env.sessionRepo.Sessions[family]
+
+	ctx := WithClientInfo(context.Background(), ClientInfo{IP: "198.51.100.1"})
+	if _, err := env.useCase.RefreshToken(ctx, refreshToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	session := env.sessionRepo.Sessions[family]
+	if session.IP != "198.51.100.1" {
+		t.Errorf("Expected IP to be updated, got %s", session.IP)
+	}
+	if session.ExpiresAt.Before(before.ExpiresAt) {
+		t.Error("Expected session expiry to be extended")
+	}
+}
+
+func TestRefreshToken_BackfillsMissingSession(t *testing.T) {
+	env := newAuthTestEnv(t)
+	refreshToken := login(t, env.useCase)
+	family := env.refreshRepo.Tokens[utils.HashToken(refreshToken)].FamilyID
+
+	// 模擬此功能上線前開啟的 token family
+	delete(env.sessionRepo.Sessions, family)
+
+	if _, err := env.useCase.RefreshToken(context.Background(), refreshToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if session, ok := env.sessionRepo.Sessions[family]; !ok || session.UserID != 1 {
+		t.Error("Expected session to be backfilled")
+	}
+}
+
+func TestRefreshToken_RevokedSession(t *testing.T) {
+	env := newAuthTestEnv(t)
+	refreshToken := login(t, env.useCase)
+	family := env.refreshRepo.Tokens[utils.HashToken(refreshToken)].FamilyID
+
+	if _, err := env.sessionRepo.Revoke(context.Background(), family, 1); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if _, err := env.useCase.RefreshToken(context.Background(), refreshToken); err != customerrors.ErrInvalidRefreshToken {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidRefreshToken, err)
+	}
+}
+
+func TestLogout_RevokesSession(t *testing.T) {
+	env := newAuthTestEnv(t)
+	claims, refreshToken := loginClaims(t, env)
+
+	if err := env.useCase.Logout(context.Background(), claims, refreshToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if session := env.sessionRepo.Sessions[claims.SessionID]; session.RevokedAt == nil {
+		t.Error("Expected session to be revoked")
+	}
+}
+
+// =============================================================================
+// SessionUseCase Tests
+// =============================================================================
+
+func TestSessionUseCase_List(t *testing.T) {
+	env := newAuthTestEnv(t)
+	current := loginFrom(t, env, ClientInfo{UserAgent: "laptop"})
+	other := loginFrom(t, env, ClientInfo{UserAgent: "phone"})
+
+	uc := NewSessionUseCase(env.sessionRepo, env.refreshRepo)
+	sessions, err := uc.List(context.Background(), 1, current)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	for _, session := range sessions {
+		if session.Current != (session.ID == current) {
+			t.Errorf("Unexpected current flag on session %s", session.ID)
+		}
+	}
+
+	// 其他用戶看不到
+	if sessions, _ := uc.List(context.Background(), 2, other); len(sessions) != 0 {
+		t.Errorf("Expected no sessions for another user, got %d", len(sessions))
+	}
+}
+
+func TestSessionUseCase_Revoke(t *testing.T) {
+	env := newAuthTestEnv(t)
+	refreshToken := login(t, env.useCase)
+	family := env.refreshRepo.Tokens[utils.HashToken(refreshToken)].FamilyID
+	uc := NewSessionUseCase(env.sessionRepo, env.refreshRepo)
+
+	t.Run("OtherUser", func(t *testing.T) {
+		if err := uc.Revoke(context.Background(), 2, family); err != customerrors.ErrSessionNotFound {
+			t.Errorf("Expected error %v, got %v", customerrors.ErrSessionNotFound, err)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		if err := uc.Revoke(context.Background(), 1, family); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if env.sessionRepo.Sessions[family].RevokedAt == nil {
+			t.Error("Expected session to be revoked")
+		}
+		if _, err := env.useCase.RefreshToken(context.Background(), refreshToken); err == nil {
+			t.Error("Expected refresh token family to be revoked")
+		}
+	})
+
+	t.Run("AlreadyRevoked", func(t *testing.T) {
+		if err := uc.Revoke(context.Background(), 1, family); err != customerrors.ErrSessionNotFound {
+			t.Errorf("Expected error %v, got %v", customerrors.ErrSessionNotFound, err)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		if err := uc.Revoke(context.Background(), 1, "does-not-exist"); err != customerrors.ErrSessionNotFound {
+			t.Errorf("Expected error %v, got %v", customerrors.ErrSessionNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"golang.org/x/text/language"
+)
+
+// 個人檔案欄位的長度上限（字元數）
+const (
+	maxDisplayNameLength = 100
+	maxBioLength         = 500
+	maxAvatarURLLength   = 2048
+)
+
+// 個人檔案欄位的驗證錯誤代碼
+const (
+	profileTooLong       = "too_long"
+	profileInvalidLocale = "invalid_locale"
+	profileInvalidTZ     = "invalid_timezone"
+	profileInvalidURL    = "invalid_url"
+	profileUnknownField  = "unknown_field"
+)
+
+// applyProfile 將請求中有提供的個人檔案欄位套用到 user，回傳不合法的欄位
+// 明確傳入 null 或空字串的欄位會被清除
+func applyProfile(user *entity.User, req request.UpdateUserRequest) []customerrors.FieldError {
+	var fields []customerrors.FieldError
+	add := func(field, code, message string) {
+		fields = append(fields, customerrors.FieldError{Field: field, Code: code, Message: message})
+	}
+
+	if req.DisplayName.Set {
+		displayName := strings.TrimSpace(req.DisplayName.Value)
+		if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+			add("display_name", profileTooLong, fmt.Sprintf("Display name must be at most %d characters", maxDisplayNameLength))
+		}
+		user.DisplayName = displayName
+	}
+
+	if req.Bio.Set {
+		bio := strings.TrimSpace(req.Bio.Value)
+		if utf8.RuneCountInString(bio) > maxBioLength {
+			add("bio", profileTooLong, fmt.Sprintf("Bio must be at most %d characters", maxBioLength))
+		}
+		user.Bio = bio
+	}
+
+	if req.Locale.Set {
+		locale := strings.TrimSpace(req.Locale.Value)
+		if locale != "" {
+			// 以正規化後的標籤儲存，例如 zh-tw 存為 zh-TW
+			tag, err := language.Parse(locale)
+			if err != nil {
+				add("locale", profileInvalidLocale, "Locale must be a valid BCP 47 language tag, e.g. zh-TW")
+			} else {
+				locale = tag.String()
+			}
+		}
+		user.Locale = locale
+	}
+
+	if req.Timezone.Set {
+		timezone := strings.TrimSpace(req.Timezone.Value)
+		if timezone != "" && !validTimezone(timezone) {
+			add("timezone", profileInvalidTZ, "Timezone must be a valid IANA time zone, e.g. Asia/Taipei")
+		}
+		user.Timezone = timezone
+	}
+
+	if req.AvatarURL.Set {
+		avatarURL := strings.TrimSpace(req.AvatarURL.Value)
+		if avatarURL != "" && !validAvatarURL(avatarURL) {
+			add("avatar_url", profileInvalidURL, fmt.Sprintf("Avatar URL must be an http(s) URL of at most %d characters", maxAvatarURLLength))
+		}
+		user.AvatarURL = avatarURL
+	}
+
+	if req.PublicFields.Set {
+		publicFields := []string{}
+		seen := map[string]bool{}
+		for _, field := range req.PublicFields.Value {
+			if !isProfileField(field) {
+				add("public_fields", profileUnknownField, fmt.Sprintf("Unknown profile field %q, allowed: %s", field, strings.Join(entity.ProfileFields, ", ")))
+				continue
+			}
+			if !seen[field] {
+				seen[field] = true
+				publicFields = append(publicFields, field)
+			}
+		}
+		user.PublicFields = publicFields
+	}
+
+	return fields
+}
+
+// validTimezone 是否為 IANA 時區名稱（不接受伺服器相依的 Local）
+func validTimezone(name string) bool {
+	if name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+func validAvatarURL(raw string) bool {
+	if len(raw) > maxAvatarURLLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isProfileField(field string) bool {
+	for _, f := range entity.ProfileFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
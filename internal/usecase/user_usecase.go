@@ -3,21 +3,46 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/dinosaur1258/GolangFramework/internal/domain/contract"
 	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
 	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/response"
+	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
 	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
-	"golang.org/x/crypto/bcrypt"
 )
 
+// UsernameChangePolicy 變更 username 的限制
+type UsernameChangePolicy struct {
+	Cooldown    time.Duration // 兩次變更之間需間隔的時間，0 表示不限制
+	Reservation time.Duration // 舊 username 保留給原用戶的期間，期間內其他帳號無法使用
+}
+
 type UserUseCase struct {
-	userRepo contract.UserRepository
+	userRepo         contract.UserRepository
+	refreshTokenRepo contract.RefreshTokenRepository
+	sessionRepo      contract.SessionRepository
+	roleRepo         contract.RoleRepository
+	passwords        *service.PasswordService
+	db               *sql.DB       // 需要同時成功的多筆寫入在同一個事務中執行
+	restoreWindow    time.Duration // 刪除後可復原的期限，過期後由背景工作永久刪除
+	usernameChange   UsernameChangePolicy
+	avatars          *service.AvatarService // nil 表示不支援上傳頭像
 }
 
-func NewUserUseCase(userRepo contract.UserRepository) *UserUseCase {
+func NewUserUseCase(userRepo contract.UserRepository, refreshTokenRepo contract.RefreshTokenRepository, sessionRepo contract.SessionRepository, roleRepo contract.RoleRepository, passwords *service.PasswordService, db *sql.DB, restoreWindow time.Duration, usernameChange UsernameChangePolicy, avatars *service.AvatarService) *UserUseCase {
 	return &UserUseCase{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		roleRepo:         roleRepo,
+		passwords:        passwords,
+		db:               db,
+		restoreWindow:    restoreWindow,
+		usernameChange:   usernameChange,
+		avatars:          avatars,
 	}
 }
 
@@ -30,18 +55,12 @@ func (u *UserUseCase) GetUserByID(ctx context.Context, id int32) (*response.User
 		return nil, err
 	}
 
-	return &response.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	return u.userResponse(ctx, user)
 }
 
-// UpdateUser 更新用戶資料
-func (u *UserUseCase) UpdateUser(ctx context.Context, userID int32, req request.UpdateUserRequest) (*response.UserResponse, error) {
-	// 取得當前用戶
-	user, err := u.userRepo.GetByID(ctx, userID)
+// GetPublicProfile 取得公開頁面的用戶資料，只包含用戶設為公開的欄位
+func (u *UserUseCase) GetPublicProfile(ctx context.Context, id int32) (*response.PublicUserResponse, error) {
+	user, err := u.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, customerrors.ErrUserNotFound
@@ -49,44 +68,142 @@ func (u *UserUseCase) UpdateUser(ctx context.Context, userID int32, req request.
 		return nil, err
 	}
 
-	// 如果要更新 email，檢查是否已被使用
-	if req.Email != "" && req.Email != user.Email {
-		existingUser, err := u.userRepo.GetByEmail(ctx, req.Email)
-		if err != nil && err != sql.ErrNoRows {
+	resp := response.NewPublicUserResponse(user)
+	if user.IsPublic(entity.ProfileFieldAvatarURL) {
+		if resp.Avatars, err = u.avatarURLs(ctx, user); err != nil {
 			return nil, err
 		}
-		if existingUser != nil {
-			return nil, customerrors.ErrUserAlreadyExists
+	}
+	return resp, nil
+}
+
+// UpdateUser 更新用戶資料
+// Email 需透過 EmailChangeUseCase 變更；username 有變更頻率限制，舊名稱會保留給原用戶一段時間
+func (u *UserUseCase) UpdateUser(ctx context.Context, userID int32, req request.UpdateUserRequest) (*response.UserResponse, error) {
+	var user *entity.User
+
+	// 變更 username 與更新個人檔案必須一起成功，且在事務中重新讀取用戶，避免以過期的資料覆蓋其他變更
+	err := database.WithTransaction(ctx, u.db, func(txCtx context.Context) error {
+		// 取得當前用戶
+		current, err := u.userRepo.GetByID(txCtx, userID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return customerrors.ErrUserNotFound
+			}
+			return err
+		}
+
+		// 直接變更 Email 會讓盜用 session 的人接管帳號，需由新 Email 確認
+		if req.Email != "" && req.Email != current.Email {
+			return customerrors.ErrEmailChangeRequiresConfirmation
+		}
+
+		// 如果要更新 username，檢查變更頻率以及是否已被使用或保留
+		changeUsername := req.Username != "" && req.Username != current.Username
+		if changeUsername {
+			if err := u.checkUsernameAvailable(txCtx, current, req.Username); err != nil {
+				return err
+			}
+		}
+
+		// 個人檔案欄位，任一欄位不合法時不更新
+		if fields := applyProfile(current, req); len(fields) > 0 {
+			return &customerrors.ValidationError{Err: customerrors.ErrInvalidProfile, Fields: fields}
+		}
+
+		if changeUsername {
+			reservedUntil := time.Now().Add(u.usernameChange.Reservation)
+			if err := u.userRepo.ChangeUsername(txCtx, userID, current.Username, req.Username, reservedUntil); err != nil {
+				if err == sql.ErrNoRows {
+					return customerrors.ErrUserNotFound
+				}
+				return err
+			}
+			current.Username = req.Username
+		}
+
+		// 更新用戶
+		if err := u.userRepo.Update(txCtx, current); err != nil {
+			return err
 		}
-		user.Email = req.Email
+		user = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 如果要更新 username，檢查是否已被使用
-	if req.Username != "" && req.Username != user.Username {
-		existingUser, err := u.userRepo.GetByUsername(ctx, req.Username)
-		if err != nil && err != sql.ErrNoRows {
-			return nil, err
+	return u.userResponse(ctx, user)
+}
+
+// checkUsernameAvailable 檢查用戶是否可改用 username
+// 用戶可在保留期間內改回自己先前的 username，但仍受變更頻率限制
+func (u *UserUseCase) checkUsernameAvailable(ctx context.Context, user *entity.User, username string) error {
+	if u.usernameChange.Cooldown > 0 && user.UsernameChangedAt != nil &&
+		time.Since(*user.UsernameChangedAt) < u.usernameChange.Cooldown {
+		return customerrors.ErrUsernameChangeCooldown
+	}
+
+	existingUser, err := u.userRepo.GetByUsername(ctx, username)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if existingUser != nil {
+		return customerrors.ErrUserAlreadyExists
+	}
+
+	reserved, err := u.userRepo.UsernameReserved(ctx, username, user.ID)
+	if err != nil {
+		return err
+	}
+	if reserved {
+		return customerrors.ErrUserAlreadyExists
+	}
+	return nil
+}
+
+// UploadAvatar 以上傳的圖片取代頭像，舊的頭像檔案會被刪除
+func (u *UserUseCase) UploadAvatar(ctx context.Context, userID int32, data []byte) (*response.UserResponse, error) {
+	if u.avatars == nil {
+		return nil, customerrors.ErrInvalidAvatar
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrUserNotFound
 		}
-		if existingUser != nil {
-			return nil, customerrors.ErrUserAlreadyExists
+		return nil, err
+	}
+
+	key, err := u.avatars.Upload(ctx, userID, data)
+	if err != nil {
+		switch err {
+		case service.ErrAvatarTooLarge:
+			return nil, customerrors.ErrAvatarTooLarge
+		case service.ErrAvatarUnsupported:
+			return nil, customerrors.ErrInvalidAvatar
 		}
-		user.Username = req.Username
+		return nil, err
 	}
 
-	// 更新用戶
-	if err := u.userRepo.Update(ctx, user); err != nil {
+	if err := u.userRepo.SetAvatar(ctx, userID, key); err != nil {
+		_ = u.avatars.Remove(ctx, key)
 		return nil, err
 	}
 
-	return &response.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	// 舊檔案刪除失敗只會留下無人引用的檔案，不影響這次上傳
+	if user.AvatarKey != "" {
+		_ = u.avatars.Remove(ctx, user.AvatarKey)
+	}
+
+	user.AvatarKey = key
+	user.AvatarURL = ""
+	return u.userResponse(ctx, user)
 }
 
-// DeleteUser 刪除用戶
+// DeleteUser 刪除用戶（軟刪除）
+// 復原期限內可由管理員復原，期限過後由 PurgeDeletedUsers 永久刪除
 func (u *UserUseCase) DeleteUser(ctx context.Context, userID int32) error {
 	// 檢查用戶是否存在
 	_, err := u.userRepo.GetByID(ctx, userID)
@@ -97,10 +214,77 @@ func (u *UserUseCase) DeleteUser(ctx context.Context, userID int32) error {
 		return err
 	}
 
+	// 讓既有的 token 全部失效
+	if err := u.revokeAllTokens(ctx, userID); err != nil {
+		return err
+	}
+
 	// 刪除用戶
 	return u.userRepo.Delete(ctx, userID)
 }
 
+// RestoreUser 在復原期限內復原已刪除的用戶，用戶需重新登入
+// 刪除後 Email 或 username 已被其他帳號使用時無法復原
+func (u *UserUseCase) RestoreUser(ctx context.Context, userID int32) (*response.UserResponse, error) {
+	user, err := u.userRepo.GetDeletedByID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if time.Since(*user.DeletedAt) > u.restoreWindow {
+		return nil, customerrors.ErrRestoreWindowExpired
+	}
+
+	// 刪除期間 Email 或 username 可能已被新帳號使用
+	existingUser, err := u.userRepo.GetByEmail(ctx, user.Email)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if existingUser != nil {
+		return nil, customerrors.ErrUserAlreadyExists
+	}
+	existingUser, err = u.userRepo.GetByUsername(ctx, user.Username)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if existingUser != nil {
+		return nil, customerrors.ErrUserAlreadyExists
+	}
+
+	restored, err := u.userRepo.Restore(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, customerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return u.userResponse(ctx, restored)
+}
+
+// PurgeDeletedUsers 永久刪除已超過復原期限的用戶與其頭像，回傳刪除的筆數
+// 帳號刪除後頭像檔案不會再被引用，刪除檔案失敗時仍回傳筆數與第一個錯誤
+func (u *UserUseCase) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	avatarKeys, err := u.userRepo.PurgeDeleted(ctx, time.Now().Add(-u.restoreWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	var firstErr error
+	for _, key := range avatarKeys {
+		if key == "" || u.avatars == nil {
+			continue
+		}
+		if err := u.avatars.Remove(ctx, key); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return int64(len(avatarKeys)), firstErr
+}
+
 // ListUsers 列出所有用戶（分頁）
 func (u *UserUseCase) ListUsers(ctx context.Context, page, limit int) ([]*response.UserResponse, error) {
 	// 預設值
@@ -126,11 +310,8 @@ func (u *UserUseCase) ListUsers(ctx context.Context, page, limit int) ([]*respon
 	// 轉換成 Response
 	userResponses := make([]*response.UserResponse, len(users))
 	for i, user := range users {
-		userResponses[i] = &response.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt,
+		if userResponses[i], err = u.userResponse(ctx, user); err != nil {
+			return nil, err
 		}
 	}
 
@@ -149,18 +330,89 @@ func (u *UserUseCase) ChangePassword(ctx context.Context, userID int32, req requ
 	}
 
 	// 驗證舊密碼
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)); err != nil {
+	if !u.passwords.Verify(req.OldPassword, user.PasswordHash) {
 		return customerrors.ErrInvalidCredentials
 	}
 
-	// 加密新密碼
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	// 檢查密碼政策並加密新密碼
+	if err := checkPassword(ctx, u.passwords, "new_password", req.NewPassword, user); err != nil {
+		return err
+	}
+	hashedPassword, err := u.passwords.Hash(req.NewPassword)
 	if err != nil {
 		return err
 	}
 
-	user.PasswordHash = string(hashedPassword)
+	// 更新密碼、記錄密碼歷史與撤銷既有登入必須一起成功，避免密碼已變更但舊的登入仍有效
+	return database.WithTransaction(ctx, u.db, func(txCtx context.Context) error {
+		// 只寫入密碼雜湊，且舊密碼驗證後密碼已在別處變更時不覆蓋
+		updated, err := u.userRepo.UpdatePasswordHash(txCtx, userID, user.PasswordHash, hashedPassword)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return customerrors.ErrInvalidCredentials
+		}
+		if err := u.passwords.Record(txCtx, userID, hashedPassword); err != nil {
+			return err
+		}
+
+		// 修改密碼後，讓既有的 token 全部失效
+		return u.revokeAllTokens(txCtx, userID)
+	})
+}
+
+// UpdateUserRole 變更用戶角色（管理員功能）
+// 角色存在 token 中，因此變更後會讓該用戶既有的 token 失效，重新登入後生效
+func (u *UserUseCase) UpdateUserRole(ctx context.Context, userID int32, role string) error {
+	if _, err := u.userRepo.GetByID(ctx, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := u.roleRepo.SetUserRole(ctx, userID, role); err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrRoleNotFound
+		}
+		return err
+	}
+
+	return u.revokeAllTokens(ctx, userID)
+}
+
+// UnlockUser 解除帳號鎖定並清除登入失敗次數（管理員功能）
+func (u *UserUseCase) UnlockUser(ctx context.Context, userID int32) error {
+	if _, err := u.userRepo.GetByID(ctx, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return customerrors.ErrUserNotFound
+		}
+		return err
+	}
+
+	return u.userRepo.ResetLoginFailures(ctx, userID)
+}
+
+// userResponse 轉換為 API 回應並附上頭像的簽章網址
+func (u *UserUseCase) userResponse(ctx context.Context, user *entity.User) (*response.UserResponse, error) {
+	resp := response.NewUserResponse(user)
+	avatars, err := u.avatarURLs(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	resp.Avatars = avatars
+	return resp, nil
+}
+
+func (u *UserUseCase) avatarURLs(ctx context.Context, user *entity.User) (response.Avatars, error) {
+	if u.avatars == nil || user.AvatarKey == "" {
+		return nil, nil
+	}
+	return u.avatars.URLs(ctx, user.AvatarKey)
+}
 
-	// 更新用戶
-	return u.userRepo.Update(ctx, user)
+// revokeAllTokens 遞增 token 版本並撤銷所有 session 與 refresh token
+func (u *UserUseCase) revokeAllTokens(ctx context.Context, userID int32) error {
+	return revokeAllLogins(ctx, u.userRepo, u.sessionRepo, u.refreshTokenRepo, userID)
 }
@@ -1,15 +1,26 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/dinosaur1258/GolangFramework/internal/domain/dto/request"
 	"github.com/dinosaur1258/GolangFramework/internal/domain/entity"
 	"github.com/dinosaur1258/GolangFramework/internal/repository/mock"
+	"github.com/dinosaur1258/GolangFramework/internal/service"
+	"github.com/dinosaur1258/GolangFramework/pkg/database"
 	customerrors "github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/dinosaur1258/GolangFramework/pkg/storage"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -65,7 +76,7 @@ func TestGetUserByID(t *testing.T) {
 				User:  tc.mockUser,
 				Error: tc.mockError,
 			}
-			usecase := NewUserUseCase(mockRepo)
+			usecase := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
 
 			result, err := usecase.GetUserByID(context.Background(), tc.userID)
 
@@ -111,7 +122,7 @@ func TestUpdateUser(t *testing.T) {
 			userID: 1,
 			request: request.UpdateUserRequest{
 				Username: "newname",
-				Email:    "new@example.com",
+				Email:    "old@example.com", // 與目前相同的 Email 不視為變更
 			},
 			setupMock: func() *mock.SimpleMockUserRepository {
 				existingUser := &entity.User{
@@ -121,6 +132,7 @@ func TestUpdateUser(t *testing.T) {
 				}
 
 				return &mock.SimpleMockUserRepository{
+					User: existingUser,
 					GetByIDFunc: func(ctx context.Context, id int32) (*entity.User, error) {
 						if id == 1 {
 							return existingUser, nil
@@ -144,6 +156,23 @@ func TestUpdateUser(t *testing.T) {
 			},
 			expectError: nil,
 		},
+		{
+			name:   "EmailChangeRequiresConfirmation",
+			userID: 1,
+			request: request.UpdateUserRequest{
+				Email: "new@example.com",
+			},
+			setupMock: func() *mock.SimpleMockUserRepository {
+				return &mock.SimpleMockUserRepository{
+					User: &entity.User{ID: 1, Username: "oldname", Email: "old@example.com"},
+					UpdateFunc: func(ctx context.Context, user *entity.User) error {
+						t.Error("Expected no update when changing email directly")
+						return nil
+					},
+				}
+			},
+			expectError: customerrors.ErrEmailChangeRequiresConfirmation,
+		},
 		{
 			name:   "UserNotFound",
 			userID: 999,
@@ -163,7 +192,7 @@ func TestUpdateUser(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockRepo := tc.setupMock()
-			usecase := NewUserUseCase(mockRepo)
+			usecase := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
 
 			result, err := usecase.UpdateUser(context.Background(), tc.userID, tc.request)
 
@@ -183,6 +212,202 @@ func TestUpdateUser(t *testing.T) {
 	}
 }
 
+func TestUpdateUser_ProfilePatch(t *testing.T) {
+	mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{
+		ID:          1,
+		Username:    "testuser",
+		Email:       "test@example.com",
+		DisplayName: "Test",
+		Bio:         "Hello",
+		Timezone:    "UTC",
+	}}
+	uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
+
+	patch := func(body string) (*entity.User, error) {
+		var req request.UpdateUserRequest
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatalf("Invalid request body: %v", err)
+		}
+		_, err := uc.UpdateUser(context.Background(), 1, req)
+		return mockRepo.User, err
+	}
+
+	// 未提供的欄位不變，null 清除，提供的值會正規化
+	user, err := patch(`{"bio": null, "locale": "zh-tw", "public_fields": ["display_name", "display_name"]}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.DisplayName != "Test" || user.Timezone != "UTC" {
+		t.Errorf("Expected omitted fields to stay unchanged, got %+v", user)
+	}
+	if user.Bio != "" {
+		t.Errorf("Expected bio to be cleared, got %q", user.Bio)
+	}
+	if user.Locale != "zh-TW" {
+		t.Errorf("Expected canonical locale zh-TW, got %q", user.Locale)
+	}
+	if len(user.PublicFields) != 1 || user.PublicFields[0] != entity.ProfileFieldDisplayName {
+		t.Errorf("Expected public fields [display_name], got %v", user.PublicFields)
+	}
+
+	// 任一欄位不合法時不更新
+	mockRepo.UpdateFunc = func(ctx context.Context, user *entity.User) error {
+		t.Error("Expected no update when a field is invalid")
+		return nil
+	}
+	_, err = patch(`{"display_name": "Changed", "timezone": "Mars/Olympus", "avatar_url": "ftp://example.com/a.png", "public_fields": ["password_hash"]}`)
+	var validationErr *customerrors.ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, customerrors.ErrInvalidProfile) {
+		t.Fatalf("Expected error %v, got %v", customerrors.ErrInvalidProfile, err)
+	}
+	invalid := map[string]bool{}
+	for _, field := range validationErr.Fields {
+		invalid[field.Field] = true
+	}
+	for _, field := range []string{"timezone", "avatar_url", "public_fields"} {
+		if !invalid[field] {
+			t.Errorf("Expected %s to be reported, got %+v", field, validationErr.Fields)
+		}
+	}
+}
+
+func TestUpdateUser_UsernameChange(t *testing.T) {
+	mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{ID: 1, Username: "alice", Email: "alice@example.com"}}
+	mockRepo.GetByUsernameFunc = func(ctx context.Context, username string) (*entity.User, error) {
+		if username == mockRepo.User.Username {
+			return mockRepo.User, nil
+		}
+		return nil, sql.ErrNoRows
+	}
+	uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour,
+		UsernameChangePolicy{Cooldown: 24 * time.Hour, Reservation: 90 * 24 * time.Hour}, nil)
+
+	rename := func(username string) error {
+		_, err := uc.UpdateUser(context.Background(), 1, request.UpdateUserRequest{Username: username})
+		return err
+	}
+
+	if err := rename("alice2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mockRepo.User.Username != "alice2" || mockRepo.User.UsernameChangedAt == nil {
+		t.Fatalf("Expected username to be changed, got %+v", mockRepo.User)
+	}
+	if mockRepo.ReservedUsernames["alice"] != 1 {
+		t.Errorf("Expected old username to be reserved, got %v", mockRepo.ReservedUsernames)
+	}
+
+	// 冷卻期間內無法再次變更
+	if err := rename("alice3"); err != customerrors.ErrUsernameChangeCooldown {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrUsernameChangeCooldown, err)
+	}
+
+	// 其他用戶保留的名稱無法使用，自己保留的可以改回
+	changedAt := time.Now().Add(-48 * time.Hour)
+	mockRepo.User.UsernameChangedAt = &changedAt
+	mockRepo.ReservedUsernames["bob"] = 2
+	if err := rename("bob"); err != customerrors.ErrUserAlreadyExists {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrUserAlreadyExists, err)
+	}
+	if err := rename("alice"); err != nil {
+		t.Fatalf("Expected to reclaim own reserved username, got %v", err)
+	}
+	if _, ok := mockRepo.ReservedUsernames["alice"]; ok || mockRepo.ReservedUsernames["alice2"] != 1 {
+		t.Errorf("Expected reservation to move to alice2, got %v", mockRepo.ReservedUsernames)
+	}
+}
+
+func TestUpdateUser_Transaction(t *testing.T) {
+	mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{ID: 1, Username: "alice", Email: "alice@example.com"}}
+	// 用戶必須在事務中重新讀取
+	mockRepo.GetByIDFunc = func(ctx context.Context, id int32) (*entity.User, error) {
+		if _, ok := database.GetTx(ctx); !ok {
+			t.Error("Expected user to be read inside the transaction")
+		}
+		user := *mockRepo.User
+		return &user, nil
+	}
+	mockRepo.GetByUsernameFunc = func(ctx context.Context, username string) (*entity.User, error) {
+		return nil, sql.ErrNoRows
+	}
+	errUpdate := errors.New("update failed")
+	mockRepo.UpdateFunc = func(ctx context.Context, user *entity.User) error {
+		return errUpdate
+	}
+	db, tx := newTestDB(t)
+	uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), db, 30*24*time.Hour, UsernameChangePolicy{}, nil)
+
+	_, err := uc.UpdateUser(context.Background(), 1, request.UpdateUserRequest{Username: "alice2"})
+	if !errors.Is(err, errUpdate) {
+		t.Fatalf("Expected update error, got %v", err)
+	}
+	// 更新個人檔案失敗時變更 username 一併 rollback
+	if ops := tx.Ops(); !slices.Equal(ops, []string{"begin", "rollback"}) {
+		t.Errorf("Expected transaction to be rolled back, got %v", ops)
+	}
+}
+
+func TestGetPublicProfile(t *testing.T) {
+	mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{
+		ID:           1,
+		Username:     "testuser",
+		Email:        "test@example.com",
+		DisplayName:  "Test",
+		Bio:          "Hello",
+		PublicFields: []string{entity.ProfileFieldDisplayName},
+	}}
+	uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
+
+	profile, err := uc.GetPublicProfile(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if profile.Username != "testuser" || profile.DisplayName != "Test" {
+		t.Errorf("Expected username and public display name, got %+v", profile)
+	}
+	if profile.Email != "" || profile.Bio != "" {
+		t.Errorf("Expected private fields to be hidden, got %+v", profile)
+	}
+}
+
+func TestUploadAvatar(t *testing.T) {
+	root := t.TempDir()
+	store, err := storage.NewLocalStore(root, "http://localhost:8080/media", "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStore failed: %v", err)
+	}
+	avatars := service.NewAvatarService(store, []int{32}, 1<<20, time.Minute)
+	mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{ID: 1, Username: "testuser", AvatarURL: "https://example.com/old.png"}}
+	uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, avatars)
+
+	var img bytes.Buffer
+	_ = png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 40, 40)))
+
+	first, err := uc.UploadAvatar(context.Background(), 1, img.Bytes())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if first.AvatarURL != "" || first.Avatars["32"] == "" {
+		t.Errorf("Expected uploaded avatar to replace the external URL, got %+v", first)
+	}
+	firstKey := mockRepo.User.AvatarKey
+
+	// 再次上傳時刪除舊的檔案
+	if _, err := uc.UploadAvatar(context.Background(), 1, img.Bytes()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mockRepo.User.AvatarKey == firstKey {
+		t.Error("Expected a new avatar key")
+	}
+	if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(firstKey), "32.jpg")); !os.IsNotExist(err) {
+		t.Errorf("Expected old avatar to be removed, got %v", err)
+	}
+
+	if _, err := uc.UploadAvatar(context.Background(), 1, []byte("not an image")); err != customerrors.ErrInvalidAvatar {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidAvatar, err)
+	}
+}
+
 // =============================================================================
 // DeleteUser Tests
 // =============================================================================
@@ -220,7 +445,7 @@ func TestDeleteUser(t *testing.T) {
 				User:  tc.mockUser,
 				Error: tc.mockError,
 			}
-			usecase := NewUserUseCase(mockRepo)
+			usecase := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
 
 			err := usecase.DeleteUser(context.Background(), tc.userID)
 
@@ -237,6 +462,123 @@ func TestDeleteUser(t *testing.T) {
 	}
 }
 
+func TestDeleteUser_SoftDeletes(t *testing.T) {
+	mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}}
+	uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
+
+	if err := uc.DeleteUser(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mockRepo.User == nil || !mockRepo.User.IsDeleted() {
+		t.Fatal("Expected user to be kept and marked as deleted")
+	}
+	if _, err := uc.GetUserByID(context.Background(), 1); err != customerrors.ErrUserNotFound {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrUserNotFound, err)
+	}
+	if err := uc.DeleteUser(context.Background(), 1); err != customerrors.ErrUserNotFound {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrUserNotFound, err)
+	}
+}
+
+// =============================================================================
+// RestoreUser Tests
+// =============================================================================
+
+func TestRestoreUser(t *testing.T) {
+	testCases := []struct {
+		name        string
+		deletedAgo  time.Duration // 0 表示未刪除
+		setup       func(repo *mock.SimpleMockUserRepository)
+		expectError error
+	}{
+		{name: "Success", deletedAgo: 24 * time.Hour},
+		{name: "NotDeleted", expectError: customerrors.ErrUserNotFound},
+		{name: "WindowExpired", deletedAgo: 8 * 24 * time.Hour, expectError: customerrors.ErrRestoreWindowExpired},
+		{
+			name:       "EmailTaken",
+			deletedAgo: time.Hour,
+			setup: func(repo *mock.SimpleMockUserRepository) {
+				repo.GetByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+					return &entity.User{ID: 2, Email: email}, nil
+				}
+			},
+			expectError: customerrors.ErrUserAlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}
+			if tc.deletedAgo > 0 {
+				deletedAt := time.Now().Add(-tc.deletedAgo)
+				user.DeletedAt = &deletedAt
+			}
+			mockRepo := &mock.SimpleMockUserRepository{User: user}
+			if tc.setup != nil {
+				tc.setup(mockRepo)
+			}
+			uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 7*24*time.Hour, UsernameChangePolicy{}, nil)
+
+			resp, err := uc.RestoreUser(context.Background(), 1)
+			if err != tc.expectError {
+				t.Fatalf("Expected error %v, got %v", tc.expectError, err)
+			}
+			if tc.expectError != nil {
+				return
+			}
+			if resp.ID != 1 || user.IsDeleted() {
+				t.Errorf("Expected user 1 to be restored, got %+v", resp)
+			}
+		})
+	}
+}
+
+func TestPurgeDeletedUsers(t *testing.T) {
+	deletedAt := time.Now().Add(-2 * time.Hour)
+	mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{ID: 1, DeletedAt: &deletedAt}}
+
+	// 仍在復原期限內
+	uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 3*time.Hour, UsernameChangePolicy{}, nil)
+	if purged, err := uc.PurgeDeletedUsers(context.Background()); err != nil || purged != 0 {
+		t.Fatalf("Expected nothing to be purged, got %d (%v)", purged, err)
+	}
+
+	uc = NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), time.Hour, UsernameChangePolicy{}, nil)
+	if purged, err := uc.PurgeDeletedUsers(context.Background()); err != nil || purged != 1 {
+		t.Fatalf("Expected 1 user to be purged, got %d (%v)", purged, err)
+	}
+	if mockRepo.User != nil {
+		t.Error("Expected user to be permanently deleted")
+	}
+}
+
+func TestPurgeDeletedUsers_RemovesAvatar(t *testing.T) {
+	root := t.TempDir()
+	store, err := storage.NewLocalStore(root, "http://localhost:8080/media", "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStore failed: %v", err)
+	}
+	avatars := service.NewAvatarService(store, []int{32}, 1<<20, time.Minute)
+	mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{ID: 1, Username: "testuser"}}
+	uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), time.Hour, UsernameChangePolicy{}, avatars)
+
+	var img bytes.Buffer
+	_ = png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 40, 40)))
+	if _, err := uc.UploadAvatar(context.Background(), 1, img.Bytes()); err != nil {
+		t.Fatalf("UploadAvatar failed: %v", err)
+	}
+	avatarPath := filepath.Join(root, filepath.FromSlash(mockRepo.User.AvatarKey), "32.jpg")
+
+	deletedAt := time.Now().Add(-2 * time.Hour)
+	mockRepo.User.DeletedAt = &deletedAt
+	if purged, err := uc.PurgeDeletedUsers(context.Background()); err != nil || purged != 1 {
+		t.Fatalf("Expected 1 user to be purged, got %d (%v)", purged, err)
+	}
+	if _, err := os.Stat(avatarPath); !os.IsNotExist(err) {
+		t.Errorf("Expected avatar to be removed, got %v", err)
+	}
+}
+
 // =============================================================================
 // ListUsers Tests
 // =============================================================================
@@ -281,7 +623,7 @@ func TestListUsers(t *testing.T) {
 				User:  tc.mockUser,
 				Error: tc.mockError,
 			}
-			usecase := NewUserUseCase(mockRepo)
+			usecase := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
 
 			result, err := usecase.ListUsers(context.Background(), tc.page, tc.limit)
 
@@ -365,7 +707,10 @@ func TestChangePassword(t *testing.T) {
 				User:  tc.mockUser,
 				Error: tc.mockError,
 			}
-			usecase := NewUserUseCase(mockRepo)
+			sessionRepo := mock.NewMockSessionRepository()
+			sessionRepo.Sessions["session-1"] = &entity.UserSession{ID: "session-1", UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+			db, tx := newTestDB(t)
+			usecase := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), sessionRepo, mock.NewMockRoleRepository(), newTestPasswordService(), db, 30*24*time.Hour, UsernameChangePolicy{}, nil)
 
 			err := usecase.ChangePassword(context.Background(), tc.userID, tc.request)
 
@@ -377,7 +722,133 @@ func TestChangePassword(t *testing.T) {
 				if err != nil {
 					t.Errorf("Expected no error, got %v", err)
 				}
+				// 修改密碼後其他裝置的 session 一併撤銷
+				if sessionRepo.Sessions["session-1"].RevokedAt == nil {
+					t.Error("Expected session to be revoked")
+				}
+				if ops := tx.Ops(); !slices.Equal(ops, []string{"begin", "commit"}) {
+					t.Errorf("Expected one committed transaction, got %v", ops)
+				}
+			}
+		})
+	}
+}
+
+func TestChangePassword_Atomic(t *testing.T) {
+	oldPasswordHash, _ := bcrypt.GenerateFromPassword([]byte("oldpass123"), bcrypt.MinCost)
+	req := request.ChangePasswordRequest{OldPassword: "oldpass123", NewPassword: "newpass456"}
+
+	t.Run("RollbackOnRevokeFailure", func(t *testing.T) {
+		mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{ID: 1, Username: "testuser", PasswordHash: string(oldPasswordHash)}}
+		mockRepo.UpdateFunc = func(ctx context.Context, user *entity.User) error {
+			t.Error("Expected change password not to update the full row")
+			return nil
+		}
+		errRevoke := errors.New("revoke failed")
+		sessionRepo := mock.NewMockSessionRepository()
+		sessionRepo.Error = errRevoke
+		db, tx := newTestDB(t)
+		uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), sessionRepo, mock.NewMockRoleRepository(), newTestPasswordService(), db, 30*24*time.Hour, UsernameChangePolicy{}, nil)
+
+		if err := uc.ChangePassword(context.Background(), 1, req); !errors.Is(err, errRevoke) {
+			t.Fatalf("Expected revoke error, got %v", err)
+		}
+		// 撤銷登入失敗時密碼變更一併 rollback
+		if ops := tx.Ops(); !slices.Equal(ops, []string{"begin", "rollback"}) {
+			t.Errorf("Expected transaction to be rolled back, got %v", ops)
+		}
+	})
+
+	t.Run("PasswordChangedConcurrently", func(t *testing.T) {
+		mockRepo := &mock.SimpleMockUserRepository{User: &entity.User{ID: 1, Username: "testuser", PasswordHash: "changed-elsewhere"}}
+		// 驗證舊密碼時讀到的是變更前的資料
+		mockRepo.GetByIDFunc = func(ctx context.Context, id int32) (*entity.User, error) {
+			return &entity.User{ID: 1, Username: "testuser", PasswordHash: string(oldPasswordHash)}, nil
+		}
+		uc := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
+
+		if err := uc.ChangePassword(context.Background(), 1, req); err != customerrors.ErrInvalidCredentials {
+			t.Errorf("Expected error %v, got %v", customerrors.ErrInvalidCredentials, err)
+		}
+		if mockRepo.User.PasswordHash != "changed-elsewhere" {
+			t.Errorf("Expected concurrent password change to be kept, got %s", mockRepo.User.PasswordHash)
+		}
+	})
+}
+
+// =============================================================================
+// UpdateUserRole Tests
+// =============================================================================
+
+func TestUpdateUserRole(t *testing.T) {
+	testCases := []struct {
+		name        string
+		role        string
+		mockUser    *entity.User
+		mockError   error
+		expectError error
+	}{
+		{
+			name:        "Success",
+			role:        entity.RoleAdmin,
+			mockUser:    &entity.User{ID: 1, Username: "testuser"},
+			expectError: nil,
+		},
+		{
+			name:        "RoleNotFound",
+			role:        "superuser",
+			mockUser:    &entity.User{ID: 1, Username: "testuser"},
+			expectError: customerrors.ErrRoleNotFound,
+		},
+		{
+			name:        "UserNotFound",
+			role:        entity.RoleAdmin,
+			mockUser:    nil,
+			mockError:   sql.ErrNoRows,
+			expectError: customerrors.ErrUserNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &mock.SimpleMockUserRepository{
+				User:  tc.mockUser,
+				Error: tc.mockError,
+			}
+			roleRepo := mock.NewMockRoleRepository()
+			usecase := NewUserUseCase(mockRepo, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), roleRepo, newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
+
+			err := usecase.UpdateUserRole(context.Background(), 1, tc.role)
+
+			if err != tc.expectError {
+				t.Fatalf("Expected error %v, got %v", tc.expectError, err)
+			}
+			if tc.expectError == nil {
+				if roles := roleRepo.UserRoles[1]; len(roles) != 1 || roles[0] != tc.role {
+					t.Errorf("Expected roles [%s], got %v", tc.role, roles)
+				}
+				if tc.mockUser.TokenVersion != 1 {
+					t.Error("Expected token version to be bumped after role change")
+				}
 			}
 		})
 	}
 }
+
+func TestUnlockUser(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Hour)
+	user := &entity.User{ID: 1, Username: "testuser", FailedLoginAttempts: 5, LockedUntil: &lockedUntil}
+	usecase := NewUserUseCase(&mock.SimpleMockUserRepository{User: user}, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
+
+	if err := usecase.UnlockUser(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.IsLocked(time.Now()) || user.FailedLoginAttempts != 0 {
+		t.Error("Expected user to be unlocked with failed attempts cleared")
+	}
+
+	notFound := NewUserUseCase(&mock.SimpleMockUserRepository{Error: sql.ErrNoRows}, mock.NewMockRefreshTokenRepository(), mock.NewMockSessionRepository(), mock.NewMockRoleRepository(), newTestPasswordService(), testDB(t), 30*24*time.Hour, UsernameChangePolicy{}, nil)
+	if err := notFound.UnlockUser(context.Background(), 1); err != customerrors.ErrUserNotFound {
+		t.Errorf("Expected error %v, got %v", customerrors.ErrUserNotFound, err)
+	}
+}
@@ -0,0 +1,54 @@
+// Package worker 在背景週期性執行維護工作（例如清除過期資料）
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job 週期性執行的背景工作
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start 在背景立即執行一次工作，之後每隔 Interval 執行，ctx 結束時停止
+// 工作回傳錯誤或 panic 時只記錄日誌，不影響下一次執行
+func Start(ctx context.Context, log *zap.Logger, job Job) {
+	go func() {
+		ticker := time.NewTicker(job.Interval)
+		defer ticker.Stop()
+
+		for {
+			runOnce(ctx, log, job)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func runOnce(ctx context.Context, log *zap.Logger, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("Background job panicked",
+				zap.String("job", job.Name),
+				zap.String("panic", fmt.Sprint(r)))
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Error("Background job failed",
+			zap.String("job", job.Name),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestStart_RunsPeriodicallyUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+
+	Start(ctx, zap.NewNop(), Job{
+		Name:     "test",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected at least 3 runs, got %d", runs.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	time.Sleep(30 * time.Millisecond) // 等待進行中的執行結束
+	stopped := runs.Load()
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != stopped {
+		t.Errorf("Expected job to stop after cancel, runs went from %d to %d", stopped, runs.Load())
+	}
+}
+
+func TestStart_SurvivesErrorsAndPanics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var runs atomic.Int32
+
+	Start(ctx, zap.NewNop(), Job{
+		Name:     "flaky",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			switch runs.Add(1) {
+			case 1:
+				return errors.New("boom")
+			case 2:
+				panic("boom")
+			}
+			return nil
+		},
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected job to keep running after failures, got %d runs", runs.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -1,15 +1,19 @@
 package config
 
-import (
-	"os"
-
-	"gopkg.in/yaml.v3"
-)
-
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	JWT      JWTConfig      `yaml:"jwt"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	JWT       JWTConfig       `yaml:"jwt"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Mail      MailConfig      `yaml:"mail"`
+	OIDC      OIDCConfig      `yaml:"oidc"`
+	Storage   StorageConfig   `yaml:"storage"`
+	Avatar    AvatarConfig    `yaml:"avatar"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+
+	// GeneratedSecrets 非 release 模式下未設定、啟動時以隨機值產生的金鑰（YAML 路徑）
+	// 這些金鑰只在目前的程序有效，重新啟動後已簽發的 token 與連結都會失效，啟動時應記錄警告
+	GeneratedSecrets []string `yaml:"-"`
 }
 
 type ServerConfig struct {
@@ -27,21 +31,300 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret      string `yaml:"secret"`
-	ExpireHours int    `yaml:"expire_hours"`
+	Algorithm           string         `yaml:"algorithm"`     // HS256（預設）、RS256 或 EdDSA
+	Secret              string         `yaml:"secret"`        // 僅 HS256 使用
+	ActiveKeyID         string         `yaml:"active_key_id"` // RS256 / EdDSA 用來簽章的金鑰
+	Keys                []JWTKeyConfig `yaml:"keys"`          // RS256 / EdDSA 可接受的金鑰（輪替時保留舊金鑰）
+	AccessExpireMinutes int            `yaml:"access_expire_minutes"`
+	RefreshExpireHours  int            `yaml:"refresh_expire_hours"`
+	RevocationStore     string         `yaml:"revocation_store"` // memory 或 postgres
 }
 
-func Load(path string) (*Config, error) {
-	file, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
+type JWTKeyConfig struct {
+	ID             string `yaml:"id"`
+	PrivateKeyFile string `yaml:"private_key_file"`
+	PublicKeyFile  string `yaml:"public_key_file"` // 只有公鑰時為驗證專用
+}
 
-	var config Config
-	err = yaml.Unmarshal(file, &config)
-	if err != nil {
-		return nil, err
-	}
+type AuthConfig struct {
+	ActionTokenSecret             string `yaml:"action_token_secret"` // 簽署 Email 驗證等一次性 token
+	FrontendURL                   string `yaml:"frontend_url"`        // 郵件中連結的前端網址
+	RequireEmailVerification      bool   `yaml:"require_email_verification"`
+	EmailVerificationExpireHours  int    `yaml:"email_verification_expire_hours"`
+	PasswordResetExpireMinutes    int    `yaml:"password_reset_expire_minutes"`
+	SessionCleanupIntervalMinutes int    `yaml:"session_cleanup_interval_minutes"` // 清除過期 session 的間隔
+	ImpersonationExpireMinutes    int    `yaml:"impersonation_expire_minutes"`     // 管理員代登入 token 的效期
+	EmailChangeExpireHours        int    `yaml:"email_change_expire_hours"`        // 變更 Email 的確認與取消連結效期
+
+	Lockout         LockoutConfig         `yaml:"lockout"`
+	AccountDeletion AccountDeletionConfig `yaml:"account_deletion"`
+	UsernameChange  UsernameChangeConfig  `yaml:"username_change"`
+	MFA             MFAConfig             `yaml:"mfa"`
+	MagicLink       MagicLinkConfig       `yaml:"magic_link"`
+	PasswordPolicy  PasswordPolicyConfig  `yaml:"password_policy"`
+	PasswordHashing PasswordHashingConfig `yaml:"password_hashing"`
+}
+
+// LockoutConfig 連續登入失敗的帳號鎖定設定
+// 達到門檻後鎖定 BaseDurationSeconds，之後每多失敗一次鎖定時間加倍，最長 MaxDurationMinutes
+type LockoutConfig struct {
+	Threshold           int `yaml:"threshold"` // 0 表示停用
+	BaseDurationSeconds int `yaml:"base_duration_seconds"`
+	MaxDurationMinutes  int `yaml:"max_duration_minutes"`
+}
+
+// AccountDeletionConfig 帳號刪除設定
+// 刪除的帳號保留 RestoreWindowDays 天，期間內可由管理員復原，之後由背景工作永久刪除
+type AccountDeletionConfig struct {
+	RestoreWindowDays    int `yaml:"restore_window_days"`
+	PurgeIntervalMinutes int `yaml:"purge_interval_minutes"`
+}
+
+// UsernameChangeConfig 變更 username 的限制
+// 舊 username 保留給原用戶 ReservationDays 天，避免被他人冒用；原用戶可在期間內改回
+type UsernameChangeConfig struct {
+	CooldownDays    int `yaml:"cooldown_days"` // 兩次變更之間需間隔的天數，0 表示不限制
+	ReservationDays int `yaml:"reservation_days"`
+}
+
+// PasswordPolicyConfig 註冊、修改及重設密碼時套用的密碼規則
+type PasswordPolicyConfig struct {
+	MinLength             int    `yaml:"min_length"`
+	MaxLength             int    `yaml:"max_length"` // 位元組，0 表示使用雜湊演算法的上限（bcrypt 72、argon2id 256）
+	RequireUppercase      bool   `yaml:"require_uppercase"`
+	RequireLowercase      bool   `yaml:"require_lowercase"`
+	RequireDigit          bool   `yaml:"require_digit"`
+	RequireSymbol         bool   `yaml:"require_symbol"`
+	DisallowUserInfo      bool   `yaml:"disallow_user_info"`      // 不可包含 username 或 Email 帳號
+	HistorySize           int    `yaml:"history_size"`            // 不可重複使用最近 N 個密碼（0 表示停用）
+	BreachedPasswordsFile string `yaml:"breached_passwords_file"` // 依雜湊排序的 SHA-1 外洩密碼清單，空白表示不檢查
+}
+
+// PasswordHashingConfig 密碼雜湊演算法
+// 以其他演算法或舊參數產生的既有雜湊仍可登入，並在登入成功時換成此設定
+type PasswordHashingConfig struct {
+	Algorithm  string         `yaml:"algorithm"` // bcrypt（預設）或 argon2id
+	BcryptCost int            `yaml:"bcrypt_cost"`
+	Argon2id   Argon2idConfig `yaml:"argon2id"`
+}
+
+type Argon2idConfig struct {
+	MemoryKiB   uint32 `yaml:"memory_kib"`
+	Iterations  uint32 `yaml:"iterations"`
+	Parallelism uint8  `yaml:"parallelism"`
+}
+
+type MailConfig struct {
+	Driver string     `yaml:"driver"` // log 或 smtp
+	From   string     `yaml:"from"`
+	SMTP   SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
 
-	return &config, nil
+type MFAConfig struct {
+	Issuer                    string `yaml:"issuer"` // 顯示在驗證器 App 中的服務名稱
+	PendingTokenExpireMinutes int    `yaml:"pending_token_expire_minutes"`
+}
+
+// MagicLinkConfig 免密碼登入連結設定
+// 寄送次數以 Email 為單位限制（另外仍受路由的 IP 限流），避免對同一個信箱大量寄信
+type MagicLinkConfig struct {
+	ExpireMinutes         int `yaml:"expire_minutes"`
+	PerEmailLimit         int `yaml:"per_email_limit"` // 每個 Email 在 PerEmailPeriodMinutes 內最多寄送的次數
+	PerEmailPeriodMinutes int `yaml:"per_email_period_minutes"`
+}
+
+// StorageConfig 上傳檔案的儲存設定
+type StorageConfig struct {
+	Driver                 string             `yaml:"driver"` // local 或 s3
+	SignedURLExpireMinutes int                `yaml:"signed_url_expire_minutes"`
+	Local                  LocalStorageConfig `yaml:"local"`
+	S3                     S3StorageConfig    `yaml:"s3"`
+}
+
+type LocalStorageConfig struct {
+	Root          string `yaml:"root"`
+	BaseURL       string `yaml:"base_url"` // 對外的 /media 網址，用於產生下載連結
+	SigningSecret string `yaml:"signing_secret"`
+}
+
+// S3StorageConfig S3 相容服務（AWS S3、MinIO 等）
+type S3StorageConfig struct {
+	Endpoint  string `yaml:"endpoint"` // 不含 scheme，例如 localhost:9000
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	UseSSL    bool   `yaml:"use_ssl"`
+}
+
+// AvatarConfig 頭像上傳設定
+type AvatarConfig struct {
+	MaxUploadKB int   `yaml:"max_upload_kb"`
+	Sizes       []int `yaml:"sizes"` // 產生的正方形縮圖邊長（像素）
+}
+
+// RateLimitConfig API 限流設定
+// 使用 redis 時多個實例共用計數（也適用其他相容 Redis 協定的服務）；memory 僅適用單一實例
+type RateLimitConfig struct {
+	Store     string                 `yaml:"store"`      // memory 或 redis
+	KeyPrefix string                 `yaml:"key_prefix"` // 計數 key 的前綴，多個服務共用 Redis 時避免衝突
+	Redis     RedisConfig            `yaml:"redis"`
+	Default   RateLimitRuleConfig    `yaml:"default"` // /api/v1 下同一用戶的所有請求共用
+	Strict    RateLimitRuleConfig    `yaml:"strict"`  // 登入、註冊等路由，每個路由各自計數
+	Routes    []RouteRateLimitConfig `yaml:"routes"`  // 個別路由的限制，取代一般限流
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
+// RateLimitRuleConfig 每 PeriodSeconds 秒平均 Limit 次請求
+type RateLimitRuleConfig struct {
+	Algorithm     string `yaml:"algorithm"` // sliding_window（預設）、sliding_log 或 token_bucket
+	Limit         int    `yaml:"limit"`
+	PeriodSeconds int    `yaml:"period_seconds"`
+	Burst         int    `yaml:"burst"` // 僅 token_bucket 使用：閒置後可連續發出的次數，0 表示等於 Limit
+}
+
+type RouteRateLimitConfig struct {
+	Route               string `yaml:"route"` // 方法與完整路徑，例如 POST /api/v1/auth/login
+	RateLimitRuleConfig `yaml:",inline"`
+}
+
+// OIDCConfig 外部 OpenID Connect 登入設定
+type OIDCConfig struct {
+	StateExpireMinutes int                  `yaml:"state_expire_minutes"` // 導向提供者到回呼之間的有效時間
+	Providers          []OIDCProviderConfig `yaml:"providers"`
+}
+
+type OIDCProviderConfig struct {
+	Name         string   `yaml:"name"`       // 出現在 /auth/oidc/:provider 路徑中
+	IssuerURL    string   `yaml:"issuer_url"` // 由 {issuer_url}/.well-known/openid-configuration 取得端點
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"` // 必須指向 /api/v1/auth/oidc/{name}/callback
+	Scopes       []string `yaml:"scopes"`       // 未設定時為 openid、email、profile
+}
+
+// Defaults 預設設定，YAML、環境變數與參數未設定的欄位使用這裡的值
+// 密碼與簽章金鑰沒有預設值，需由設定檔或環境變數提供
+func Defaults() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port: "8080",
+			Mode: "debug",
+		},
+		Database: DatabaseConfig{
+			Host:    "localhost",
+			Port:    "5432",
+			User:    "postgres",
+			DBName:  "golang_framework",
+			SSLMode: "disable",
+		},
+		JWT: JWTConfig{
+			Algorithm:           "HS256",
+			AccessExpireMinutes: 15,
+			RefreshExpireHours:  168,
+			RevocationStore:     "postgres",
+		},
+		Auth: AuthConfig{
+			FrontendURL:                   "http://localhost:3000",
+			EmailVerificationExpireHours:  24,
+			PasswordResetExpireMinutes:    30,
+			SessionCleanupIntervalMinutes: 60,
+			ImpersonationExpireMinutes:    15,
+			EmailChangeExpireHours:        24,
+			Lockout: LockoutConfig{
+				Threshold:           5,
+				BaseDurationSeconds: 30,
+				MaxDurationMinutes:  60,
+			},
+			AccountDeletion: AccountDeletionConfig{
+				RestoreWindowDays:    30,
+				PurgeIntervalMinutes: 60,
+			},
+			UsernameChange: UsernameChangeConfig{
+				CooldownDays:    30,
+				ReservationDays: 90,
+			},
+			MFA: MFAConfig{
+				Issuer:                    "GolangFramework",
+				PendingTokenExpireMinutes: 5,
+			},
+			MagicLink: MagicLinkConfig{
+				ExpireMinutes:         15,
+				PerEmailLimit:         3,
+				PerEmailPeriodMinutes: 15,
+			},
+			PasswordPolicy: PasswordPolicyConfig{
+				MinLength:        8,
+				RequireLowercase: true,
+				RequireDigit:     true,
+				DisallowUserInfo: true,
+				HistorySize:      5,
+			},
+			PasswordHashing: PasswordHashingConfig{
+				Algorithm:  "bcrypt",
+				BcryptCost: 10,
+				Argon2id: Argon2idConfig{
+					MemoryKiB:   19456,
+					Iterations:  2,
+					Parallelism: 1,
+				},
+			},
+		},
+		Mail: MailConfig{
+			Driver: "log",
+			From:   "no-reply@example.com",
+			SMTP: SMTPConfig{
+				Host: "localhost",
+				Port: "1025",
+			},
+		},
+		OIDC: OIDCConfig{
+			StateExpireMinutes: 10,
+		},
+		Storage: StorageConfig{
+			Driver:                 "local",
+			SignedURLExpireMinutes: 60,
+			Local: LocalStorageConfig{
+				Root:    "./data/uploads",
+				BaseURL: "http://localhost:8080/media",
+			},
+			S3: S3StorageConfig{
+				Region: "us-east-1",
+				Bucket: "golang-framework",
+			},
+		},
+		Avatar: AvatarConfig{
+			MaxUploadKB: 5120,
+			Sizes:       []int{512, 128, 64},
+		},
+		RateLimit: RateLimitConfig{
+			Store:     "memory",
+			KeyPrefix: "ratelimit",
+			Redis: RedisConfig{
+				Addr: "localhost:6379",
+			},
+			Default: RateLimitRuleConfig{
+				Algorithm:     "sliding_window",
+				Limit:         100,
+				PeriodSeconds: 60,
+			},
+			Strict: RateLimitRuleConfig{
+				Algorithm:     "sliding_window",
+				Limit:         10,
+				PeriodSeconds: 60,
+			},
+		},
+	}
 }
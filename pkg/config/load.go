@@ -0,0 +1,255 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvPrefix 環境變數的前綴，例如 database.password 對應 APP_DATABASE_PASSWORD
+	EnvPrefix = "APP_"
+	// FileSuffix 加在環境變數後表示從檔案讀取，例如 APP_JWT_SECRET_FILE=/run/secrets/jwt_secret
+	FileSuffix = "_FILE"
+
+	// DefaultPath 未指定 -config 或 APP_CONFIG_FILE 時讀取的設定檔（不存在時略過）
+	DefaultPath = "config/config.yaml"
+)
+
+// ValidationError 設定有誤，列出所有問題讓啟動時一次修正
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load 依序套用預設值、YAML 設定檔、環境變數與命令列參數，後者覆蓋前者，最後驗證設定
+//
+// 設定檔路徑依序取自 -config 參數、APP_CONFIG_FILE，預設為 config/config.yaml
+// 每個設定都可以用環境變數（APP_ 加上大寫的 YAML 路徑，以 _ 連接）或同名的參數覆蓋：
+//
+//	APP_DATABASE_PASSWORD=secret      -database.password=secret
+//	APP_JWT_SECRET_FILE=/run/secrets/jwt_secret
+//	APP_AVATAR_SIZES=512,128          -avatar.sizes=512,128
+//
+// 清單只支援數字與字串（以逗號分隔）；OIDC 提供者等結構清單只能在設定檔中設定
+// release 以外的模式未設定簽章金鑰時以隨機值代替，並列在 GeneratedSecrets
+// 發生錯誤時回傳 *ValidationError 列出所有問題；args 含 -h 時回傳 flag.ErrHelp
+func Load(args []string) (*Config, error) {
+	return load(args, os.LookupEnv)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Defaults()
+	fields := leafFields(reflect.ValueOf(cfg).Elem(), "")
+
+	// 先解析參數取得設定檔路徑，參數的值最後才套用
+	flags := flag.NewFlagSet("api", flag.ContinueOnError)
+	path := flags.String("config", "", "設定檔路徑（也可用 "+EnvPrefix+"CONFIG_FILE 設定，預設 "+DefaultPath+"）")
+	overrides := make(map[string]string)
+	for _, f := range fields {
+		key := f.key
+		flags.Func(key, "覆蓋 "+key+"（環境變數 "+envName(key)+"）", func(value string) error {
+			overrides[key] = value
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var problems []string
+
+	// YAML 設定檔，未指定路徑且預設檔不存在時只使用預設值與環境變數
+	explicit := *path != ""
+	if value, ok := lookupEnv(EnvPrefix + "CONFIG_FILE"); !explicit && ok && value != "" {
+		*path, explicit = value, true
+	}
+	if !explicit {
+		*path = DefaultPath
+	}
+	file, err := os.ReadFile(*path)
+	switch {
+	case err == nil:
+		problems = append(problems, decodeYAML(file, cfg)...)
+	case !explicit && errors.Is(err, fs.ErrNotExist):
+	default:
+		return nil, err
+	}
+
+	// 環境變數，*_FILE 從檔案讀取（例如 Docker / Kubernetes secrets）
+	for _, f := range fields {
+		name := envName(f.key)
+		value, ok := lookupEnv(name)
+		if filePath, fromFile := lookupEnv(name + FileSuffix); fromFile {
+			if ok {
+				problems = append(problems, fmt.Sprintf("%s: both %s and %s are set", f.key, name, name+FileSuffix))
+				continue
+			}
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", f.key, err))
+				continue
+			}
+			value, ok = strings.TrimRight(string(content), "\r\n"), true
+		}
+		if !ok {
+			continue
+		}
+		if err := setValue(f.value, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s: %v", f.key, name, err))
+		}
+	}
+
+	// 命令列參數
+	for _, f := range fields {
+		if value, ok := overrides[f.key]; ok {
+			if err := setValue(f.value, value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: -%s: %v", f.key, f.key, err))
+			}
+		}
+	}
+
+	// 本機開發未設定金鑰時以隨機值代替，release 模式必須明確設定
+	if cfg.Server.Mode != "release" {
+		if err := cfg.generateSecrets(); err != nil {
+			return nil, err
+		}
+	}
+
+	problems = append(problems, cfg.problems()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// decodeYAML 型別不符的欄位列為問題，其餘欄位照常套用
+func decodeYAML(file []byte, cfg *Config) []string {
+	err := yaml.Unmarshal(file, cfg)
+	var typeErr *yaml.TypeError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &typeErr):
+		return typeErr.Errors
+	default:
+		return []string{err.Error()}
+	}
+}
+
+// field 可由環境變數與參數設定的欄位，key 為 YAML 路徑（例如 rate_limit.redis.addr）
+type field struct {
+	key   string
+	value reflect.Value
+}
+
+// leafFields 依 yaml tag 列出所有純量與純量清單欄位
+func leafFields(v reflect.Value, prefix string) []field {
+	var fields []field
+	for i := 0; i < v.NumField(); i++ {
+		name, opts, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		value := v.Field(i)
+		if name == "-" {
+			continue
+		}
+		if opts == "inline" {
+			fields = append(fields, leafFields(value, prefix)...)
+			continue
+		}
+		key := prefix + name
+		switch {
+		case value.Kind() == reflect.Struct:
+			fields = append(fields, leafFields(value, key+".")...)
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Struct:
+			// 結構清單只能在設定檔中設定
+		default:
+			fields = append(fields, field{key: key, value: value})
+		}
+	}
+	return fields
+}
+
+// generateSecrets 為未設定的簽章金鑰產生隨機值，並記錄在 GeneratedSecrets
+func (c *Config) generateSecrets() error {
+	secrets := []struct {
+		key   string
+		value *string
+		used  bool
+	}{
+		{key: "jwt.secret", value: &c.JWT.Secret, used: c.JWT.Algorithm == "HS256"},
+		{key: "auth.action_token_secret", value: &c.Auth.ActionTokenSecret, used: true},
+		{key: "storage.local.signing_secret", value: &c.Storage.Local.SigningSecret, used: c.Storage.Driver == "local"},
+	}
+	for _, secret := range secrets {
+		if !secret.used || *secret.value != "" {
+			continue
+		}
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return fmt.Errorf("generate %s: %w", secret.key, err)
+		}
+		*secret.value = hex.EncodeToString(random)
+		c.GeneratedSecrets = append(c.GeneratedSecrets, secret.key)
+	}
+	return nil
+}
+
+func envName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// setValue 將字串轉成欄位的型別，清單以逗號分隔
+func setValue(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Slice {
+		var parts []string
+		if strings.TrimSpace(raw) != "" {
+			parts = strings.Split(raw, ",")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		v.SetUint(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// mapEnv 以 map 模擬環境變數
+func mapEnv(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return path
+}
+
+// problemsOf 取出 ValidationError 中的問題
+func problemsOf(t *testing.T, err error) []string {
+	t.Helper()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+	return validationErr.Problems
+}
+
+func TestLoad_Layers(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+database:
+  host: db.internal
+avatar:
+  sizes: [256]
+`)
+	secret := writeFile(t, "jwt_secret", "from-file\n")
+
+	cfg, err := load([]string{"-config", path, "-server.port=9200"}, mapEnv(map[string]string{
+		"APP_SERVER_PORT":           "9100",
+		"APP_DATABASE_USER":         "app",
+		"APP_RATE_LIMIT_REDIS_ADDR": "redis:6379",
+		"APP_AVATAR_SIZES":          "128, 32",
+		"APP_JWT_SECRET_FILE":       secret,
+	}))
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		got, want any
+	}{
+		{name: "FlagOverridesEnv", got: cfg.Server.Port, want: "9200"},
+		{name: "YAMLOverridesDefault", got: cfg.Database.Host, want: "db.internal"},
+		{name: "EnvOverridesDefault", got: cfg.Database.User, want: "app"},
+		{name: "NestedEnv", got: cfg.RateLimit.Redis.Addr, want: "redis:6379"},
+		{name: "SecretFromFile", got: cfg.JWT.Secret, want: "from-file"},
+		{name: "Default", got: cfg.JWT.AccessExpireMinutes, want: 15},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, tc.got)
+		}
+	}
+	if !slices.Equal(cfg.Avatar.Sizes, []int{128, 32}) {
+		t.Errorf("Expected avatar sizes [128 32], got %v", cfg.Avatar.Sizes)
+	}
+}
+
+func TestLoad_Problems(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 80a
+  mode: release
+auth:
+  lockout:
+    threshold: many
+  magic_link:
+    per_email_limit: 0
+oidc:
+  state_expire_minutes: -1
+storage:
+  signed_url_expire_minutes: 0
+avatar:
+  sizes: [128, 0]
+`)
+	secret := writeFile(t, "secret", "x")
+
+	_, err := load([]string{"-config", path}, mapEnv(map[string]string{
+		"APP_AVATAR_MAX_UPLOAD_KB":               "big",
+		"APP_AUTH_LOCKOUT_BASE_DURATION_SECONDS": "0",
+		"APP_AUTH_ACTION_TOKEN_SECRET":           "x",
+		"APP_AUTH_ACTION_TOKEN_SECRET_FILE":      secret,
+		"APP_STORAGE_LOCAL_SIGNING_SECRET_FILE":  filepath.Join(t.TempDir(), "missing"),
+	}))
+	problems := problemsOf(t, err)
+
+	// 所有問題一次列出
+	for _, want := range []string{
+		"server.port: must be a number between 1 and 65535",
+		"jwt.secret: is required (set APP_JWT_SECRET or APP_JWT_SECRET_FILE)",
+		"auth.lockout.base_duration_seconds: must be greater than 0, got 0",
+		"auth.magic_link.per_email_limit: must be greater than 0, got 0",
+		"oidc.state_expire_minutes: must be greater than 0, got -1",
+		"storage.signed_url_expire_minutes: must be greater than 0, got 0",
+		"avatar.sizes[1]: must be greater than 0, got 0",
+		"avatar.max_upload_kb: APP_AVATAR_MAX_UPLOAD_KB: invalid integer",
+		"auth.action_token_secret: both APP_AUTH_ACTION_TOKEN_SECRET and APP_AUTH_ACTION_TOKEN_SECRET_FILE are set",
+		"storage.local.signing_secret:",
+		"`many` into int",
+	} {
+		if !slices.ContainsFunc(problems, func(p string) bool { return strings.Contains(p, want) }) {
+			t.Errorf("Expected a problem containing %q, got:\n%s", want, err)
+		}
+	}
+}
+
+func TestLoad_ConfigFile(t *testing.T) {
+	t.Run("DefaultPathMissing", func(t *testing.T) {
+		// 測試在 pkg/config 執行，預設的 config/config.yaml 不存在時只使用預設值
+		cfg, err := load(nil, mapEnv(nil))
+		if err != nil {
+			t.Fatalf("load failed: %v", err)
+		}
+		if cfg.Server.Port != "8080" {
+			t.Errorf("Expected default port 8080, got %q", cfg.Server.Port)
+		}
+	})
+
+	t.Run("ExplicitPathMissing", func(t *testing.T) {
+		env := mapEnv(map[string]string{"APP_CONFIG_FILE": filepath.Join(t.TempDir(), "missing.yaml")})
+		if _, err := load(nil, env); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected not exist error, got %v", err)
+		}
+	})
+
+	// 專案內的設定檔不含金鑰：debug 模式自動產生，release 模式需由環境變數提供
+	t.Run("RepositoryFiles", func(t *testing.T) {
+		cfg, err := load([]string{"-config", "../../config/config.yaml"}, mapEnv(nil))
+		if err != nil {
+			t.Fatalf("config.yaml: %v", err)
+		}
+		if len(cfg.GeneratedSecrets) != 3 {
+			t.Errorf("Expected 3 generated secrets for config.yaml, got %v", cfg.GeneratedSecrets)
+		}
+
+		docker := []string{"-config", "../../config/config.docker.yaml"}
+		_, err = load(docker, mapEnv(nil))
+		if problems := problemsOf(t, err); len(problems) != 3 {
+			t.Errorf("Expected 3 missing secrets, got:\n%s", err)
+		}
+		if _, err := load(docker, mapEnv(map[string]string{
+			"APP_JWT_SECRET":                   "a",
+			"APP_AUTH_ACTION_TOKEN_SECRET":     "b",
+			"APP_STORAGE_LOCAL_SIGNING_SECRET": "c",
+		})); err != nil {
+			t.Errorf("config.docker.yaml: %v", err)
+		}
+	})
+}
+
+func TestLoad_GeneratedSecrets(t *testing.T) {
+	env := mapEnv(map[string]string{"APP_JWT_SECRET": "from-env"})
+
+	first, err := load(nil, env)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	second, err := load(nil, env)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	// 已設定的金鑰不會被取代
+	if first.JWT.Secret != "from-env" {
+		t.Errorf("Expected jwt.secret from env, got %q", first.JWT.Secret)
+	}
+	want := []string{"auth.action_token_secret", "storage.local.signing_secret"}
+	if !slices.Equal(first.GeneratedSecrets, want) {
+		t.Errorf("Expected generated secrets %v, got %v", want, first.GeneratedSecrets)
+	}
+	// 每個程序產生不同的金鑰
+	if first.Auth.ActionTokenSecret == "" || first.Auth.ActionTokenSecret == second.Auth.ActionTokenSecret {
+		t.Errorf("Expected distinct random secrets, got %q and %q", first.Auth.ActionTokenSecret, second.Auth.ActionTokenSecret)
+	}
+
+	// release 模式不產生
+	if _, err := load([]string{"-server.mode=release"}, env); err == nil {
+		t.Error("Expected missing secrets to fail in release mode")
+	}
+}
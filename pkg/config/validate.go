@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// Validate 檢查設定，有問題時回傳 *ValidationError 列出所有問題
+func (c *Config) Validate() error {
+	if problems := c.problems(); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// problems 以 YAML 路徑描述每個問題
+// 簽章金鑰在所有模式都必須設定；Load 在 release 以外的模式會先以隨機值補上，方便本機開發
+func (c *Config) problems() []string {
+	var problems []string
+	add := func(key, format string, args ...any) {
+		problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+	}
+	oneOf := func(key, value string, allowed ...string) {
+		if !slices.Contains(allowed, value) {
+			add(key, "must be one of %v, got %q", allowed, value)
+		}
+	}
+	port := func(key, value string) {
+		if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+			add(key, "must be a number between 1 and 65535, got %q", value)
+		}
+	}
+	required := func(key, value string) {
+		if value == "" {
+			add(key, "is required")
+		}
+	}
+	positive := func(key string, value int) {
+		if value <= 0 {
+			add(key, "must be greater than 0, got %d", value)
+		}
+	}
+
+	secret := func(key, value string) {
+		if value == "" {
+			add(key, "is required (set %s or %s)", envName(key), envName(key)+FileSuffix)
+		}
+	}
+
+	port("server.port", c.Server.Port)
+	oneOf("server.mode", c.Server.Mode, "debug", "release", "test")
+
+	required("database.host", c.Database.Host)
+	port("database.port", c.Database.Port)
+	required("database.user", c.Database.User)
+	required("database.dbname", c.Database.DBName)
+
+	oneOf("jwt.algorithm", c.JWT.Algorithm, "HS256", "RS256", "EdDSA")
+	if c.JWT.Algorithm == "HS256" {
+		secret("jwt.secret", c.JWT.Secret)
+	} else if c.JWT.Algorithm == "RS256" || c.JWT.Algorithm == "EdDSA" {
+		required("jwt.active_key_id", c.JWT.ActiveKeyID)
+		if len(c.JWT.Keys) == 0 {
+			add("jwt.keys", "at least one key is required for %s", c.JWT.Algorithm)
+		}
+	}
+	positive("jwt.access_expire_minutes", c.JWT.AccessExpireMinutes)
+	positive("jwt.refresh_expire_hours", c.JWT.RefreshExpireHours)
+	oneOf("jwt.revocation_store", c.JWT.RevocationStore, "memory", "postgres")
+
+	secret("auth.action_token_secret", c.Auth.ActionTokenSecret)
+	oneOf("auth.password_hashing.algorithm", c.Auth.PasswordHashing.Algorithm, "bcrypt", "argon2id")
+
+	// 各種效期與間隔為 0 或負數時 token 會立即失效，背景工作的 ticker 也會 panic
+	positive("auth.email_verification_expire_hours", c.Auth.EmailVerificationExpireHours)
+	positive("auth.email_change_expire_hours", c.Auth.EmailChangeExpireHours)
+	positive("auth.password_reset_expire_minutes", c.Auth.PasswordResetExpireMinutes)
+	positive("auth.impersonation_expire_minutes", c.Auth.ImpersonationExpireMinutes)
+	positive("auth.session_cleanup_interval_minutes", c.Auth.SessionCleanupIntervalMinutes)
+	positive("auth.lockout.base_duration_seconds", c.Auth.Lockout.BaseDurationSeconds)
+	positive("auth.lockout.max_duration_minutes", c.Auth.Lockout.MaxDurationMinutes)
+	positive("auth.account_deletion.restore_window_days", c.Auth.AccountDeletion.RestoreWindowDays)
+	positive("auth.account_deletion.purge_interval_minutes", c.Auth.AccountDeletion.PurgeIntervalMinutes)
+	positive("auth.mfa.pending_token_expire_minutes", c.Auth.MFA.PendingTokenExpireMinutes)
+	positive("auth.magic_link.expire_minutes", c.Auth.MagicLink.ExpireMinutes)
+	positive("auth.magic_link.per_email_limit", c.Auth.MagicLink.PerEmailLimit)
+	positive("auth.magic_link.per_email_period_minutes", c.Auth.MagicLink.PerEmailPeriodMinutes)
+
+	oneOf("mail.driver", c.Mail.Driver, "log", "smtp")
+	if c.Mail.Driver == "smtp" {
+		required("mail.smtp.host", c.Mail.SMTP.Host)
+		port("mail.smtp.port", c.Mail.SMTP.Port)
+	}
+
+	positive("oidc.state_expire_minutes", c.OIDC.StateExpireMinutes)
+	for i, provider := range c.OIDC.Providers {
+		key := fmt.Sprintf("oidc.providers[%d]", i)
+		required(key+".name", provider.Name)
+		required(key+".issuer_url", provider.IssuerURL)
+		required(key+".client_id", provider.ClientID)
+	}
+
+	oneOf("storage.driver", c.Storage.Driver, "local", "s3")
+	positive("storage.signed_url_expire_minutes", c.Storage.SignedURLExpireMinutes)
+	switch c.Storage.Driver {
+	case "local":
+		required("storage.local.root", c.Storage.Local.Root)
+		secret("storage.local.signing_secret", c.Storage.Local.SigningSecret)
+	case "s3":
+		required("storage.s3.endpoint", c.Storage.S3.Endpoint)
+		required("storage.s3.bucket", c.Storage.S3.Bucket)
+	}
+
+	positive("avatar.max_upload_kb", c.Avatar.MaxUploadKB)
+	if len(c.Avatar.Sizes) == 0 {
+		add("avatar.sizes", "at least one size is required")
+	}
+	for i, size := range c.Avatar.Sizes {
+		positive(fmt.Sprintf("avatar.sizes[%d]", i), size)
+	}
+
+	oneOf("rate_limit.store", c.RateLimit.Store, "memory", "redis")
+	if c.RateLimit.Store == "redis" {
+		required("rate_limit.redis.addr", c.RateLimit.Redis.Addr)
+	}
+	rule := func(key string, rule RateLimitRuleConfig) {
+		oneOf(key+".algorithm", rule.Algorithm, "", "sliding_window", "sliding_log", "token_bucket")
+		positive(key+".limit", rule.Limit)
+		positive(key+".period_seconds", rule.PeriodSeconds)
+	}
+	rule("rate_limit.default", c.RateLimit.Default)
+	rule("rate_limit.strict", c.RateLimit.Strict)
+	for i, route := range c.RateLimit.Routes {
+		key := fmt.Sprintf("rate_limit.routes[%d]", i)
+		required(key+".route", route.Route)
+		rule(key, route.RateLimitRuleConfig)
+	}
+
+	return problems
+}
@@ -13,15 +13,16 @@ const txKey contextKey = "db_transaction"
 // WithTransaction 執行一個事務操作
 // 如果 fn 返回 error,會自動 rollback
 // 如果 fn 成功執行完畢,會自動 commit
-func WithTransaction(db *sql.DB, fn func(context.Context) error) error {
+// 事務使用呼叫端的 ctx:請求超時或取消時執行中的查詢會被取消(lib/pq 會通知 Postgres 中止查詢),事務隨之 rollback
+func WithTransaction(ctx context.Context, db *sql.DB, fn func(context.Context) error) (err error) {
 	// 1. 開始事務
-	tx, err := db.BeginTx(context.Background(), nil)
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
 	// 2. 將 transaction 放入 context
-	ctx := context.WithValue(context.Background(), txKey, tx)
+	txCtx := context.WithValue(ctx, txKey, tx)
 
 	// 3. 使用 defer 確保事務一定會被處理(commit 或 rollback)
 	defer func() {
@@ -33,13 +34,13 @@ func WithTransaction(db *sql.DB, fn func(context.Context) error) error {
 			// 如果有錯誤,rollback
 			tx.Rollback()
 		} else {
-			// 成功則 commit
+			// 成功則 commit(ctx 已取消時 commit 會失敗並回傳錯誤)
 			err = tx.Commit()
 		}
 	}()
 
 	// 4. 執行業務邏輯
-	err = fn(ctx)
+	err = fn(txCtx)
 	return err
 }
 
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeConnector 記錄事務操作的 driver；查詢 "SLOW" 會等到 ctx 結束，模擬 lib/pq 取消執行中的查詢
+type fakeConnector struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (f *fakeConnector) record(op string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.log = append(f.log, op)
+}
+
+func (f *fakeConnector) ops() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.log)
+}
+
+func (f *fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{f}, nil }
+func (f *fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ f *fakeConnector }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.f.record("begin")
+	return &fakeTx{c.f}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if query == "SLOW" {
+		<-ctx.Done()
+		c.f.record("cancel")
+		return nil, ctx.Err()
+	}
+	c.f.record("exec")
+	return driver.RowsAffected(1), nil
+}
+
+type fakeTx struct{ f *fakeConnector }
+
+func (t *fakeTx) Commit() error   { t.f.record("commit"); return nil }
+func (t *fakeTx) Rollback() error { t.f.record("rollback"); return nil }
+
+func newFakeDB(t *testing.T) (*sql.DB, *fakeConnector) {
+	t.Helper()
+
+	connector := &fakeConnector{}
+	db := sql.OpenDB(connector)
+	t.Cleanup(func() { db.Close() })
+	return db, connector
+}
+
+// exec 以 context 中的事務執行查詢
+func exec(ctx context.Context, query string) error {
+	tx, ok := GetTx(ctx)
+	if !ok {
+		return errors.New("no transaction in context")
+	}
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func TestWithTransaction(t *testing.T) {
+	t.Run("Commit", func(t *testing.T) {
+		db, connector := newFakeDB(t)
+
+		err := WithTransaction(context.Background(), db, func(ctx context.Context) error {
+			return exec(ctx, "INSERT")
+		})
+		if err != nil {
+			t.Fatalf("WithTransaction failed: %v", err)
+		}
+		if ops := connector.ops(); !slices.Equal(ops, []string{"begin", "exec", "commit"}) {
+			t.Errorf("Unexpected operations %v", ops)
+		}
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		db, connector := newFakeDB(t)
+		errFailed := errors.New("failed")
+
+		err := WithTransaction(context.Background(), db, func(ctx context.Context) error {
+			if err := exec(ctx, "INSERT"); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		if !errors.Is(err, errFailed) {
+			t.Fatalf("Expected errFailed, got %v", err)
+		}
+		if ops := connector.ops(); !slices.Equal(ops, []string{"begin", "exec", "rollback"}) {
+			t.Errorf("Unexpected operations %v", ops)
+		}
+	})
+
+	t.Run("DeadlineCancelsQuery", func(t *testing.T) {
+		db, connector := newFakeDB(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := WithTransaction(ctx, db, func(ctx context.Context) error {
+			return exec(ctx, "SLOW")
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected deadline exceeded, got %v", err)
+		}
+		// ctx 取消後 database/sql 會在背景 rollback，這裡只確認查詢被取消且沒有 commit
+		ops := connector.ops()
+		if !slices.Contains(ops, "cancel") || slices.Contains(ops, "commit") {
+			t.Errorf("Expected query to be cancelled without commit, got %v", ops)
+		}
+	})
+
+	t.Run("CancelledBeforeCommit", func(t *testing.T) {
+		db, connector := newFakeDB(t)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// fn 成功但 ctx 已取消時不可回報成功
+		err := WithTransaction(ctx, db, func(ctx context.Context) error {
+			if err := exec(ctx, "INSERT"); err != nil {
+				return err
+			}
+			cancel()
+			return nil
+		})
+		if err == nil {
+			t.Fatal("Expected error when context is cancelled before commit")
+		}
+		if slices.Contains(connector.ops(), "commit") {
+			t.Errorf("Expected no commit, got %v", connector.ops())
+		}
+	})
+}
@@ -11,6 +11,55 @@ var (
 	ErrUnauthorized       = errors.New("unauthorized")
 	ErrForbidden          = errors.New("forbidden")
 	ErrInternalServer     = errors.New("internal server error")
+
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+
+	ErrRoleNotFound = errors.New("role not found")
+
+	ErrInvalidVerificationToken = errors.New("invalid verification token")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+	ErrEmailNotVerified         = errors.New("email not verified")
+
+	ErrInvalidResetToken = errors.New("invalid password reset token")
+
+	ErrAccountLocked = errors.New("account locked")
+
+	ErrInvalidMFAToken   = errors.New("invalid mfa token")
+	ErrInvalidMFACode    = errors.New("invalid mfa code")
+	ErrMFAAlreadyEnabled = errors.New("mfa already enabled")
+	ErrMFANotEnabled     = errors.New("mfa not enabled")
+	ErrMFASetupRequired  = errors.New("mfa setup required")
+
+	ErrOIDCProviderNotFound = errors.New("oidc provider not found")
+	ErrInvalidOIDCState     = errors.New("invalid oidc state")
+	ErrOIDCLoginFailed      = errors.New("oidc login failed")
+	ErrOIDCEmailNotVerified = errors.New("oidc email not verified")
+	ErrOIDCAccountConflict  = errors.New("oidc account conflict")
+
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrInvalidAPIKeyScope = errors.New("invalid api key scope")
+	ErrAPIKeyNotAllowed   = errors.New("api key not allowed")
+
+	ErrSessionNotFound = errors.New("session not found")
+
+	ErrPasswordPolicy = errors.New("password policy violation")
+
+	ErrCannotImpersonate       = errors.New("user cannot be impersonated")
+	ErrImpersonationNotAllowed = errors.New("impersonation not allowed")
+
+	ErrInvalidMagicLink = errors.New("invalid magic link")
+	ErrTooManyRequests  = errors.New("too many requests")
+
+	ErrRestoreWindowExpired = errors.New("restore window expired")
+
+	ErrInvalidProfile = errors.New("invalid profile")
+	ErrInvalidAvatar  = errors.New("invalid avatar")
+	ErrAvatarTooLarge = errors.New("avatar too large")
+
+	ErrEmailChangeRequiresConfirmation = errors.New("email change requires confirmation")
+	ErrInvalidEmailChangeToken         = errors.New("invalid email change token")
+	ErrUsernameChangeCooldown          = errors.New("username change cooldown")
 )
 
 // 錯誤代碼（用於 API 響應）
@@ -23,6 +72,56 @@ const (
 	CodeForbidden          = "FORBIDDEN"
 	CodeInternalServer     = "INTERNAL_SERVER_ERROR"
 	CodeValidationFailed   = "VALIDATION_FAILED"
+
+	CodeInvalidRefreshToken = "INVALID_REFRESH_TOKEN"
+	CodeRefreshTokenReused  = "REFRESH_TOKEN_REUSED"
+
+	CodeRoleNotFound = "ROLE_NOT_FOUND"
+
+	CodeInvalidVerificationToken = "INVALID_VERIFICATION_TOKEN"
+	CodeEmailAlreadyVerified     = "EMAIL_ALREADY_VERIFIED"
+	CodeEmailNotVerified         = "EMAIL_NOT_VERIFIED"
+
+	CodeInvalidResetToken = "INVALID_RESET_TOKEN"
+
+	CodeAccountLocked = "ACCOUNT_LOCKED"
+
+	CodeInvalidMFAToken   = "INVALID_MFA_TOKEN"
+	CodeInvalidMFACode    = "INVALID_MFA_CODE"
+	CodeMFAAlreadyEnabled = "MFA_ALREADY_ENABLED"
+	CodeMFANotEnabled     = "MFA_NOT_ENABLED"
+	CodeMFASetupRequired  = "MFA_SETUP_REQUIRED"
+
+	CodeOIDCProviderNotFound = "OIDC_PROVIDER_NOT_FOUND"
+	CodeInvalidOIDCState     = "INVALID_OIDC_STATE"
+	CodeOIDCLoginFailed      = "OIDC_LOGIN_FAILED"
+	CodeOIDCEmailNotVerified = "OIDC_EMAIL_NOT_VERIFIED"
+	CodeOIDCAccountConflict  = "OIDC_ACCOUNT_CONFLICT"
+
+	CodeAPIKeyNotFound     = "API_KEY_NOT_FOUND"
+	CodeInvalidAPIKeyScope = "INVALID_API_KEY_SCOPE"
+	CodeAPIKeyNotAllowed   = "API_KEY_NOT_ALLOWED"
+
+	CodeSessionNotFound = "SESSION_NOT_FOUND"
+
+	CodePasswordPolicy = "PASSWORD_POLICY_VIOLATION"
+
+	CodeCannotImpersonate       = "CANNOT_IMPERSONATE"
+	CodeImpersonationNotAllowed = "IMPERSONATION_NOT_ALLOWED"
+
+	CodeInvalidMagicLink = "INVALID_MAGIC_LINK"
+	CodeTooManyRequests  = "RATE_LIMIT_EXCEEDED"
+	CodeRequestTimeout   = "REQUEST_TIMEOUT"
+
+	CodeRestoreWindowExpired = "RESTORE_WINDOW_EXPIRED"
+
+	CodeInvalidProfile = "INVALID_PROFILE"
+	CodeInvalidAvatar  = "INVALID_AVATAR"
+	CodeAvatarTooLarge = "AVATAR_TOO_LARGE"
+
+	CodeEmailChangeRequiresConfirmation = "EMAIL_CHANGE_REQUIRES_CONFIRMATION"
+	CodeInvalidEmailChangeToken         = "INVALID_EMAIL_CHANGE_TOKEN"
+	CodeUsernameChangeCooldown          = "USERNAME_CHANGE_COOLDOWN"
 )
 
 // 錯誤訊息
@@ -35,4 +134,75 @@ const (
 	MsgForbidden          = "Access forbidden"
 	MsgInternalServer     = "Internal server error"
 	MsgValidationFailed   = "Validation failed"
+
+	MsgInvalidRefreshToken = "Invalid or expired refresh token"
+	MsgRefreshTokenReused  = "Refresh token has already been used, all sessions in this family have been revoked"
+
+	MsgRoleNotFound = "Role not found"
+
+	MsgInvalidVerificationToken = "Invalid or expired verification token"
+	MsgEmailAlreadyVerified     = "Email has already been verified"
+	MsgEmailNotVerified         = "Email address has not been verified"
+
+	MsgInvalidResetToken = "Invalid, expired or already used password reset token"
+
+	MsgAccountLocked = "Account is temporarily locked due to too many failed login attempts"
+
+	MsgInvalidMFAToken   = "Invalid or expired MFA token, please log in again"
+	MsgInvalidMFACode    = "Invalid authentication code"
+	MsgMFAAlreadyEnabled = "Two-factor authentication is already enabled"
+	MsgMFANotEnabled     = "Two-factor authentication is not enabled"
+	MsgMFASetupRequired  = "Two-factor authentication has not been set up"
+
+	MsgOIDCProviderNotFound = "Identity provider not found"
+	MsgInvalidOIDCState     = "Invalid or expired login state, please try again"
+	MsgOIDCLoginFailed      = "Failed to sign in with the identity provider"
+	MsgOIDCEmailNotVerified = "The identity provider did not return a verified email address"
+	MsgOIDCAccountConflict  = "An account with this email already exists, please sign in with your password first"
+
+	MsgAPIKeyNotFound     = "API key not found"
+	MsgInvalidAPIKeyScope = "API key scopes must be a subset of your permissions"
+	MsgAPIKeyNotAllowed   = "This operation is not available with an API key"
+
+	MsgSessionNotFound = "Session not found"
+
+	MsgPasswordPolicy = "Password does not meet the password policy"
+
+	MsgCannotImpersonate       = "This user cannot be impersonated"
+	MsgImpersonationNotAllowed = "This operation is not available while impersonating a user"
+
+	MsgInvalidMagicLink = "Invalid, expired or already used login link"
+	MsgTooManyRequests  = "Too many requests, please try again later"
+	MsgRequestTimeout   = "Request timeout"
+
+	MsgRestoreWindowExpired = "The restore window for this account has expired"
+
+	MsgInvalidProfile = "Profile contains invalid fields"
+	MsgInvalidAvatar  = "Avatar must be a JPEG, PNG, GIF or WebP image"
+	MsgAvatarTooLarge = "Avatar image is too large"
+
+	MsgEmailChangeRequiresConfirmation = "Email can only be changed via POST /users/profile/email, which requires confirmation from the new address"
+	MsgInvalidEmailChangeToken         = "Invalid, expired or already used email change link"
+	MsgUsernameChangeCooldown          = "Username was changed recently, please try again later"
 )
+
+// FieldError 單一欄位的驗證錯誤
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError 帶有欄位明細的錯誤，Err 為對應的錯誤定義（可用 errors.Is 判斷）
+type ValidationError struct {
+	Err    error
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
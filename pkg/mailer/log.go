@@ -0,0 +1,25 @@
+package mailer
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type logMailer struct {
+	logger *zap.Logger
+}
+
+// NewLogMailer 建立只把郵件內容寫入日誌的 Mailer（開發環境使用）
+func NewLogMailer(logger *zap.Logger) Mailer {
+	return &logMailer{logger: logger}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.Info("📧 Mail (log only)",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body),
+	)
+	return nil
+}
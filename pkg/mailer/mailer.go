@@ -0,0 +1,15 @@
+package mailer
+
+import "context"
+
+// Message 要寄出的郵件
+type Message struct {
+	To      string
+	Subject string
+	Body    string // 純文字內容
+}
+
+// Mailer 寄送郵件的介面
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
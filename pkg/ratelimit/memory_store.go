@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval 清除過期狀態的間隔
+const memorySweepInterval = time.Minute
+
+// MemoryStore 將狀態存放在記憶體，僅適用單一實例
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	nextSweep time.Time
+}
+
+type memoryEntry struct {
+	expiresAt time.Time
+	tat       time.Time   // token bucket
+	log       []time.Time // sliding log，依時間排序
+	count     int64       // sliding window 的單一時間窗
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*memoryEntry)}
+}
+
+func (s *MemoryStore) takeToken(ctx context.Context, key string, now time.Time, interval time.Duration, burst int64) (bool, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key, now)
+	tat := entry.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(interval)
+	if newTAT.Sub(now) > time.Duration(burst)*interval {
+		return false, tat, nil
+	}
+
+	entry.tat = newTAT
+	entry.expiresAt = newTAT
+	return true, newTAT, nil
+}
+
+func (s *MemoryStore) appendLog(ctx context.Context, key string, now time.Time, window time.Duration, limit int64) (bool, int64, time.Time, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key, now)
+	cutoff := now.Add(-window)
+	expired := 0
+	for expired < len(entry.log) && !entry.log[expired].After(cutoff) {
+		expired++
+	}
+	entry.log = entry.log[expired:]
+
+	allowed := int64(len(entry.log)) < limit
+	if allowed {
+		entry.log = append(entry.log, now)
+		entry.expiresAt = now.Add(window)
+	}
+
+	count := int64(len(entry.log))
+	if count == 0 {
+		return allowed, 0, time.Time{}, time.Time{}, nil
+	}
+	return allowed, count, entry.log[0], entry.log[count-1], nil
+}
+
+func (s *MemoryStore) incrementWindow(ctx context.Context, key string, windowStart time.Time, period time.Duration, prevWeight float64, limit int64) (bool, int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	window := windowStart.UnixMicro() / period.Microseconds()
+	prev := s.entry(key+":"+strconv.FormatInt(window-1, 10), windowStart).count
+	curr := s.entry(key+":"+strconv.FormatInt(window, 10), windowStart)
+
+	if float64(prev)*prevWeight+float64(curr.count)+1 > float64(limit) {
+		return false, prev, curr.count, nil
+	}
+
+	curr.count++
+	// 下一個時間窗仍會以此計數估算
+	curr.expiresAt = windowStart.Add(2 * period)
+	return true, prev, curr.count, nil
+}
+
+// entry 取得 key 的狀態，不存在或已過期時建立新的；順便定期清除過期的狀態
+func (s *MemoryStore) entry(key string, now time.Time) *memoryEntry {
+	if !now.Before(s.nextSweep) {
+		for k, e := range s.entries {
+			if !e.expiresAt.After(now) {
+				delete(s.entries, k)
+			}
+		}
+		s.nextSweep = now.Add(memorySweepInterval)
+	}
+
+	entry, ok := s.entries[key]
+	if !ok || !entry.expiresAt.After(now) {
+		entry = &memoryEntry{}
+		s.entries[key] = entry
+	}
+	return entry
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Limiter 判斷 key 的請求是否在限制內，允許時同時計入這次請求
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Result 一次判斷的結果
+type Result struct {
+	Allowed    bool
+	Limit      int64         // 可連續發出的最大請求數（token bucket 為 burst）
+	Remaining  int64         // 目前還能立即發出的請求數
+	RetryAfter time.Duration // 被拒絕時，需等待多久才能再發出一次請求
+	ResetAt    time.Time     // 回到完全未使用（Remaining 等於 Limit）的時間
+}
+
+// Rate 每 Period 平均 Limit 次請求
+type Rate struct {
+	Limit  int64
+	Period time.Duration
+}
+
+// Algorithm 限流演算法
+type Algorithm string
+
+const (
+	// AlgorithmTokenBucket 以固定速率補充 token，閒置時最多累積 burst 個，可表達「每秒 10 次，最多連續 50 次」
+	AlgorithmTokenBucket Algorithm = "token_bucket"
+	// AlgorithmSlidingWindow 以前一個時間窗的計數依重疊比例加權估算，只需兩個計數，不會在時間窗交界時放行兩倍請求
+	AlgorithmSlidingWindow Algorithm = "sliding_window"
+	// AlgorithmSlidingLog 記錄時間窗內每次請求的時間，最精確，但狀態大小與 Limit 成正比
+	AlgorithmSlidingLog Algorithm = "sliding_log"
+)
+
+// Policy 限流規則
+type Policy struct {
+	Algorithm Algorithm // 空白時為 sliding_window
+	Rate      Rate
+	Burst     int64 // 僅 token bucket 使用，0 表示等於 Rate.Limit
+}
+
+// New 依 policy 建立使用系統時間的 Limiter
+func New(store Store, policy Policy) (Limiter, error) {
+	if policy.Rate.Limit <= 0 || policy.Rate.Period <= 0 {
+		return nil, errors.New("rate limit and period must be positive")
+	}
+	if policy.Burst < 0 {
+		return nil, errors.New("rate limit burst must not be negative")
+	}
+
+	switch policy.Algorithm {
+	case AlgorithmTokenBucket:
+		return NewTokenBucket(store, policy.Rate, policy.Burst, SystemClock{}), nil
+	case "", AlgorithmSlidingWindow:
+		return NewSlidingWindow(store, policy.Rate, SystemClock{}), nil
+	case AlgorithmSlidingLog:
+		return NewSlidingLog(store, policy.Rate, SystemClock{}), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit algorithm %q", policy.Algorithm)
+	}
+}
+
+// Store 保存各 key 的限流狀態，每個操作必須是原子的
+// MemoryStore 僅適用單一實例；RedisStore 讓多個實例共用狀態，重新啟動也不會歸零
+// 同一個 key 只能用於一種演算法
+type Store interface {
+	// takeToken token bucket（以 GCRA 實作）：tat 為 bucket 補滿的時間
+	// 下一次請求使 tat 增加 interval，增加後距離 now 超過 burst 個 interval 時拒絕
+	// 允許時回傳增加後的 tat，拒絕時回傳目前的 tat
+	takeToken(ctx context.Context, key string, now time.Time, interval time.Duration, burst int64) (allowed bool, tat time.Time, err error)
+
+	// appendLog sliding log：移除 window 以前的紀錄，未達 limit 時記錄 now
+	// 回傳記錄後的筆數與最早、最晚一筆的時間
+	appendLog(ctx context.Context, key string, now time.Time, window time.Duration, limit int64) (allowed bool, count int64, oldest, newest time.Time, err error)
+
+	// incrementWindow sliding window counter：prev 以 prevWeight 加權後加上 curr 仍小於 limit 時將 curr 加一
+	// windowStart 為目前時間窗的開始時間，回傳前一個與目前時間窗（更新後）的計數
+	incrementWindow(ctx context.Context, key string, windowStart time.Time, period time.Duration, prevWeight float64, limit int64) (allowed bool, prev, curr int64, err error)
+}
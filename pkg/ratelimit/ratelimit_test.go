@@ -0,0 +1,316 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// testEpoch 對齊分鐘，讓 sliding window 的時間窗從測試開始
+var testEpoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// forEachStore 分別以記憶體與 miniredis 執行
+// 設定 REDIS_TEST_ADDR 時另外以真正的 Redis 執行：
+//
+//	REDIS_TEST_ADDR=localhost:6379 go test ./pkg/ratelimit
+func forEachStore(t *testing.T, run func(t *testing.T, store Store)) {
+	t.Run("Memory", func(t *testing.T) {
+		run(t, NewMemoryStore())
+	})
+	t.Run("Miniredis", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		run(t, newTestRedisStore(t, server.Addr()))
+	})
+	if addr := os.Getenv("REDIS_TEST_ADDR"); addr != "" {
+		t.Run("Redis", func(t *testing.T) {
+			run(t, newTestRedisStore(t, addr))
+		})
+	}
+}
+
+// newTestRedisStore 每個測試使用不同的前綴，不需要清空 Redis
+func newTestRedisStore(t testing.TB, addr string) *RedisStore {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisStore(client, "test:"+t.Name()+":"+strconv.FormatInt(time.Now().UnixNano(), 10))
+}
+
+// allowN 連續請求 n 次，回傳放行的次數與最後一次的結果
+func allowN(t *testing.T, limiter Limiter, key string, n int) (int, Result) {
+	t.Helper()
+
+	allowed := 0
+	var last Result
+	for i := 0; i < n; i++ {
+		result, err := limiter.Allow(context.Background(), key)
+		if err != nil {
+			t.Fatalf("Allow failed: %v", err)
+		}
+		if result.Allowed {
+			allowed++
+		}
+		last = result
+	}
+	return allowed, last
+}
+
+func TestTokenBucket_Burst(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		clock := NewManualClock(testEpoch)
+		limiter := NewTokenBucket(store, Rate{Limit: 10, Period: time.Second}, 50, clock)
+
+		first, _ := allowN(t, limiter, "k", 1)
+		result, _ := limiter.Allow(context.Background(), "k")
+		if first != 1 || result.Limit != 50 || result.Remaining != 48 {
+			t.Errorf("Expected limit 50 and 48 remaining, got %+v", result)
+		}
+		if !result.ResetAt.Equal(testEpoch.Add(200 * time.Millisecond)) {
+			t.Errorf("Expected bucket to refill at +200ms, got %v", result.ResetAt.Sub(testEpoch))
+		}
+
+		// 閒置時累積的 burst 用完後被拒絕
+		allowed, result := allowN(t, limiter, "k", 49)
+		if allowed != 48 || result.Allowed {
+			t.Fatalf("Expected 48 more requests within burst, got %d (%+v)", allowed, result)
+		}
+		if result.RetryAfter != 100*time.Millisecond {
+			t.Errorf("Expected RetryAfter 100ms, got %v", result.RetryAfter)
+		}
+
+		// 之後依速率補充
+		clock.Advance(100 * time.Millisecond)
+		if allowed, _ := allowN(t, limiter, "k", 2); allowed != 1 {
+			t.Errorf("Expected 1 request after 100ms, got %d", allowed)
+		}
+		clock.Advance(time.Second)
+		if allowed, _ := allowN(t, limiter, "k", 20); allowed != 10 {
+			t.Errorf("Expected 10 requests after 1s, got %d", allowed)
+		}
+
+		// 補滿後不超過 burst
+		clock.Advance(time.Minute)
+		if allowed, _ := allowN(t, limiter, "k", 60); allowed != 50 {
+			t.Errorf("Expected full burst of 50, got %d", allowed)
+		}
+	})
+}
+
+func TestSlidingWindow_NoBoundaryBurst(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		clock := NewManualClock(testEpoch.Add(59 * time.Second))
+		limiter := NewSlidingWindow(store, Rate{Limit: 10, Period: time.Minute}, clock)
+
+		if allowed, _ := allowN(t, limiter, "k", 10); allowed != 10 {
+			t.Fatalf("Expected 10 requests, got %d", allowed)
+		}
+
+		// 固定時間窗在此會重新計數；前一個時間窗仍佔 59/60
+		clock.Advance(2 * time.Second)
+		if allowed, _ := allowN(t, limiter, "k", 10); allowed != 0 {
+			t.Errorf("Expected no requests right after the boundary, got %d", allowed)
+		}
+
+		// 前一個時間窗剩一半的權重
+		clock.Set(testEpoch.Add(90 * time.Second))
+		if allowed, _ := allowN(t, limiter, "k", 10); allowed != 5 {
+			t.Errorf("Expected 5 requests at half window, got %d", allowed)
+		}
+	})
+}
+
+func TestSlidingWindow_RetryAfter(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		clock := NewManualClock(testEpoch)
+		limiter := NewSlidingWindow(store, Rate{Limit: 10, Period: time.Minute}, clock)
+
+		_, result := allowN(t, limiter, "k", 11)
+		if result.Allowed || result.Remaining != 0 {
+			t.Fatalf("Expected 11th request to be rejected, got %+v", result)
+		}
+		// 下一個時間窗經過 1/10 後，估算值降到 9
+		if result.RetryAfter != 66*time.Second {
+			t.Errorf("Expected RetryAfter 66s, got %v", result.RetryAfter)
+		}
+		if !result.ResetAt.Equal(testEpoch.Add(2 * time.Minute)) {
+			t.Errorf("Expected reset at +2m, got %v", result.ResetAt.Sub(testEpoch))
+		}
+
+		clock.Advance(result.RetryAfter - time.Second)
+		if allowed, _ := allowN(t, limiter, "k", 1); allowed != 0 {
+			t.Error("Expected request before RetryAfter to be rejected")
+		}
+		clock.Advance(time.Second)
+		if allowed, _ := allowN(t, limiter, "k", 1); allowed != 1 {
+			t.Error("Expected request after RetryAfter to be allowed")
+		}
+	})
+}
+
+func TestSlidingLog(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		clock := NewManualClock(testEpoch)
+		limiter := NewSlidingLog(store, Rate{Limit: 3, Period: time.Minute}, clock)
+
+		for i := 0; i < 3; i++ {
+			if allowed, _ := allowN(t, limiter, "k", 1); allowed != 1 {
+				t.Fatalf("Expected request %d to be allowed", i+1)
+			}
+			clock.Advance(10 * time.Second)
+		}
+
+		_, result := allowN(t, limiter, "k", 1)
+		if result.Allowed || result.RetryAfter != 30*time.Second {
+			t.Fatalf("Expected rejection with RetryAfter 30s, got %+v", result)
+		}
+		if !result.ResetAt.Equal(testEpoch.Add(80 * time.Second)) {
+			t.Errorf("Expected reset at +80s, got %v", result.ResetAt.Sub(testEpoch))
+		}
+
+		// 最早一筆滿一分鐘後移出
+		clock.Set(testEpoch.Add(time.Minute))
+		allowed, result := allowN(t, limiter, "k", 2)
+		if allowed != 1 || result.Allowed {
+			t.Errorf("Expected exactly 1 request after the oldest expired, got %d", allowed)
+		}
+	})
+}
+
+func TestLimiter_KeysAreIndependent(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		clock := NewManualClock(testEpoch)
+		rate := Rate{Limit: 2, Period: time.Minute}
+		limiters := map[Algorithm]Limiter{
+			AlgorithmTokenBucket:   NewTokenBucket(store, rate, 0, clock),
+			AlgorithmSlidingWindow: NewSlidingWindow(store, rate, clock),
+			AlgorithmSlidingLog:    NewSlidingLog(store, rate, clock),
+		}
+
+		for algorithm, limiter := range limiters {
+			for _, key := range []string{"a", "b"} {
+				key = string(algorithm) + ":" + key
+				if allowed, _ := allowN(t, limiter, key, 3); allowed != 2 {
+					t.Errorf("%s: expected 2 requests for %s, got %d", algorithm, key, allowed)
+				}
+			}
+		}
+	})
+}
+
+func TestRedisStore_SharedAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	clock := NewManualClock(testEpoch)
+	rate := Rate{Limit: 4, Period: time.Minute}
+
+	// 兩個實例各自建立連線與 store，使用相同的前綴
+	newStore := func() *RedisStore {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return NewRedisStore(client, "shared")
+	}
+	first, second := newStore(), newStore()
+
+	for _, algorithm := range []Algorithm{AlgorithmTokenBucket, AlgorithmSlidingWindow, AlgorithmSlidingLog} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			newLimiter := func(store Store) Limiter {
+				switch algorithm {
+				case AlgorithmTokenBucket:
+					return NewTokenBucket(store, rate, 0, clock)
+				case AlgorithmSlidingLog:
+					return NewSlidingLog(store, rate, clock)
+				default:
+					return NewSlidingWindow(store, rate, clock)
+				}
+			}
+			a, b := newLimiter(first), newLimiter(second)
+			key := string(algorithm)
+
+			allowedA, _ := allowN(t, a, key, 2)
+			allowedB, _ := allowN(t, b, key, 3)
+			if allowedA+allowedB != 4 {
+				t.Errorf("Expected 4 requests across instances, got %d", allowedA+allowedB)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	store := NewMemoryStore()
+	rate := Rate{Limit: 1, Period: time.Second}
+
+	for _, policy := range []Policy{
+		{Rate: rate},
+		{Algorithm: AlgorithmTokenBucket, Rate: rate, Burst: 5},
+		{Algorithm: AlgorithmSlidingLog, Rate: rate},
+	} {
+		if _, err := New(store, policy); err != nil {
+			t.Errorf("Expected policy %+v to be valid, got %v", policy, err)
+		}
+	}
+
+	for _, policy := range []Policy{
+		{Rate: Rate{Limit: 0, Period: time.Second}},
+		{Rate: Rate{Limit: 1}},
+		{Algorithm: AlgorithmTokenBucket, Rate: rate, Burst: -1},
+		{Algorithm: "fixed_window", Rate: rate},
+	} {
+		if _, err := New(store, policy); err == nil {
+			t.Errorf("Expected policy %+v to be rejected", policy)
+		}
+	}
+}
+
+// BenchmarkLimiters 以 100 個 key 平行請求
+//
+//	go test ./pkg/ratelimit -run '^$' -bench .
+func BenchmarkLimiters(b *testing.B) {
+	server := miniredis.RunT(b)
+	stores := []struct {
+		name  string
+		store Store
+	}{
+		{name: "Memory", store: NewMemoryStore()},
+		{name: "Miniredis", store: newTestRedisStore(b, server.Addr())},
+	}
+	policies := []Policy{
+		{Algorithm: AlgorithmTokenBucket, Rate: Rate{Limit: 1000, Period: time.Second}, Burst: 5000},
+		{Algorithm: AlgorithmSlidingWindow, Rate: Rate{Limit: 1000, Period: time.Second}},
+		{Algorithm: AlgorithmSlidingLog, Rate: Rate{Limit: 1000, Period: time.Second}},
+	}
+
+	for _, s := range stores {
+		for _, policy := range policies {
+			b.Run(s.name+"/"+string(policy.Algorithm), func(b *testing.B) {
+				limiter, err := New(s.store, policy)
+				if err != nil {
+					b.Fatalf("New failed: %v", err)
+				}
+				ctx := context.Background()
+
+				// 同一個 key 只能用於一種演算法
+				keys := make([]string, 100)
+				for i := range keys {
+					keys[i] = string(policy.Algorithm) + ":user:" + strconv.Itoa(i)
+				}
+
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					i := 0
+					for pb.Next() {
+						if _, err := limiter.Allow(ctx, keys[i%len(keys)]); err != nil {
+							b.Errorf("Allow failed: %v", err)
+							return
+						}
+						i++
+					}
+				})
+			})
+		}
+	}
+}
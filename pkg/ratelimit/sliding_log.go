@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// slidingLog 記錄每次放行的時間，過去 Period 內最多 Limit 筆
+type slidingLog struct {
+	store  Store
+	limit  int64
+	window time.Duration
+	clock  Clock
+}
+
+// NewSlidingLog 建立 sliding log，適合次數少、需要精確計數的限制（例如寄信次數）
+func NewSlidingLog(store Store, rate Rate, clock Clock) Limiter {
+	window := rate.Period.Truncate(time.Microsecond)
+	if window <= 0 {
+		window = time.Microsecond
+	}
+	return &slidingLog{store: store, limit: rate.Limit, window: window, clock: clock}
+}
+
+func (l *slidingLog) Allow(ctx context.Context, key string) (Result, error) {
+	now := l.clock.Now().Truncate(time.Microsecond)
+	allowed, count, oldest, newest, err := l.store.appendLog(ctx, key, now, l.window, l.limit)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Allowed: allowed, Limit: l.limit, Remaining: l.limit - count, ResetAt: now}
+	if count > 0 {
+		result.ResetAt = newest.Add(l.window)
+	}
+	if !allowed {
+		// 最早一筆移出時間窗後即可再放行
+		result.RetryAfter = oldest.Add(l.window).Sub(now)
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// slidingWindow 以固定時間窗計數，並依前一個時間窗與目前時間的重疊比例估算過去 Period 內的請求數
+type slidingWindow struct {
+	store  Store
+	limit  int64
+	period time.Duration
+	clock  Clock
+}
+
+// NewSlidingWindow 建立 sliding window counter，過去 rate.Period 內（估算）最多 rate.Limit 次
+func NewSlidingWindow(store Store, rate Rate, clock Clock) Limiter {
+	period := rate.Period.Truncate(time.Microsecond)
+	if period <= 0 {
+		period = time.Microsecond
+	}
+	return &slidingWindow{store: store, limit: rate.Limit, period: period, clock: clock}
+}
+
+func (w *slidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	now := w.clock.Now().Truncate(time.Microsecond)
+	windowStart := now.Truncate(w.period)
+	// 前一個時間窗仍在過去 Period 內的比例
+	prevWeight := 1 - float64(now.Sub(windowStart))/float64(w.period)
+
+	allowed, prev, curr, err := w.store.incrementWindow(ctx, key, windowStart, w.period, prevWeight, w.limit)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Allowed: allowed, Limit: w.limit}
+	estimated := float64(prev)*prevWeight + float64(curr)
+	if remaining := int64(math.Floor(float64(w.limit) - estimated)); remaining > 0 {
+		result.Remaining = remaining
+	}
+
+	// 目前時間窗的計數在下一個時間窗結束時完全移出
+	switch {
+	case curr > 0:
+		result.ResetAt = windowStart.Add(2 * w.period)
+	case prev > 0:
+		result.ResetAt = windowStart.Add(w.period)
+	default:
+		result.ResetAt = now
+	}
+
+	if !allowed {
+		result.RetryAfter = w.retryAfter(now, windowStart, prev, curr)
+	}
+	return result, nil
+}
+
+// retryAfter 沒有新請求的情況下，估算值降到可再放行一次所需的時間
+func (w *slidingWindow) retryAfter(now, windowStart time.Time, prev, curr int64) time.Duration {
+	// 目前時間窗內：prev 的權重隨時間遞減
+	if prev > 0 && curr+1 <= w.limit {
+		elapsed := 1 - float64(w.limit-curr-1)/float64(prev)
+		at := windowStart.Add(time.Duration(math.Ceil(elapsed * float64(w.period))))
+		if at.Before(windowStart.Add(w.period)) {
+			return at.Sub(now)
+		}
+	}
+
+	// 下一個時間窗：目前的計數成為 prev，權重隨時間遞減
+	next := windowStart.Add(w.period)
+	elapsed := 0.0
+	if curr > 0 {
+		elapsed = math.Max(0, 1-float64(w.limit-1)/float64(curr))
+	}
+	return next.Add(time.Duration(math.Ceil(elapsed * float64(w.period)))).Sub(now)
+}
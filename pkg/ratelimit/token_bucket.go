@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// tokenBucket 每 Rate.Period / Rate.Limit 補充一個 token，最多累積 burst 個
+type tokenBucket struct {
+	store    Store
+	interval time.Duration // 補充一個 token 的時間
+	burst    int64
+	clock    Clock
+}
+
+// NewTokenBucket 建立 token bucket，burst 為 0 時等於 rate.Limit
+// 例如 Rate{Limit: 10, Period: time.Second} 與 burst 50：閒置後可連續 50 次，之後每秒 10 次
+func NewTokenBucket(store Store, rate Rate, burst int64, clock Clock) Limiter {
+	if burst <= 0 {
+		burst = rate.Limit
+	}
+	interval := (rate.Period / time.Duration(rate.Limit)).Truncate(time.Microsecond)
+	if interval <= 0 {
+		interval = time.Microsecond
+	}
+	return &tokenBucket{store: store, interval: interval, burst: burst, clock: clock}
+}
+
+func (b *tokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	// 狀態以微秒為單位存放，統一捨去以免記憶體與 Redis 的結果不同
+	now := b.clock.Now().Truncate(time.Microsecond)
+	allowed, tat, err := b.store.takeToken(ctx, key, now, b.interval, b.burst)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Allowed: allowed, Limit: b.burst, ResetAt: tat}
+	if allowed {
+		// tat 距離 now 越遠，已使用的 token 越多
+		result.Remaining = int64(now.Add(time.Duration(b.burst)*b.interval).Sub(tat) / b.interval)
+	} else {
+		result.RetryAfter = tat.Add(b.interval - time.Duration(b.burst)*b.interval).Sub(now)
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// get 以 LocalStore 的 ServeHTTP 取得簽章網址的內容
+func get(t *testing.T, store *LocalStore, signedURL string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("Invalid signed URL %q: %v", signedURL, err)
+	}
+	req := httptest.NewRequest(http.MethodGet, u.String(), nil)
+	req.URL.Path = strings.TrimPrefix(u.Path, "/media")
+	rec := httptest.NewRecorder()
+	store.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLocalStore_SignedURL(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "http://localhost:8080/media/", "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStore failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "avatars/1/v1/64.jpg", strings.NewReader("image-bytes"), 11, "image/jpeg"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	signedURL, err := store.SignedURL(ctx, "avatars/1/v1/64.jpg", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL failed: %v", err)
+	}
+	if !strings.HasPrefix(signedURL, "http://localhost:8080/media/avatars/1/v1/64.jpg?") {
+		t.Errorf("Unexpected signed URL %q", signedURL)
+	}
+
+	if rec := get(t, store, signedURL); rec.Code != http.StatusOK || rec.Body.String() != "image-bytes" {
+		t.Fatalf("Expected file to be served, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	t.Run("TamperedKey", func(t *testing.T) {
+		tampered := strings.Replace(signedURL, "/64.jpg", "/128.jpg", 1)
+		if rec := get(t, store, tampered); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		defer func() { store.now = time.Now }()
+		if rec := get(t, store, signedURL); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		if err := store.Delete(ctx, "avatars/1/v1/64.jpg"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if rec := get(t, store, signedURL); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", rec.Code)
+		}
+		// 刪除不存在的檔案不是錯誤
+		if err := store.Delete(ctx, "avatars/1/v1/64.jpg"); err != nil {
+			t.Errorf("Expected no error deleting a missing file, got %v", err)
+		}
+	})
+}
+
+func TestLocalStore_RejectsInvalidKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "http://localhost:8080/media", "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStore failed: %v", err)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "avatars/../../secret", "avatars//1.jpg"} {
+		t.Run(key, func(t *testing.T) {
+			if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+				t.Errorf("Expected key %q to be rejected", key)
+			}
+		})
+	}
+}
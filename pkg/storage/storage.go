@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// BlobStore 存放上傳檔案（例如頭像）的介面
+// key 為以 / 分隔的相對路徑，例如 avatars/1/abc/128.jpg
+type BlobStore interface {
+	// Put 寫入檔案，已存在時覆寫
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Delete 刪除檔案，檔案不存在時不回傳錯誤
+	Delete(ctx context.Context, key string) error
+	// SignedURL 產生在 ttl 內有效的下載網址
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// validateKey 拒絕空白、絕對路徑與 .. 等可能跳出儲存目錄的 key
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
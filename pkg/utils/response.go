@@ -1,6 +1,9 @@
 package utils
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/dinosaur1258/GolangFramework/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
 
 // Response 統一響應結構
 type Response struct {
@@ -12,9 +15,10 @@ type Response struct {
 
 // ErrorDetail 錯誤詳情
 type ErrorDetail struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code    string              `json:"code"`
+	Message string              `json:"message"`
+	Details string              `json:"details,omitempty"`
+	Fields  []errors.FieldError `json:"fields,omitempty"`
 }
 
 // SuccessResponse 成功響應
@@ -42,3 +46,15 @@ func ErrorResponse(c *gin.Context, statusCode int, code, message string, details
 		Error:   errorDetail,
 	})
 }
+
+// FieldErrorResponse 帶有欄位明細的錯誤響應
+func FieldErrorResponse(c *gin.Context, statusCode int, code, message string, fields []errors.FieldError) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Error: &ErrorDetail{
+			Code:    code,
+			Message: message,
+			Fields:  fields,
+		},
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/dinosaur1258/GolangFramework/pkg/errors"
 	"github.com/gin-gonic/gin"
 )
 
@@ -107,3 +108,29 @@ func TestErrorResponseWithoutDetails(t *testing.T) {
 		t.Errorf("Expected empty details, got '%s'", response.Error.Details)
 	}
 }
+
+// TestFieldErrorResponse 測試帶有欄位明細的錯誤響應
+func TestFieldErrorResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	FieldErrorResponse(c, http.StatusBadRequest, "PASSWORD_POLICY_VIOLATION", "Invalid password", []errors.FieldError{
+		{Field: "password", Code: "too_short", Message: "Password is too short"},
+	})
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var response Response
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if response.Error == nil || len(response.Error.Fields) != 1 {
+		t.Fatalf("Expected one field error, got %+v", response.Error)
+	}
+	if field := response.Error.Fields[0]; field.Field != "password" || field.Code != "too_short" {
+		t.Errorf("Unexpected field error: %+v", field)
+	}
+}